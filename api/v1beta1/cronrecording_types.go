@@ -0,0 +1,159 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CronRecordingSpec defines the desired state of CronRecording
+type CronRecordingSpec struct {
+	// The schedule on which to create Recordings, in Cron format.
+	// For example, "0 2 * * *" creates a Recording every night at 02:00 UTC.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Schedule string `json:"schedule"`
+	// Template for the Recordings created on each scheduled run. The name of each
+	// JFR recording is suffixed with the scheduled time to keep it unique.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	RecordingTemplate RecordingTemplateSpec `json:"recordingTemplate"`
+	// How to treat a scheduled run while Recordings from previous runs are still active:
+	// "Allow" creates the new Recording anyway, "Forbid" skips the new run, and
+	// "Replace" deletes the active Recordings before creating the new one.
+	// If omitted, Allow will be assumed.
+	// +optional
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Allow","urn:alm:descriptor:com.tectonic.ui:select:Forbid","urn:alm:descriptor:com.tectonic.ui:select:Replace"}
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// The number of successfully completed Recordings to keep. Older Recordings are
+	// deleted along with their archived files. If omitted, 3 will be assumed.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	SuccessfulRecordingsHistoryLimit *int32 `json:"successfulRecordingsHistoryLimit,omitempty"`
+	// The number of failed Recordings to keep. Older Recordings are deleted.
+	// If omitted, 1 will be assumed.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	FailedRecordingsHistoryLimit *int32 `json:"failedRecordingsHistoryLimit,omitempty"`
+}
+
+// RecordingTemplateSpec describes the Recordings created from a CronRecording or RecordingTrigger
+type RecordingTemplateSpec struct {
+	// Labels and annotations to apply to each created Recording.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of each created Recording.
+	Spec RecordingSpec `json:"spec"`
+}
+
+// ConcurrencyPolicy describes how a CronRecording handles overlapping runs
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows Recordings from different runs to be active at once.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips a run if a Recording from a previous run is still active.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent deletes active Recordings from previous runs before
+	// starting a new one.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// CronRecordingStatus defines the observed state of CronRecording
+type CronRecordingStatus struct {
+	// Recordings created by this CronRecording that have not yet completed.
+	// +optional
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Active []corev1.ObjectReference `json:"active,omitempty"`
+	// The last time a Recording was scheduled.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// The last time a Recording created by this CronRecording completed successfully.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// Conditions of the CronRecording, such as whether its schedule is valid.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Condition types for CronRecording
+const (
+	// CronRecordingConditionScheduleValid indicates whether the schedule
+	// could be parsed. No Recordings are created while it is False.
+	CronRecordingConditionScheduleValid string = "ScheduleValid"
+)
+
+// CronRecordingLabel is applied to each Recording created by a CronRecording,
+// and contains the name of that CronRecording. Names longer than 63 characters are
+// truncated, and a hash of the full name is appended.
+const CronRecordingLabel = "operator.cryostat.io/cronrecording"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=cronrecordings,scope=Namespaced
+
+// CronRecording is the Schema for the cronrecordings API
+//+operator-sdk:csv:customresourcedefinitions:resources={{Recording,v1beta1}}
+type CronRecording struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CronRecordingSpec   `json:"spec,omitempty"`
+	Status CronRecordingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CronRecordingList contains a list of CronRecording
+type CronRecordingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CronRecording `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CronRecording{}, &CronRecordingList{})
+}
@@ -39,6 +39,7 @@ package v1beta1
 import (
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -68,14 +69,55 @@ type CryostatSpec struct {
 	// Options to control how the operator exposes the application over a network
 	// +optional
 	NetworkOptions *NetworkConfigurationList `json:"networkOptions,omitempty"`
+	// How long the operator keeps trying to clean up the recordings and JFR files in
+	// Cryostat belonging to a Recording being deleted, or the event templates uploaded
+	// for a FlightRecorder being deleted, such as "15m". Once elapsed, the
+	// operator stops trying, emits a Warning Event describing what may have been left
+	// behind, and lets the deletion proceed. If omitted, the operator keeps trying
+	// until the cleanup succeeds.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RecordingFinalizerTimeout *metav1.Duration `json:"recordingFinalizerTimeout,omitempty"`
 }
 
 // CryostatStatus defines the observed state of Cryostat
 type CryostatStatus struct {
+	// Address of the deployed Cryostat web application.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:org.w3:link"}
 	ApplicationURL string `json:"applicationUrl"`
+	// Address of the deployed Grafana dashboard. Empty for minimal deployments.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:org.w3:link"}
+	GrafanaURL string `json:"grafanaUrl,omitempty"`
+	// Address of the WebSocket command channel of the deployed Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:org.w3:link"}
+	CommandURL string `json:"commandUrl,omitempty"`
+	// Conditions of the components managed by the Cryostat Operator,
+	// describing why an installation may not yet be available.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// Condition types for Cryostat
+const (
+	// CryostatConditionTLSReady indicates whether the certificates used to
+	// secure communication between Cryostat components have been issued.
+	CryostatConditionTLSReady string = "TLSReady"
+	// CryostatConditionIngressReady indicates whether the Routes or Ingresses
+	// exposing Cryostat's services outside of the cluster are available.
+	CryostatConditionIngressReady string = "IngressReady"
+	// CryostatConditionStorageBound indicates whether the Persistent Volume
+	// Claim used to store Flight Recordings and Templates has been bound.
+	CryostatConditionStorageBound string = "StorageBound"
+	// CryostatConditionDeploymentAvailable indicates whether the Cryostat
+	// Deployment has its minimum number of replicas available.
+	CryostatConditionDeploymentAvailable string = "DeploymentAvailable"
+)
+
 // StorageConfiguration provides customization to the storage created by
 // the operator to hold Flight Recordings and Recording Templates.
 type StorageConfiguration struct {
@@ -84,6 +126,42 @@ type StorageConfiguration struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	PVC *PersistentVolumeClaimConfig `json:"pvc,omitempty"`
+	// Policy limiting the archived Flight Recordings kept in storage.
+	// Once any limit is exceeded, the oldest archived recordings are deleted first.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Retention *ArchiveRetentionPolicy `json:"retention,omitempty"`
+	// The deletion policy used for Recordings in this namespace that do not specify
+	// their own. With "Retain", archived Flight Recordings are kept in storage after
+	// their Recording is deleted. If omitted, defaults to "Delete".
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Delete","urn:alm:descriptor:com.tectonic.ui:select:Retain"}
+	RecordingDeletionPolicy RecordingDeletionPolicy `json:"recordingDeletionPolicy,omitempty"`
+}
+
+// ArchiveRetentionPolicy limits the archived Flight Recordings kept in
+// Cryostat's storage. Archived recordings belonging to a Recording with
+// the "operator.cryostat.io/retain" annotation set to "true" are never deleted,
+// but still count towards these limits.
+type ArchiveRetentionPolicy struct {
+	// The maximum number of archived recordings to keep.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	MaxFiles *int32 `json:"maxFiles,omitempty"`
+	// The maximum total size of archived recordings to keep, such as "400Mi".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MaxTotalSize *resource.Quantity `json:"maxTotalSize,omitempty"`
+	// The maximum age of archived recordings to keep, such as "168h".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// How often the operator enforces this policy. If omitted, defaults to one hour.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Interval *metav1.Duration `json:"interval,omitempty"`
 }
 
 // NetworkConfiguration provides customization for the corresponding ingress,
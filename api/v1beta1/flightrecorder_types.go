@@ -38,6 +38,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -51,14 +52,30 @@ type FlightRecorderSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	JMXCredentials *JMXAuthSecret `json:"jmxCredentials,omitempty"`
+	// Custom event templates to upload to Cryostat for this FlightRecorder's JVM.
+	// Each template is read from a ConfigMap in the same namespace, and is replaced
+	// in Cryostat whenever the ConfigMap changes. FlightRecorders are recreated along
+	// with their pods, so templates that should outlive the pod are better listed in
+	// the pod's EventTemplatesAnnotation.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +listType=atomic
+	EventTemplates []TemplateConfigMap `json:"eventTemplates,omitempty"`
 }
 
 // FlightRecorderStatus defines the observed state of FlightRecorder
 type FlightRecorderStatus struct {
-	// Listing of events available in the target JVM
+	// Listing of events available in the target JVM. Empty if the operator stores
+	// the events in a shared ConfigMap instead, as referenced by EventCatalog.
+	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +listType=atomic
-	Events []EventInfo `json:"events"`
+	Events []EventInfo `json:"events,omitempty"`
+	// Reference to a ConfigMap containing the events available in the target JVM,
+	// if the operator stores them there instead of in Events
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	EventCatalog *EventCatalogReference `json:"eventCatalog,omitempty"`
 	// Listing of templates available in the target JVM
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +listType=atomic
@@ -70,11 +87,155 @@ type FlightRecorderStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
 	// +kubebuilder:validation:Minimum=0
 	Port int32 `json:"port"`
+	// Listing of recordings in the target JVM, including those started outside
+	// of the operator, such as from the Cryostat web console
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +listType=atomic
+	Recordings []JVMRecordingInfo `json:"recordings,omitempty"`
+	// The date/time when this status was last refreshed from the target JVM
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+	// The date/time when recordings left behind by deleted Recordings were last
+	// removed from the target JVM
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	LastOrphanSweepTime *metav1.Time `json:"lastOrphanSweepTime,omitempty"`
+	// Identifies the instance of the target JVM that this status was read from
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	JVMIdentity *JVMIdentity `json:"jvmIdentity,omitempty"`
+	// Details of the target JVM's runtime, such as its version and memory usage
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	JVM *JVMInfo `json:"jvm,omitempty"`
+	// Conditions of the FlightRecorder, such as whether Cryostat can connect
+	// to the target JVM, along with the reason for each.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Custom event templates that the operator has uploaded to Cryostat
+	// from the ConfigMaps listed in the spec
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +listType=atomic
+	UploadedTemplates []UploadedTemplate `json:"uploadedTemplates,omitempty"`
+}
+
+// EventCatalogReference refers to a ConfigMap containing the events available
+// in a target JVM. The ConfigMap is named after a hash of its contents, so that
+// it is shared by all FlightRecorders whose JVMs have the same events.
+type EventCatalogReference struct {
+	// Name of the ConfigMap in the FlightRecorder's namespace
+	ConfigMapName string `json:"configMapName"`
+	// Number of events in the catalog
+	EventCount int32 `json:"eventCount"`
+}
+
+// EventCatalogLabel is applied to ConfigMaps created by the operator to hold
+// event catalogs
+const EventCatalogLabel = "operator.cryostat.io/event-catalog"
+
+// EventCatalogKey is the key within an event catalog ConfigMap containing the
+// events, as a JSON list in the same format as FlightRecorderStatus.Events
+const EventCatalogKey = "events.json"
+
+// UploadedTemplate describes a custom event template uploaded to Cryostat
+// from a ConfigMap
+type UploadedTemplate struct {
+	// Name of the template in Cryostat, taken from the label of the template file
+	Name string `json:"name"`
+	// Name of the ConfigMap the template was read from
+	ConfigMapName string `json:"configMapName"`
+	// Filename within the ConfigMap containing the template file
+	Filename string `json:"filename"`
+	// Hash of the uploaded template file, used to detect changes to the ConfigMap
+	Hash string `json:"hash"`
+}
+
+// JVMInfo contains details of a target JVM's runtime, as of the last refresh
+type JVMInfo struct {
+	// Vendor of the JVM implementation
+	// +optional
+	Vendor string `json:"vendor,omitempty"`
+	// Version of the JVM implementation
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Process ID of the JVM, if known
+	// +optional
+	PID *int64 `json:"pid,omitempty"`
+	// How long the JVM had been running
+	Uptime metav1.Duration `json:"uptime"`
+	// Usage of the JVM's heap
+	HeapUsage JVMMemoryUsage `json:"heapUsage"`
+	// Usage of the JVM's memory outside of the heap
+	NonHeapUsage JVMMemoryUsage `json:"nonHeapUsage"`
+	// Whether JDK Flight Recorder is available in the JVM, meaning that
+	// it reported at least one event type that can be recorded
+	JFRAvailable bool `json:"jfrAvailable"`
+}
+
+// JVMMemoryUsage contains the usage of a pool of memory in a JVM
+type JVMMemoryUsage struct {
+	// Memory used
+	Used resource.Quantity `json:"used"`
+	// Memory guaranteed to be available to the JVM
+	Committed resource.Quantity `json:"committed"`
+	// The maximum memory that can be used, if defined
+	// +optional
+	Max *resource.Quantity `json:"max,omitempty"`
+}
+
+// Condition types for FlightRecorder
+const (
+	// FlightRecorderConditionConnected indicates whether Cryostat could connect
+	// to the target JVM when the status was last refreshed. When false, the reason
+	// is one of "AuthenticationFailed", "TLSUntrusted" or "Unreachable".
+	FlightRecorderConditionConnected string = "Connected"
+)
+
+// JVMIdentity identifies an instance of a target JVM. A change in any field means
+// the JVM may have been restarted or replaced since the status was last refreshed.
+type JVMIdentity struct {
+	// IP address of the target pod
+	PodIP string `json:"podIP"`
+	// Total number of times the containers of the target pod have restarted
+	RestartCount int32 `json:"restartCount"`
 }
 
 // RecordingLabel is the label name to be used with FlightRecorderSpec.RecordingSelector
 const RecordingLabel = "operator.cryostat.io/flightrecorder"
 
+// AdoptRecordingsAnnotation, when set to "true" on a FlightRecorder, causes the
+// operator to create a Recording for each recording in the target JVM that is
+// not already managed by one
+const AdoptRecordingsAnnotation = "operator.cryostat.io/adopt-recordings"
+
+// EventTemplatesAnnotation, when set on a target pod, lists custom event templates
+// to upload for its FlightRecorder in addition to FlightRecorderSpec.EventTemplates,
+// as comma-separated "<ConfigMap name>/<filename>" pairs. Set in the pod template
+// of a workload, it applies to every FlightRecorder created for its pods.
+const EventTemplatesAnnotation = "operator.cryostat.io/event-templates"
+
+// JVMRecordingInfo contains metadata for a recording found in the target JVM
+type JVMRecordingInfo struct {
+	// Name of the recording in the target JVM
+	Name string `json:"name"`
+	// State of the recording within its lifecycle
+	// +kubebuilder:validation:Enum=CREATED;RUNNING;STOPPING;STOPPED
+	State RecordingState `json:"state"`
+	// The date/time when the recording was started
+	StartTime metav1.Time `json:"startTime"`
+	// The configured duration of the recording, a zero value means it records indefinitely
+	Duration metav1.Duration `json:"duration"`
+	// Name of the Recording object that manages this recording, if any
+	// +optional
+	Recording string `json:"recording,omitempty"`
+}
+
 // EventInfo contains metadata for a JFR event type
 type EventInfo struct {
 	// The ID used by JFR to uniquely identify this event type
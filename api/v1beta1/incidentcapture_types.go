@@ -0,0 +1,172 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IncidentCaptureSpec defines the desired state of IncidentCapture
+type IncidentCaptureSpec struct {
+	// Selects the pods to capture JFR data from. Each selected pod is expected to have a
+	// FlightRecorder. Pods are selected once, when the capture begins.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Selector metav1.LabelSelector `json:"selector"`
+	// How long to record on each pod, such as "30s". If zero or omitted, a snapshot of
+	// the data already collected by the recordings running in each JVM is taken at once.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Duration metav1.Duration `json:"duration,omitempty"`
+	// The event template used to record on each pod. Required when Duration is set,
+	// and must be omitted otherwise.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Template *TemplateReference `json:"template,omitempty"`
+}
+
+// IncidentCaptureStatus defines the observed state of IncidentCapture
+type IncidentCaptureStatus struct {
+	// The date/time when the capture began.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// The date/time when every target finished being captured.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// The progress of the capture on each selected pod, including the archived JFR
+	// file or the reason for a failure.
+	// +optional
+	// +listType=map
+	// +listMapKey=pod
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Targets []IncidentCaptureTarget `json:"targets,omitempty"`
+	// Conditions of the IncidentCapture, such as whether it has completed, or
+	// failed on any of its targets.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The most recent generation of the IncidentCapture observed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// IncidentCaptureTarget describes the capture from one of the selected pods
+type IncidentCaptureTarget struct {
+	// Name of the target pod
+	Pod string `json:"pod"`
+	// Name of the FlightRecorder corresponding to the target pod
+	// +optional
+	FlightRecorder string `json:"flightRecorder,omitempty"`
+	// Progress of the capture from this pod
+	// +kubebuilder:validation:Enum=Pending;Recording;Archived;Failed
+	State IncidentCaptureTargetState `json:"state"`
+	// Name of the recording created in the target JVM
+	// +optional
+	RecordingName string `json:"recordingName,omitempty"`
+	// Name of the JFR file archived from this pod
+	// +optional
+	ArchivedFile string `json:"archivedFile,omitempty"`
+	// A URL to download the archived JFR file
+	// +optional
+	DownloadURL string `json:"downloadURL,omitempty"`
+	// A URL to download the autogenerated HTML report for the archived JFR file
+	// +optional
+	ReportURL string `json:"reportURL,omitempty"`
+	// Describes why the capture from this pod failed, or what it is waiting for
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// IncidentCaptureTargetState describes the progress of a capture from one pod
+type IncidentCaptureTargetState string
+
+const (
+	// IncidentCaptureTargetPending means nothing has been captured from the pod yet
+	IncidentCaptureTargetPending IncidentCaptureTargetState = "Pending"
+	// IncidentCaptureTargetRecording means a recording is in progress on the pod
+	IncidentCaptureTargetRecording IncidentCaptureTargetState = "Recording"
+	// IncidentCaptureTargetArchived means the data captured from the pod has been
+	// saved to persistent storage
+	IncidentCaptureTargetArchived IncidentCaptureTargetState = "Archived"
+	// IncidentCaptureTargetFailed means data could not be captured from the pod
+	IncidentCaptureTargetFailed IncidentCaptureTargetState = "Failed"
+)
+
+// IncidentCaptureLabel is attached to each recording an IncidentCapture creates in
+// a target JVM, and contains the name of that IncidentCapture
+const IncidentCaptureLabel = "operator.cryostat.io/incidentcapture"
+
+// Condition types for IncidentCapture
+const (
+	// IncidentCaptureConditionComplete indicates whether every target has
+	// either been archived or has failed.
+	IncidentCaptureConditionComplete string = "Complete"
+	// IncidentCaptureConditionFailed indicates whether the capture failed
+	// on any of its targets.
+	IncidentCaptureConditionFailed string = "Failed"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=incidentcaptures,scope=Namespaced
+
+// IncidentCapture is the Schema for the incidentcaptures API
+//+operator-sdk:csv:customresourcedefinitions:resources={{FlightRecorder,v1beta1}}
+type IncidentCapture struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IncidentCaptureSpec   `json:"spec,omitempty"`
+	Status IncidentCaptureStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IncidentCaptureList contains a list of IncidentCapture
+type IncidentCaptureList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IncidentCapture `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IncidentCapture{}, &IncidentCaptureList{})
+}
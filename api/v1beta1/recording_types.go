@@ -38,6 +38,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -46,17 +47,46 @@ type RecordingSpec struct {
 	// Name of the recording to be created.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	Name string `json:"name"`
-	// TODO Maybe replace with more specific type (e.g. "typeID, option, value" tuples)
-
 	// A list of event options to use when creating the recording.
 	// These are used to enable and fine-tune individual events.
 	// Examples: "jdk.ExecutionSample:enabled=true", "jdk.ExecutionSample:period=200ms"
+	// Cannot be used together with Template.
+	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	// +listType=atomic
-	EventOptions []string `json:"eventOptions"`
+	EventOptions []string `json:"eventOptions,omitempty"`
+	// A list of event options to use when creating the recording, in addition to
+	// any in EventOptions. Each option is checked against the events listed in the
+	// referenced FlightRecorder's status before the recording is created.
+	// Cannot be used together with Template.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +listType=atomic
+	Events []EventOption `json:"events,omitempty"`
+	// An event template to use when creating the recording, such as "Continuous"
+	// or "Profiling". The template must be listed in the referenced FlightRecorder's
+	// status. Cannot be used together with EventOptions.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Template *TemplateReference `json:"template,omitempty"`
 	// The requested total duration of the recording, a zero value will record indefinitely.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
 	Duration metav1.Duration `json:"duration"`
+	// The maximum size of the recording, such as "512Mi". Once reached, the oldest
+	// events are discarded. If omitted, the JVM's default is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// The maximum age of events kept in the recording. Older events are discarded.
+	// If omitted, the JVM's default is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// Whether the JVM should buffer the recording on disk, rather than only in memory.
+	// If omitted, the JVM's default is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ToDisk *bool `json:"toDisk,omitempty"`
 	// Desired state of the recording. If omitted, RUNNING will be assumed.
 	// +kubebuilder:validation:Enum=RUNNING;STOPPED
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:RUNNING","urn:alm:descriptor:com.tectonic.ui:select:STOPPED"}
@@ -67,9 +97,132 @@ type RecordingSpec struct {
 	// this object is deleted. If false, the JFR file will be deleted when its corresponding JVM exits.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:checkbox"}
 	Archive bool `json:"archive"`
-	// Reference to the FlightRecorder object that corresponds to this Recording
+	// While the recording is running, how often to save a copy of it to persistent
+	// storage, such as "1h". Only applies when Archive is true. Each copy is listed
+	// in the ArchivedSnapshots of the status.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ArchiveInterval *metav1.Duration `json:"archiveInterval,omitempty"`
+	// The time to start the recording, such as "2021-05-01T22:00:00Z". Until then, the
+	// recording is shown as SCHEDULED in the status. If omitted, the recording is
+	// started as soon as possible.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	StartAt *metav1.Time `json:"startAt,omitempty"`
+	// The time to stop the recording if it is still running, such as "2021-05-01T23:00:00Z".
+	// Must be later than StartAt. The recording is not started if this time has
+	// already passed.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	StopAt *metav1.Time `json:"stopAt,omitempty"`
+	// Reference to the FlightRecorder object that corresponds to this Recording.
+	// Cannot be used together with Workload or Selector.
+	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	FlightRecorder *corev1.LocalObjectReference `json:"flightRecorder"`
+	FlightRecorder *corev1.LocalObjectReference `json:"flightRecorder,omitempty"`
+	// A Deployment or StatefulSet whose pods should all run this recording. The recording
+	// is started on every current pod, and on new pods as they appear, such as during a
+	// rollout. Cannot be used together with FlightRecorder or Selector.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Workload *WorkloadReference `json:"workload,omitempty"`
+	// A label selector for pods that should all run this recording. The recording is
+	// started on every matching pod, and on new pods as they appear.
+	// Cannot be used together with FlightRecorder or Workload.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// The number of seconds to keep this Recording after it has stopped, and has been
+	// archived if requested. Once elapsed, the Recording is deleted along with its
+	// JFR files. If omitted, the Recording is kept until deleted manually.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// What to do when EventOptions, Events, Template, Duration, MaxSize, MaxAge or ToDisk
+	// are changed after the recording has started, since these cannot be applied to an
+	// existing recording. With "Never", the default, such changes are rejected and the
+	// existing recording is kept. With "OnSpecChange", the recording is stopped, archived
+	// if requested, and created again from the new spec. Changes to other fields, such as
+	// State, Archive and StopAt, are always applied to the existing recording.
+	// +optional
+	// +kubebuilder:validation:Enum=Never;OnSpecChange
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Never","urn:alm:descriptor:com.tectonic.ui:select:OnSpecChange"}
+	RestartPolicy RecordingRestartPolicy `json:"restartPolicy,omitempty"`
+	// What to do with the JFR files archived for this recording when the Recording is
+	// deleted. With "Delete", they are deleted from Cryostat along with the Recording.
+	// With "Retain", they are kept and labelled in Cryostat with the name and namespace
+	// of the deleted Recording. If omitted, the default from the Cryostat's storage
+	// options is used, or "Delete" if that is not set either.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Delete","urn:alm:descriptor:com.tectonic.ui:select:Retain"}
+	DeletionPolicy RecordingDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// RecordingRestartPolicy describes how changes to a Recording's spec that cannot
+// be applied to an existing recording are handled
+type RecordingRestartPolicy string
+
+const (
+	// RecordingRestartPolicyNever means such changes are rejected
+	RecordingRestartPolicyNever RecordingRestartPolicy = "Never"
+	// RecordingRestartPolicyOnSpecChange means the recording is created again
+	// from the new spec
+	RecordingRestartPolicyOnSpecChange RecordingRestartPolicy = "OnSpecChange"
+)
+
+// RecordingDeletionPolicy describes what happens to the archived JFR files of
+// a Recording when it is deleted
+type RecordingDeletionPolicy string
+
+const (
+	// RecordingDeletionPolicyDelete means the archived JFR files are deleted
+	// along with the Recording
+	RecordingDeletionPolicyDelete RecordingDeletionPolicy = "Delete"
+	// RecordingDeletionPolicyRetain means the archived JFR files are kept in
+	// Cryostat after the Recording is deleted
+	RecordingDeletionPolicyRetain RecordingDeletionPolicy = "Retain"
+)
+
+// WorkloadKind is a kind of workload that a Recording can target
+type WorkloadKind string
+
+const (
+	// WorkloadKindDeployment refers to a Deployment
+	WorkloadKindDeployment WorkloadKind = "Deployment"
+	// WorkloadKindStatefulSet refers to a StatefulSet
+	WorkloadKindStatefulSet WorkloadKind = "StatefulSet"
+)
+
+// WorkloadReference refers to a workload in the same namespace as the Recording
+type WorkloadReference struct {
+	// The kind of workload
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet
+	Kind WorkloadKind `json:"kind"`
+	// The name of the workload
+	Name string `json:"name"`
+}
+
+// TemplateReference refers to an event template available to a FlightRecorder
+type TemplateReference struct {
+	// The name of the template
+	Name string `json:"name"`
+	// The type of template, which is either "TARGET" for built-in templates,
+	// or "CUSTOM" for user created templates. If omitted, TARGET will be assumed.
+	// +optional
+	// +kubebuilder:validation:Enum=TARGET;CUSTOM
+	Type TemplateType `json:"type,omitempty"`
+}
+
+// EventOption sets an option for a JFR event type
+type EventOption struct {
+	// The ID used by JFR to uniquely identify the event type, such as "jdk.ExecutionSample"
+	TypeID string `json:"typeId"`
+	// The ID of the option to set, such as "enabled" or "period"
+	Option string `json:"option"`
+	// The value to set the option to, such as "true" or "20ms"
+	Value string `json:"value"`
 }
 
 // RecordingState describes the current state of the recording according
@@ -89,12 +242,15 @@ const (
 	// RecordingStateStopped means the recording has completed and the
 	// JFR file is fully written.
 	RecordingStateStopped RecordingState = "STOPPED"
+	// RecordingStateScheduled means the recording will not be created
+	// until its StartAt time. Only used in a Recording's status.
+	RecordingStateScheduled RecordingState = "SCHEDULED"
 )
 
 // RecordingStatus defines the observed state of Recording
 type RecordingStatus struct {
 	// Current state of the recording.
-	// +kubebuilder:validation:Enum=CREATED;RUNNING;STOPPING;STOPPED
+	// +kubebuilder:validation:Enum=SCHEDULED;CREATED;RUNNING;STOPPING;STOPPED
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
 	// +optional
 	State *RecordingState `json:"state,omitempty"`
@@ -102,10 +258,27 @@ type RecordingStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
 	// +optional
 	StartTime metav1.Time `json:"startTime,omitempty"`
+	// The date/time when the recording was first observed to have stopped.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 	// The duration of the recording specified during creation.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
 	// +optional
 	Duration metav1.Duration `json:"duration,omitempty"`
+	// The maximum size of the recording, as reported by the JVM. A zero value means no limit.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// The maximum age of events kept in the recording, as reported by the JVM.
+	// A zero value means no limit.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// Whether the recording is buffered on disk, as reported by the JVM.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	// +optional
+	ToDisk *bool `json:"toDisk,omitempty"`
 	// A URL to download the JFR file for the recording.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:org.w3:link"}
 	// +optional
@@ -114,10 +287,160 @@ type RecordingStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:org.w3:link"}
 	// +optional
 	ReportURL *string `json:"reportURL,omitempty"`
-	// TODO Consider adding Conditions:
-	// https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
+	// The state of the recording on each target pod, for recordings created
+	// from a Workload or Selector.
+	// +optional
+	// +listType=map
+	// +listMapKey=pod
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Targets []RecordingTargetStatus `json:"targets,omitempty"`
+	// Copies of the recording saved to persistent storage while it was running,
+	// either every ArchiveInterval, because its target pod was shutting down, or
+	// because it was restarted after a spec change, from oldest to newest. Only the
+	// most recent copies are listed, older copies are removed from storage.
+	// +optional
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ArchivedSnapshots []ArchivedSnapshot `json:"archivedSnapshots,omitempty"`
+	// Conditions of the Recording, such as whether it is ready, archived,
+	// or has failed, along with the reason for each.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The most recent generation of the Recording observed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The generation of the Recording whose spec is currently being recorded. This is
+	// lower than the current generation while changes to the spec have not been applied.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	RecordingGeneration int64 `json:"recordingGeneration,omitempty"`
+	// A hash of the spec fields that the recording in the target JVM was created from,
+	// used to detect changes that cannot be applied to the existing recording.
+	// +optional
+	RecordingSpecHash string `json:"recordingSpecHash,omitempty"`
 }
 
+// RecordingTargetStatus describes the recording on one of the pods targeted
+// by a Recording's Workload or Selector
+type RecordingTargetStatus struct {
+	// Name of the target pod
+	Pod string `json:"pod"`
+	// Name of the FlightRecorder corresponding to the target pod
+	// +optional
+	FlightRecorder string `json:"flightRecorder,omitempty"`
+	// Current state of the recording on this pod
+	// +kubebuilder:validation:Enum=CREATED;RUNNING;STOPPING;STOPPED
+	// +optional
+	State *RecordingState `json:"state,omitempty"`
+	// The date/time when the recording started on this pod
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// A URL to download the JFR file for the recording on this pod
+	// +optional
+	DownloadURL *string `json:"downloadURL,omitempty"`
+	// A URL to download the autogenerated HTML report for the recording on this pod
+	// +optional
+	ReportURL *string `json:"reportURL,omitempty"`
+	// Whether the recording on this pod has been saved to persistent storage
+	// +optional
+	Archived bool `json:"archived,omitempty"`
+	// Describes why the recording could not be created or updated on this pod
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ArchivedSnapshot describes a copy of a running recording saved to
+// persistent storage
+type ArchivedSnapshot struct {
+	// Name of the archived JFR file
+	Name string `json:"name"`
+	// A URL to download the archived JFR file
+	// +optional
+	DownloadURL string `json:"downloadURL,omitempty"`
+	// A URL to download the autogenerated HTML report for the archived JFR file
+	// +optional
+	ReportURL string `json:"reportURL,omitempty"`
+	// The date/time when the copy was saved
+	Time metav1.Time `json:"time"`
+	// Why the copy was saved
+	// +kubebuilder:validation:Enum=Interval;TargetTerminating;SpecChanged
+	// +optional
+	Reason ArchivedSnapshotReason `json:"reason,omitempty"`
+}
+
+// ArchivedSnapshotReason describes why a copy of a running recording was saved
+type ArchivedSnapshotReason string
+
+const (
+	// ArchivedSnapshotReasonInterval means the copy was saved because the
+	// ArchiveInterval had elapsed
+	ArchivedSnapshotReasonInterval ArchivedSnapshotReason = "Interval"
+	// ArchivedSnapshotReasonTargetTerminating means the copy was saved because
+	// the target pod was shutting down, and the recording would otherwise be lost
+	ArchivedSnapshotReasonTargetTerminating ArchivedSnapshotReason = "TargetTerminating"
+	// ArchivedSnapshotReasonSpecChanged means the copy was saved because the
+	// recording was restarted to apply changes to its spec
+	ArchivedSnapshotReasonSpecChanged ArchivedSnapshotReason = "SpecChanged"
+)
+
+// Condition types for Recording
+const (
+	// RecordingConditionReady indicates whether the recording has been
+	// created in the target JVM and its status is up to date.
+	RecordingConditionReady string = "Ready"
+	// RecordingConditionArchived indicates whether the recording has been
+	// saved to Cryostat's persistent storage.
+	RecordingConditionArchived string = "Archived"
+	// RecordingConditionTargetAvailable indicates whether the FlightRecorder
+	// and target pod for this recording could be found.
+	RecordingConditionTargetAvailable string = "TargetAvailable"
+	// RecordingConditionFailed indicates whether the operator encountered
+	// an error while processing this recording.
+	RecordingConditionFailed string = "Failed"
+)
+
+// RecordingRetainAnnotation exempts the archived JFR file of a Recording from
+// the Cryostat archive retention policy, when set to "true"
+const RecordingRetainAnnotation = "operator.cryostat.io/retain"
+
+// AlertFingerprintLabel is applied to each Recording created in response to
+// an Alertmanager alert, and contains the fingerprint of that alert
+const AlertFingerprintLabel = "operator.cryostat.io/alert-fingerprint"
+
+// AlertNameAnnotation is applied to each Recording created in response to
+// an Alertmanager alert, and contains the name of that alert
+const AlertNameAnnotation = "operator.cryostat.io/alert-name"
+
+// RecordingAdoptedAnnotation is applied to each Recording created for a recording
+// that already existed in the target JVM. The operator uses the existing recording
+// instead of creating a new one.
+const RecordingAdoptedAnnotation = "operator.cryostat.io/adopted"
+
+// RecordingOwnerLabel is attached to each recording the operator creates in a
+// target JVM, and contains the name of the Recording it was created for. Cryostat
+// lists it among the labels of the recording.
+const RecordingOwnerLabel = "operator.cryostat.io/recording"
+
+// RecordingForceDeleteAnnotation lets a Recording being deleted be removed
+// without cleaning up its recordings and JFR files in Cryostat, when set to "true".
+// It also applies to a FlightRecorder and the event templates uploaded for it.
+const RecordingForceDeleteAnnotation = "operator.cryostat.io/force-delete"
+
+// RecordingNamespaceLabel is attached to each archived JFR file kept in Cryostat
+// after its Recording was deleted with the "Retain" deletion policy, and contains
+// the namespace of that Recording. The name of the Recording is found in the
+// RecordingOwnerLabel.
+const RecordingNamespaceLabel = "operator.cryostat.io/namespace"
+
+// RecordingRetainLabel is attached to each archived JFR file kept in Cryostat
+// after its Recording was deleted with the "Retain" deletion policy. Files with
+// this label set to "true" are exempt from the Cryostat archive retention policy,
+// like those of a Recording with the RecordingRetainAnnotation.
+const RecordingRetainLabel = "operator.cryostat.io/retain"
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
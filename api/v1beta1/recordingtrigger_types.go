@@ -0,0 +1,146 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RecordingTriggerSpec defines the desired state of RecordingTrigger
+type RecordingTriggerSpec struct {
+	// Selects the pods to watch. A Recording is only created for pods that have a FlightRecorder.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Selector metav1.LabelSelector `json:"selector"`
+	// Name of the container to watch within each pod. If omitted, all containers are watched.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ContainerName string `json:"containerName,omitempty"`
+	// Create a Recording whenever a container restarts, whatever the reason.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:checkbox"}
+	OnRestart bool `json:"onRestart,omitempty"`
+	// Create a Recording when a container restarts after terminating for one of these
+	// reasons, such as "OOMKilled" or "Error".
+	// +optional
+	// +listType=set
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TerminationReasons []string `json:"terminationReasons,omitempty"`
+	// Create a Recording when a container is waiting to start for one of these reasons,
+	// such as "CrashLoopBackOff".
+	// +optional
+	// +listType=set
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	WaitingReasons []string `json:"waitingReasons,omitempty"`
+	// Template for the Recordings created when triggered. The Recording targets the
+	// FlightRecorder of the affected pod, and the name of each JFR recording is
+	// suffixed with the time it was triggered to keep it unique.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	RecordingTemplate RecordingTemplateSpec `json:"recordingTemplate"`
+	// The minimum time between Recordings created for the same pod, such as "10m".
+	// Triggers during this time are ignored. If omitted, 10 minutes will be assumed.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// RecordingTriggerStatus defines the observed state of RecordingTrigger
+type RecordingTriggerStatus struct {
+	// The pods currently watched by this trigger.
+	// +optional
+	// +listType=map
+	// +listMapKey=pod
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Pods []RecordingTriggerPodStatus `json:"pods,omitempty"`
+	// The last time a Recording was created by this trigger.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	LastTriggerTime *metav1.Time `json:"lastTriggerTime,omitempty"`
+}
+
+// RecordingTriggerPodStatus describes what a RecordingTrigger has observed of one pod
+type RecordingTriggerPodStatus struct {
+	// Name of the pod
+	Pod string `json:"pod"`
+	// The total restart count of the watched containers when last observed
+	RestartCount int32 `json:"restartCount"`
+	// Why a Recording will be created once the watched containers are running again
+	// +optional
+	PendingReason string `json:"pendingReason,omitempty"`
+	// The last time a Recording was created for this pod
+	// +optional
+	LastTriggerTime *metav1.Time `json:"lastTriggerTime,omitempty"`
+	// Name of the last Recording created for this pod
+	// +optional
+	LastRecording string `json:"lastRecording,omitempty"`
+}
+
+// RecordingTriggerLabel is applied to each Recording created by a RecordingTrigger,
+// and contains the name of that RecordingTrigger. Names longer than 63 characters are
+// truncated, and a hash of the full name is appended.
+const RecordingTriggerLabel = "operator.cryostat.io/recordingtrigger"
+
+// RecordingTriggerReasonAnnotation is applied to each Recording created by a
+// RecordingTrigger, and contains the reason it was triggered
+const RecordingTriggerReasonAnnotation = "operator.cryostat.io/trigger-reason"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=recordingtriggers,scope=Namespaced
+
+// RecordingTrigger is the Schema for the recordingtriggers API
+//+operator-sdk:csv:customresourcedefinitions:resources={{Recording,v1beta1}}
+type RecordingTrigger struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RecordingTriggerSpec   `json:"spec,omitempty"`
+	Status RecordingTriggerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RecordingTriggerList contains a list of RecordingTrigger
+type RecordingTriggerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RecordingTrigger `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RecordingTrigger{}, &RecordingTriggerList{})
+}
@@ -41,12 +41,63 @@
 package v1beta1
 
 import (
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/api/networking/v1"
+	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchiveRetentionPolicy) DeepCopyInto(out *ArchiveRetentionPolicy) {
+	*out = *in
+	if in.MaxFiles != nil {
+		in, out := &in.MaxFiles, &out.MaxFiles
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxTotalSize != nil {
+		in, out := &in.MaxTotalSize, &out.MaxTotalSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchiveRetentionPolicy.
+func (in *ArchiveRetentionPolicy) DeepCopy() *ArchiveRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchiveRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchivedSnapshot) DeepCopyInto(out *ArchivedSnapshot) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchivedSnapshot.
+func (in *ArchivedSnapshot) DeepCopy() *ArchivedSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchivedSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateSecret) DeepCopyInto(out *CertificateSecret) {
 	*out = *in
@@ -67,13 +118,133 @@ func (in *CertificateSecret) DeepCopy() *CertificateSecret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronRecording) DeepCopyInto(out *CronRecording) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronRecording.
+func (in *CronRecording) DeepCopy() *CronRecording {
+	if in == nil {
+		return nil
+	}
+	out := new(CronRecording)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CronRecording) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronRecordingList) DeepCopyInto(out *CronRecordingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CronRecording, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronRecordingList.
+func (in *CronRecordingList) DeepCopy() *CronRecordingList {
+	if in == nil {
+		return nil
+	}
+	out := new(CronRecordingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CronRecordingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronRecordingSpec) DeepCopyInto(out *CronRecordingSpec) {
+	*out = *in
+	in.RecordingTemplate.DeepCopyInto(&out.RecordingTemplate)
+	if in.SuccessfulRecordingsHistoryLimit != nil {
+		in, out := &in.SuccessfulRecordingsHistoryLimit, &out.SuccessfulRecordingsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedRecordingsHistoryLimit != nil {
+		in, out := &in.FailedRecordingsHistoryLimit, &out.FailedRecordingsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronRecordingSpec.
+func (in *CronRecordingSpec) DeepCopy() *CronRecordingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CronRecordingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronRecordingStatus) DeepCopyInto(out *CronRecordingStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronRecordingStatus.
+func (in *CronRecordingStatus) DeepCopy() *CronRecordingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CronRecordingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cryostat) DeepCopyInto(out *Cryostat) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cryostat.
@@ -156,6 +327,11 @@ func (in *CryostatSpec) DeepCopyInto(out *CryostatSpec) {
 		*out = new(NetworkConfigurationList)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RecordingFinalizerTimeout != nil {
+		in, out := &in.RecordingFinalizerTimeout, &out.RecordingFinalizerTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatSpec.
@@ -171,6 +347,13 @@ func (in *CryostatSpec) DeepCopy() *CryostatSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CryostatStatus) DeepCopyInto(out *CryostatStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatStatus.
@@ -183,6 +366,21 @@ func (in *CryostatStatus) DeepCopy() *CryostatStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventCatalogReference) DeepCopyInto(out *EventCatalogReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventCatalogReference.
+func (in *EventCatalogReference) DeepCopy() *EventCatalogReference {
+	if in == nil {
+		return nil
+	}
+	out := new(EventCatalogReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EventInfo) DeepCopyInto(out *EventInfo) {
 	*out = *in
@@ -210,6 +408,21 @@ func (in *EventInfo) DeepCopy() *EventInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventOption) DeepCopyInto(out *EventOption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventOption.
+func (in *EventOption) DeepCopy() *EventOption {
+	if in == nil {
+		return nil
+	}
+	out := new(EventOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FlightRecorder) DeepCopyInto(out *FlightRecorder) {
 	*out = *in
@@ -282,6 +495,11 @@ func (in *FlightRecorderSpec) DeepCopyInto(out *FlightRecorderSpec) {
 		*out = new(JMXAuthSecret)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EventTemplates != nil {
+		in, out := &in.EventTemplates, &out.EventTemplates
+		*out = make([]TemplateConfigMap, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlightRecorderSpec.
@@ -304,6 +522,11 @@ func (in *FlightRecorderStatus) DeepCopyInto(out *FlightRecorderStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EventCatalog != nil {
+		in, out := &in.EventCatalog, &out.EventCatalog
+		*out = new(EventCatalogReference)
+		**out = **in
+	}
 	if in.Templates != nil {
 		in, out := &in.Templates, &out.Templates
 		*out = make([]TemplateInfo, len(*in))
@@ -311,9 +534,46 @@ func (in *FlightRecorderStatus) DeepCopyInto(out *FlightRecorderStatus) {
 	}
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
-		*out = new(corev1.ObjectReference)
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Recordings != nil {
+		in, out := &in.Recordings, &out.Recordings
+		*out = make([]JVMRecordingInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastRefreshTime != nil {
+		in, out := &in.LastRefreshTime, &out.LastRefreshTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastOrphanSweepTime != nil {
+		in, out := &in.LastOrphanSweepTime, &out.LastOrphanSweepTime
+		*out = (*in).DeepCopy()
+	}
+	if in.JVMIdentity != nil {
+		in, out := &in.JVMIdentity, &out.JVMIdentity
+		*out = new(JVMIdentity)
 		**out = **in
 	}
+	if in.JVM != nil {
+		in, out := &in.JVM, &out.JVM
+		*out = new(JVMInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UploadedTemplates != nil {
+		in, out := &in.UploadedTemplates, &out.UploadedTemplates
+		*out = make([]UploadedTemplate, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlightRecorderStatus.
@@ -327,114 +587,322 @@ func (in *FlightRecorderStatus) DeepCopy() *FlightRecorderStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JMXAuthSecret) DeepCopyInto(out *JMXAuthSecret) {
+func (in *IncidentCapture) DeepCopyInto(out *IncidentCapture) {
 	*out = *in
-	if in.UsernameKey != nil {
-		in, out := &in.UsernameKey, &out.UsernameKey
-		*out = new(string)
-		**out = **in
-	}
-	if in.PasswordKey != nil {
-		in, out := &in.PasswordKey, &out.PasswordKey
-		*out = new(string)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JMXAuthSecret.
-func (in *JMXAuthSecret) DeepCopy() *JMXAuthSecret {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncidentCapture.
+func (in *IncidentCapture) DeepCopy() *IncidentCapture {
 	if in == nil {
 		return nil
 	}
-	out := new(JMXAuthSecret)
+	out := new(IncidentCapture)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IncidentCapture) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkConfiguration) DeepCopyInto(out *NetworkConfiguration) {
+func (in *IncidentCaptureList) DeepCopyInto(out *IncidentCaptureList) {
 	*out = *in
-	if in.IngressSpec != nil {
-		in, out := &in.IngressSpec, &out.IngressSpec
-		*out = new(v1.IngressSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IncidentCapture, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfiguration.
-func (in *NetworkConfiguration) DeepCopy() *NetworkConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncidentCaptureList.
+func (in *IncidentCaptureList) DeepCopy() *IncidentCaptureList {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfiguration)
+	out := new(IncidentCaptureList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IncidentCaptureList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkConfigurationList) DeepCopyInto(out *NetworkConfigurationList) {
+func (in *IncidentCaptureSpec) DeepCopyInto(out *IncidentCaptureSpec) {
 	*out = *in
-	if in.CoreConfig != nil {
-		in, out := &in.CoreConfig, &out.CoreConfig
-		*out = new(NetworkConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.CommandConfig != nil {
-		in, out := &in.CommandConfig, &out.CommandConfig
-		*out = new(NetworkConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.GrafanaConfig != nil {
-		in, out := &in.GrafanaConfig, &out.GrafanaConfig
-		*out = new(NetworkConfiguration)
-		(*in).DeepCopyInto(*out)
+	in.Selector.DeepCopyInto(&out.Selector)
+	out.Duration = in.Duration
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(TemplateReference)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigurationList.
-func (in *NetworkConfigurationList) DeepCopy() *NetworkConfigurationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncidentCaptureSpec.
+func (in *IncidentCaptureSpec) DeepCopy() *IncidentCaptureSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfigurationList)
+	out := new(IncidentCaptureSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OptionDescriptor) DeepCopyInto(out *OptionDescriptor) {
+func (in *IncidentCaptureStatus) DeepCopyInto(out *IncidentCaptureStatus) {
 	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]IncidentCaptureTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionDescriptor.
-func (in *OptionDescriptor) DeepCopy() *OptionDescriptor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncidentCaptureStatus.
+func (in *IncidentCaptureStatus) DeepCopy() *IncidentCaptureStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OptionDescriptor)
+	out := new(IncidentCaptureStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PersistentVolumeClaimConfig) DeepCopyInto(out *PersistentVolumeClaimConfig) {
+func (in *IncidentCaptureTarget) DeepCopyInto(out *IncidentCaptureTarget) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncidentCaptureTarget.
+func (in *IncidentCaptureTarget) DeepCopy() *IncidentCaptureTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(IncidentCaptureTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JMXAuthSecret) DeepCopyInto(out *JMXAuthSecret) {
+	*out = *in
+	if in.UsernameKey != nil {
+		in, out := &in.UsernameKey, &out.UsernameKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.PasswordKey != nil {
+		in, out := &in.PasswordKey, &out.PasswordKey
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JMXAuthSecret.
+func (in *JMXAuthSecret) DeepCopy() *JMXAuthSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(JMXAuthSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMIdentity) DeepCopyInto(out *JVMIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMIdentity.
+func (in *JVMIdentity) DeepCopy() *JVMIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMInfo) DeepCopyInto(out *JVMInfo) {
+	*out = *in
+	if in.PID != nil {
+		in, out := &in.PID, &out.PID
+		*out = new(int64)
+		**out = **in
+	}
+	out.Uptime = in.Uptime
+	in.HeapUsage.DeepCopyInto(&out.HeapUsage)
+	in.NonHeapUsage.DeepCopyInto(&out.NonHeapUsage)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMInfo.
+func (in *JVMInfo) DeepCopy() *JVMInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMMemoryUsage) DeepCopyInto(out *JVMMemoryUsage) {
+	*out = *in
+	out.Used = in.Used.DeepCopy()
+	out.Committed = in.Committed.DeepCopy()
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMMemoryUsage.
+func (in *JVMMemoryUsage) DeepCopy() *JVMMemoryUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMMemoryUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMRecordingInfo) DeepCopyInto(out *JVMRecordingInfo) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMRecordingInfo.
+func (in *JVMRecordingInfo) DeepCopy() *JVMRecordingInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMRecordingInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfiguration) DeepCopyInto(out *NetworkConfiguration) {
+	*out = *in
+	if in.IngressSpec != nil {
+		in, out := &in.IngressSpec, &out.IngressSpec
+		*out = new(networkingv1.IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfiguration.
+func (in *NetworkConfiguration) DeepCopy() *NetworkConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfigurationList) DeepCopyInto(out *NetworkConfigurationList) {
+	*out = *in
+	if in.CoreConfig != nil {
+		in, out := &in.CoreConfig, &out.CoreConfig
+		*out = new(NetworkConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommandConfig != nil {
+		in, out := &in.CommandConfig, &out.CommandConfig
+		*out = new(NetworkConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaConfig != nil {
+		in, out := &in.GrafanaConfig, &out.GrafanaConfig
+		*out = new(NetworkConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigurationList.
+func (in *NetworkConfigurationList) DeepCopy() *NetworkConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptionDescriptor) DeepCopyInto(out *OptionDescriptor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionDescriptor.
+func (in *OptionDescriptor) DeepCopy() *OptionDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(OptionDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimConfig) DeepCopyInto(out *PersistentVolumeClaimConfig) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
@@ -449,7 +917,7 @@ func (in *PersistentVolumeClaimConfig) DeepCopyInto(out *PersistentVolumeClaimCo
 	}
 	if in.Spec != nil {
 		in, out := &in.Spec, &out.Spec
-		*out = new(corev1.PersistentVolumeClaimSpec)
+		*out = new(v1.PersistentVolumeClaimSpec)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -531,15 +999,68 @@ func (in *RecordingSpec) DeepCopyInto(out *RecordingSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]EventOption, len(*in))
+		copy(*out, *in)
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(TemplateReference)
+		**out = **in
+	}
 	out.Duration = in.Duration
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ToDisk != nil {
+		in, out := &in.ToDisk, &out.ToDisk
+		*out = new(bool)
+		**out = **in
+	}
 	if in.State != nil {
 		in, out := &in.State, &out.State
 		*out = new(RecordingState)
 		**out = **in
 	}
+	if in.ArchiveInterval != nil {
+		in, out := &in.ArchiveInterval, &out.ArchiveInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.StartAt != nil {
+		in, out := &in.StartAt, &out.StartAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StopAt != nil {
+		in, out := &in.StopAt, &out.StopAt
+		*out = (*in).DeepCopy()
+	}
 	if in.FlightRecorder != nil {
 		in, out := &in.FlightRecorder, &out.FlightRecorder
-		*out = new(corev1.LocalObjectReference)
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(WorkloadReference)
+		**out = **in
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
 		**out = **in
 	}
 }
@@ -563,7 +1084,26 @@ func (in *RecordingStatus) DeepCopyInto(out *RecordingStatus) {
 		**out = **in
 	}
 	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 	out.Duration = in.Duration
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ToDisk != nil {
+		in, out := &in.ToDisk, &out.ToDisk
+		*out = new(bool)
+		**out = **in
+	}
 	if in.DownloadURL != nil {
 		in, out := &in.DownloadURL, &out.DownloadURL
 		*out = new(string)
@@ -574,6 +1114,27 @@ func (in *RecordingStatus) DeepCopyInto(out *RecordingStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]RecordingTargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ArchivedSnapshots != nil {
+		in, out := &in.ArchivedSnapshots, &out.ArchivedSnapshots
+		*out = make([]ArchivedSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingStatus.
@@ -586,6 +1147,193 @@ func (in *RecordingStatus) DeepCopy() *RecordingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingTargetStatus) DeepCopyInto(out *RecordingTargetStatus) {
+	*out = *in
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(RecordingState)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DownloadURL != nil {
+		in, out := &in.DownloadURL, &out.DownloadURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReportURL != nil {
+		in, out := &in.ReportURL, &out.ReportURL
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingTargetStatus.
+func (in *RecordingTargetStatus) DeepCopy() *RecordingTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingTemplateSpec) DeepCopyInto(out *RecordingTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingTemplateSpec.
+func (in *RecordingTemplateSpec) DeepCopy() *RecordingTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingTrigger) DeepCopyInto(out *RecordingTrigger) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingTrigger.
+func (in *RecordingTrigger) DeepCopy() *RecordingTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RecordingTrigger) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingTriggerList) DeepCopyInto(out *RecordingTriggerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RecordingTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingTriggerList.
+func (in *RecordingTriggerList) DeepCopy() *RecordingTriggerList {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingTriggerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RecordingTriggerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingTriggerPodStatus) DeepCopyInto(out *RecordingTriggerPodStatus) {
+	*out = *in
+	if in.LastTriggerTime != nil {
+		in, out := &in.LastTriggerTime, &out.LastTriggerTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingTriggerPodStatus.
+func (in *RecordingTriggerPodStatus) DeepCopy() *RecordingTriggerPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingTriggerPodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingTriggerSpec) DeepCopyInto(out *RecordingTriggerSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.TerminationReasons != nil {
+		in, out := &in.TerminationReasons, &out.TerminationReasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WaitingReasons != nil {
+		in, out := &in.WaitingReasons, &out.WaitingReasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.RecordingTemplate.DeepCopyInto(&out.RecordingTemplate)
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingTriggerSpec.
+func (in *RecordingTriggerSpec) DeepCopy() *RecordingTriggerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingTriggerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingTriggerStatus) DeepCopyInto(out *RecordingTriggerStatus) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]RecordingTriggerPodStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastTriggerTime != nil {
+		in, out := &in.LastTriggerTime, &out.LastTriggerTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingTriggerStatus.
+func (in *RecordingTriggerStatus) DeepCopy() *RecordingTriggerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingTriggerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
@@ -594,6 +1342,11 @@ func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 		*out = new(PersistentVolumeClaimConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(ArchiveRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
@@ -635,3 +1388,48 @@ func (in *TemplateInfo) DeepCopy() *TemplateInfo {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateReference) DeepCopyInto(out *TemplateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateReference.
+func (in *TemplateReference) DeepCopy() *TemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UploadedTemplate) DeepCopyInto(out *UploadedTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UploadedTemplate.
+func (in *UploadedTemplate) DeepCopy() *UploadedTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(UploadedTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadReference) DeepCopyInto(out *WorkloadReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadReference.
+func (in *WorkloadReference) DeepCopy() *WorkloadReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,338 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+)
+
+// AlertReceiver serves an Alertmanager webhook receiver, which creates a
+// Recording on the pod named by each firing alert
+type AlertReceiver struct {
+	client.Client
+	Log logr.Logger
+	// Address the HTTP server binds to, such as ":8082"
+	BindAddress string
+	// Bearer token that requests must present in their Authorization header
+	Token string
+	// Namespaces in which alerts may create Recordings
+	Namespaces []string
+	// Event template used for each Recording
+	Template operatorv1beta1.TemplateReference
+	// Duration of each Recording, or zero for continuous Recordings
+	Duration time.Duration
+	// How long to keep each Recording once it has finished, or zero to keep it indefinitely
+	TTL time.Duration
+}
+
+// Path the Alertmanager webhook payload is accepted on
+const AlertReceiverPath = "/alerts"
+
+// Alert labels identifying the pod to record, as set by the
+// Prometheus Kubernetes service discovery
+const (
+	alertLabelNamespace = "namespace"
+	alertLabelPod       = "pod"
+	alertLabelName      = "alertname"
+)
+
+// Largest Alertmanager payload accepted
+const maxAlertPayloadBytes = 1 << 20
+
+// alertPayload is the body of a request sent by an Alertmanager webhook receiver,
+// see https://prometheus.io/docs/alerting/latest/configuration/#webhook_config
+type alertPayload struct {
+	GroupKey string  `json:"groupKey"`
+	Receiver string  `json:"receiver"`
+	Alerts   []alert `json:"alerts"`
+}
+
+type alert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Fingerprint string            `json:"fingerprint"`
+	StartsAt    time.Time         `json:"startsAt"`
+}
+
+// Alertmanager fingerprints are hexadecimal, which is also safe to use
+// in object names and label values
+var alertFingerprintRegexp = regexp.MustCompile(`^[0-9a-f]{1,32}$`)
+
+// Outcomes of handling a single alert
+const (
+	alertResultCreated   = "Created"
+	alertResultDuplicate = "Duplicate"
+	alertResultIgnored   = "Ignored"
+)
+
+// alertResult describes how a single alert was handled, and is returned
+// to the sender to make the receiver easier to diagnose
+type alertResult struct {
+	Fingerprint string `json:"fingerprint"`
+	Result      string `json:"result"`
+	Recording   string `json:"recording,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+type alertResponse struct {
+	Alerts []alertResult `json:"alerts"`
+}
+
+// Start runs the HTTP server until the context is cancelled
+func (r *AlertReceiver) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(AlertReceiverPath, r)
+	server := &http.Server{
+		Addr:    r.BindAddress,
+		Handler: mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			r.Log.Error(err, "failed to shut down alert receiver")
+		}
+	}()
+
+	r.Log.Info("starting alert receiver", "address", r.BindAddress, "path", AlertReceiverPath)
+	err := server.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// NeedLeaderElection allows every replica of the manager to receive alerts,
+// duplicate alerts are rejected when creating their Recordings
+func (r *AlertReceiver) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP handles an Alertmanager webhook payload
+func (r *AlertReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if !r.authorized(req) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="cryostat-operator"`)
+		http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+		return
+	}
+
+	payload := &alertPayload{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxAlertPayloadBytes))
+	if err := decoder.Decode(payload); err != nil {
+		r.Log.Error(err, "failed to decode alert payload")
+		http.Error(w, fmt.Sprintf("invalid alert payload: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	reqLogger := r.Log.WithValues("groupKey", payload.GroupKey, "receiver", payload.Receiver)
+	reqLogger.Info("received alerts", "count", len(payload.Alerts))
+	response := &alertResponse{Alerts: []alertResult{}}
+	for idx := range payload.Alerts {
+		result, err := r.handleAlert(req.Context(), &payload.Alerts[idx])
+		if err != nil {
+			// Alertmanager retries the notification when the server fails
+			reqLogger.Error(err, "failed to handle alert", "fingerprint", result.Fingerprint)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		reqLogger.Info("handled alert", "fingerprint", result.Fingerprint, "result", result.Result,
+			"recording", result.Recording, "message", result.Message)
+		response.Alerts = append(response.Alerts, *result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		reqLogger.Error(err, "failed to write alert response")
+	}
+}
+
+// authorized checks that the request presents the receiver's bearer token.
+// Without a token, no request is authorized.
+func (r *AlertReceiver) authorized(req *http.Request) bool {
+	if len(r.Token) == 0 {
+		return false
+	}
+	const prefix = "Bearer "
+	header := req.Header.Get("Authorization")
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return false
+	}
+	token := strings.TrimSpace(header[len(prefix):])
+	return subtle.ConstantTimeCompare([]byte(token), []byte(r.Token)) == 1
+}
+
+func (r *AlertReceiver) namespaceAllowed(namespace string) bool {
+	for _, allowed := range r.Namespaces {
+		if allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAlert creates a Recording for a firing alert on the pod named by its labels
+func (r *AlertReceiver) handleAlert(ctx context.Context, alert *alert) (*alertResult, error) {
+	fingerprint := alert.Fingerprint
+	if !alertFingerprintRegexp.MatchString(fingerprint) {
+		// Older versions of Alertmanager don't send fingerprints
+		fingerprint = alertFingerprint(alert.Labels)
+	}
+	result := &alertResult{Fingerprint: fingerprint}
+	if alert.Status != "firing" {
+		result.Result = alertResultIgnored
+		result.Message = "Alert is not firing"
+		return result, nil
+	}
+	namespace := alert.Labels[alertLabelNamespace]
+	podName := alert.Labels[alertLabelPod]
+	if len(namespace) == 0 || len(podName) == 0 {
+		result.Result = alertResultIgnored
+		result.Message = fmt.Sprintf("Alert does not have both %q and %q labels", alertLabelNamespace, alertLabelPod)
+		return result, nil
+	}
+	result.Namespace = namespace
+	if !r.namespaceAllowed(namespace) {
+		result.Result = alertResultIgnored
+		result.Message = fmt.Sprintf("Alerts may not create Recordings in namespace %s", namespace)
+		return result, nil
+	}
+
+	jfrs, err := getFlightRecordersByPod(ctx, r.Client, namespace)
+	if err != nil {
+		return result, err
+	}
+	jfr, pres := jfrs[podName]
+	if !pres {
+		result.Result = alertResultIgnored
+		result.Message = fmt.Sprintf("No FlightRecorder found for pod %s", podName)
+		return result, nil
+	}
+
+	// The name is derived from the fingerprint and start time, so that each firing
+	// of an alert is only recorded once, even though Alertmanager resends it
+	recording := r.newRecordingForAlert(alert, fingerprint, namespace, jfr)
+	result.Recording = recording.Name
+	err = r.Client.Create(ctx, recording)
+	if kerrors.IsAlreadyExists(err) {
+		result.Result = alertResultDuplicate
+		result.Message = "A Recording already exists for this firing of the alert"
+		return result, nil
+	} else if err != nil {
+		return result, err
+	}
+	result.Result = alertResultCreated
+	return result, nil
+}
+
+func (r *AlertReceiver) newRecordingForAlert(alert *alert, fingerprint string, namespace string,
+	jfr *operatorv1beta1.FlightRecorder) *operatorv1beta1.Recording {
+	name := "alert-" + fingerprint
+	if !alert.StartsAt.IsZero() {
+		name += "-" + strconv.FormatInt(alert.StartsAt.Unix(), 10)
+	}
+	template := r.Template
+	var ttl *int32
+	if r.TTL > 0 {
+		seconds := int32(r.TTL.Seconds())
+		ttl = &seconds
+	}
+	return &operatorv1beta1.Recording{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				operatorv1beta1.AlertFingerprintLabel: fingerprint,
+			},
+			Annotations: map[string]string{
+				operatorv1beta1.AlertNameAnnotation: alert.Labels[alertLabelName],
+			},
+		},
+		Spec: operatorv1beta1.RecordingSpec{
+			Name:                    name,
+			Template:                &template,
+			Duration:                metav1.Duration{Duration: r.Duration},
+			Archive:                 true,
+			TTLSecondsAfterFinished: ttl,
+			FlightRecorder:          &corev1.LocalObjectReference{Name: jfr.Name},
+		},
+	}
+}
+
+// alertFingerprint computes a fingerprint for an alert from its labels
+func alertFingerprint(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := fnv.New64a()
+	for _, key := range keys {
+		// Separate each key and value, so that they can't run together
+		hash.Write([]byte(key))
+		hash.Write([]byte{0xff})
+		hash.Write([]byte(labels[key]))
+		hash.Write([]byte{0xff})
+	}
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
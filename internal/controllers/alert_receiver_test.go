@@ -0,0 +1,335 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+)
+
+type alertReceiverTestInput struct {
+	receiver *controllers.AlertReceiver
+	objs     []runtime.Object
+	client   client.Client
+}
+
+type alertReceiverResponse struct {
+	Alerts []struct {
+		Fingerprint string `json:"fingerprint"`
+		Result      string `json:"result"`
+		Recording   string `json:"recording"`
+		Message     string `json:"message"`
+	} `json:"alerts"`
+}
+
+const testFingerprint = "6f2e1c9b8a7d4e30"
+const testAlertToken = "alert-token"
+
+var _ = Describe("AlertReceiver", func() {
+	var t *alertReceiverTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.receiver = &controllers.AlertReceiver{
+			Client:     t.client,
+			Log:        logger,
+			Token:      testAlertToken,
+			Namespaces: []string{"default"},
+			Template: operatorv1beta1.TemplateReference{
+				Name: "Profiling",
+				Type: operatorv1beta1.TemplateTypeTarget,
+			},
+			Duration: 5 * time.Minute,
+			TTL:      24 * time.Hour,
+		}
+	})
+
+	BeforeEach(func() {
+		t = &alertReceiverTestInput{
+			objs: []runtime.Object{
+				test.NewFlightRecorder(), test.NewTargetPod(),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("receiving alerts", func() {
+		Context("with a firing alert for a pod", func() {
+			It("should create a recording for the pod", func() {
+				resp := t.postAlerts(test.NewAlertPayload("firing", "test-pod", testFingerprint))
+				Expect(resp.Code).To(Equal(http.StatusOK))
+
+				expected := test.NewAlertRecording(testFingerprint)
+				recording := t.getRecording(expected.Name)
+				Expect(recording.Labels).To(Equal(expected.Labels))
+				Expect(recording.Annotations).To(Equal(expected.Annotations))
+				Expect(recording.Spec).To(Equal(expected.Spec))
+			})
+			It("should report the recording", func() {
+				resp := t.postAlerts(test.NewAlertPayload("firing", "test-pod", testFingerprint))
+				result := decodeAlertResponse(resp)
+				Expect(result.Alerts).To(HaveLen(1))
+				Expect(result.Alerts[0].Fingerprint).To(Equal(testFingerprint))
+				Expect(result.Alerts[0].Result).To(Equal("Created"))
+				Expect(result.Alerts[0].Recording).To(Equal(test.NewAlertRecordingName(testFingerprint)))
+			})
+			It("should not create a second recording for the same alert", func() {
+				t.postAlerts(test.NewAlertPayload("firing", "test-pod", testFingerprint))
+				resp := t.postAlerts(test.NewAlertPayload("firing", "test-pod", testFingerprint))
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				result := decodeAlertResponse(resp)
+				Expect(result.Alerts[0].Result).To(Equal("Duplicate"))
+				Expect(t.getAlertRecordings()).To(HaveLen(1))
+			})
+			It("should create another recording when the alert fires again", func() {
+				t.postAlerts(test.NewAlertPayload("firing", "test-pod", testFingerprint))
+				payload := strings.Replace(test.NewAlertPayload("firing", "test-pod", testFingerprint),
+					"2021-05-01T12:00:00Z", "2021-05-02T08:30:00Z", 1)
+				resp := t.postAlerts(payload)
+				result := decodeAlertResponse(resp)
+				Expect(result.Alerts[0].Result).To(Equal("Created"))
+				Expect(result.Alerts[0].Recording).To(Equal("alert-" + testFingerprint + "-1619944200"))
+				Expect(t.getAlertRecordings()).To(HaveLen(2))
+			})
+		})
+		Context("with an alert without a fingerprint", func() {
+			It("should compute one from the labels", func() {
+				first := decodeAlertResponse(t.postAlerts(test.NewAlertPayload("firing", "test-pod", "")))
+				second := decodeAlertResponse(t.postAlerts(test.NewAlertPayload("firing", "test-pod", "")))
+				Expect(first.Alerts[0].Result).To(Equal("Created"))
+				Expect(first.Alerts[0].Fingerprint).To(MatchRegexp("^[0-9a-f]{16}$"))
+				Expect(second.Alerts[0].Result).To(Equal("Duplicate"))
+				Expect(second.Alerts[0].Fingerprint).To(Equal(first.Alerts[0].Fingerprint))
+			})
+		})
+		Context("with a resolved alert", func() {
+			It("should not create a recording", func() {
+				resp := t.postAlerts(test.NewAlertPayload("resolved", "test-pod", testFingerprint))
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(decodeAlertResponse(resp).Alerts[0].Result).To(Equal("Ignored"))
+				Expect(t.getAlertRecordings()).To(BeEmpty())
+			})
+		})
+		Context("with an alert without a pod label", func() {
+			It("should not create a recording", func() {
+				payload := strings.Replace(test.NewAlertPayload("firing", "test-pod", testFingerprint),
+					`"pod": "test-pod"`, `"instance": "10.0.0.1:8080"`, 1)
+				resp := t.postAlerts(payload)
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				result := decodeAlertResponse(resp)
+				Expect(result.Alerts[0].Result).To(Equal("Ignored"))
+				Expect(result.Alerts[0].Message).To(ContainSubstring(`"pod"`))
+				Expect(t.getAlertRecordings()).To(BeEmpty())
+			})
+		})
+		Context("with an alert for a pod without a FlightRecorder", func() {
+			It("should not create a recording", func() {
+				resp := t.postAlerts(test.NewAlertPayload("firing", "other-pod", testFingerprint))
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				result := decodeAlertResponse(resp)
+				Expect(result.Alerts[0].Result).To(Equal("Ignored"))
+				Expect(result.Alerts[0].Message).To(Equal("No FlightRecorder found for pod other-pod"))
+				Expect(t.getAlertRecordings()).To(BeEmpty())
+			})
+		})
+		Context("with an alert for a namespace that is not allowed", func() {
+			It("should not create a recording", func() {
+				payload := strings.ReplaceAll(test.NewAlertPayload("firing", "test-pod", testFingerprint),
+					`"namespace": "default"`, `"namespace": "other"`)
+				resp := t.postAlerts(payload)
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				result := decodeAlertResponse(resp)
+				Expect(result.Alerts[0].Result).To(Equal("Ignored"))
+				Expect(result.Alerts[0].Message).To(Equal("Alerts may not create Recordings in namespace other"))
+				recordings := &operatorv1beta1.RecordingList{}
+				Expect(t.client.List(context.Background(), recordings)).To(Succeed())
+				Expect(recordings.Items).To(BeEmpty())
+			})
+		})
+		Context("without a bearer token", func() {
+			It("should respond with unauthorized", func() {
+				req := httptest.NewRequest(http.MethodPost, controllers.AlertReceiverPath,
+					strings.NewReader(test.NewAlertPayload("firing", "test-pod", testFingerprint)))
+				resp := httptest.NewRecorder()
+				t.receiver.ServeHTTP(resp, req)
+				Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+				Expect(resp.Header().Get("WWW-Authenticate")).To(HavePrefix("Bearer"))
+				Expect(t.getAlertRecordings()).To(BeEmpty())
+			})
+		})
+		Context("with the wrong bearer token", func() {
+			It("should respond with unauthorized", func() {
+				req := httptest.NewRequest(http.MethodPost, controllers.AlertReceiverPath,
+					strings.NewReader(test.NewAlertPayload("firing", "test-pod", testFingerprint)))
+				req.Header.Set("Authorization", "Bearer wrong")
+				resp := httptest.NewRecorder()
+				t.receiver.ServeHTTP(resp, req)
+				Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+				Expect(t.getAlertRecordings()).To(BeEmpty())
+			})
+		})
+		Context("when the receiver has no token", func() {
+			JustBeforeEach(func() {
+				t.receiver.Token = ""
+			})
+			It("should reject every request", func() {
+				req := httptest.NewRequest(http.MethodPost, controllers.AlertReceiverPath,
+					strings.NewReader(test.NewAlertPayload("firing", "test-pod", testFingerprint)))
+				req.Header.Set("Authorization", "Bearer ")
+				resp := httptest.NewRecorder()
+				t.receiver.ServeHTTP(resp, req)
+				Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+		Context("with an invalid payload", func() {
+			It("should respond with bad request", func() {
+				resp := t.postAlerts("{")
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("with a GET request", func() {
+			It("should respond with method not allowed", func() {
+				req := httptest.NewRequest(http.MethodGet, controllers.AlertReceiverPath, nil)
+				resp := httptest.NewRecorder()
+				t.receiver.ServeHTTP(resp, req)
+				Expect(resp.Code).To(Equal(http.StatusMethodNotAllowed))
+				Expect(resp.Header().Get("Allow")).To(Equal(http.MethodPost))
+			})
+		})
+	})
+
+	Describe("serving alerts", func() {
+		var cancel context.CancelFunc
+		var done chan error
+		var address string
+
+		JustBeforeEach(func() {
+			// Find a free local port
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			address = listener.Addr().String()
+			Expect(listener.Close()).To(Succeed())
+
+			t.receiver.BindAddress = address
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan error, 1)
+			go func() {
+				done <- t.receiver.Start(ctx)
+			}()
+		})
+
+		It("should accept alerts over HTTP until stopped", func() {
+			url := "http://" + address + controllers.AlertReceiverPath
+			payload := test.NewAlertPayload("firing", "test-pod", testFingerprint)
+			Eventually(func() (int, error) {
+				req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(payload))
+				if err != nil {
+					return 0, err
+				}
+				req.Header.Set("Authorization", "Bearer "+testAlertToken)
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					return 0, err
+				}
+				defer resp.Body.Close()
+				return resp.StatusCode, nil
+			}).Should(Equal(http.StatusOK))
+			Expect(t.getAlertRecordings()).To(HaveLen(1))
+
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+	})
+})
+
+func (t *alertReceiverTestInput) postAlerts(payload string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, controllers.AlertReceiverPath, strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAlertToken)
+	resp := httptest.NewRecorder()
+	t.receiver.ServeHTTP(resp, req)
+	return resp
+}
+
+func (t *alertReceiverTestInput) getRecording(name string) *operatorv1beta1.Recording {
+	recording := &operatorv1beta1.Recording{}
+	err := t.client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, recording)
+	Expect(err).ToNot(HaveOccurred())
+	return recording
+}
+
+func (t *alertReceiverTestInput) getAlertRecordings() []operatorv1beta1.Recording {
+	recordings := &operatorv1beta1.RecordingList{}
+	err := t.client.List(context.Background(), recordings, client.InNamespace("default"),
+		client.HasLabels{operatorv1beta1.AlertFingerprintLabel})
+	Expect(err).ToNot(HaveOccurred())
+	return recordings.Items
+}
+
+func decodeAlertResponse(resp *httptest.ResponseRecorder) *alertReceiverResponse {
+	result := &alertReceiverResponse{}
+	Expect(json.Unmarshal(resp.Body.Bytes(), result)).To(Succeed())
+	return result
+}
@@ -0,0 +1,285 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+)
+
+// ArchiveRetentionReconciler periodically enforces the archive retention
+// policy of a Cryostat object
+type ArchiveRetentionReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+	common.Reconciler
+}
+
+// How often the retention policy is enforced, if not specified in the policy
+const defaultRetentionInterval = time.Hour
+
+// Reasons used for Events emitted while enforcing the retention policy
+const (
+	eventArchivedRecordingsPruned = "ArchivedRecordingsPruned"
+	eventArchivePruneFailed       = "ArchivePruneFailed"
+	eventRetentionPolicyExceeded  = "RetentionPolicyExceeded"
+)
+
+// Cryostat archives recordings with a timestamp suffix, such as
+// "10-217-0-29_my-recording_20210429T221259Z.jfr", optionally followed
+// by a counter to keep the filename unique
+var archivedTimestampRegexp = regexp.MustCompile(`_(\d{8}T\d{6}Z)(\.\d+)?\.jfr$`)
+
+const archivedTimestampLayout = "20060102T150405Z"
+
+// archivedFile is a recording in Cryostat's archives considered by the retention policy
+type archivedFile struct {
+	cryostatClient.SavedRecording
+	// Time the file was archived, or zero if unknown
+	archivedTime time.Time
+	// Whether the file must be kept regardless of the policy
+	retained bool
+}
+
+// Reconcile processes a Cryostat object and deletes archived recordings from its
+// storage that exceed its retention policy
+func (r *ArchiveRetentionReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	// Fetch the Cryostat instance
+	instance := &operatorv1beta1.Cryostat{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			reqLogger.Info("Cryostat instance not found")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Nothing to do unless a retention policy is configured
+	if instance.Spec.StorageOptions == nil || instance.Spec.StorageOptions.Retention == nil ||
+		instance.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+	policy := instance.Spec.StorageOptions.Retention
+	result := reconcile.Result{RequeueAfter: defaultRetentionInterval}
+	if policy.Interval != nil && policy.Interval.Duration > 0 {
+		result.RequeueAfter = policy.Interval.Duration
+	}
+	reqLogger.Info("Enforcing archive retention policy")
+
+	// Obtain a client configured to communicate with Cryostat
+	cryostat, err := r.GetCryostatClient(ctx, instance.Namespace, nil)
+	if err != nil {
+		if err == common.ErrCertNotReady {
+			reqLogger.Info("Waiting for CA certificate")
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Determine which archived recordings to delete
+	saved, err := cryostat.ListSavedRecordings()
+	if err != nil {
+		reqLogger.Error(err, "failed to list saved flight recordings")
+		return reconcile.Result{}, err
+	}
+	retained, err := r.getRetainedFiles(ctx, instance.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	expired, exceeded := selectExpiredFiles(newArchivedFiles(saved, retained), policy, r.Now())
+
+	// Delete the selected files, oldest first
+	deleted := []string{}
+	for _, file := range expired {
+		err = cryostat.DeleteSavedRecording(file.Name)
+		if err != nil {
+			reqLogger.Error(err, "failed to delete saved recording", "file", file.Name)
+			r.EventRecorder.Event(instance, corev1.EventTypeWarning, eventArchivePruneFailed,
+				fmt.Sprintf("Failed to delete archived recording \"%s\" to enforce the retention policy: %s",
+					file.Name, err.Error()))
+			r.emitPrunedEvent(instance, deleted)
+			return reconcile.Result{}, err
+		}
+		reqLogger.Info("deleted saved recording to enforce retention policy", "file", file.Name)
+		deleted = append(deleted, file.Name)
+	}
+	r.emitPrunedEvent(instance, deleted)
+
+	if exceeded {
+		r.EventRecorder.Event(instance, corev1.EventTypeWarning, eventRetentionPolicyExceeded,
+			"Archived recordings exceed the retention policy, but the remaining recordings are retained")
+	}
+	return result, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ArchiveRetentionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("archiveretention").
+		For(&operatorv1beta1.Cryostat{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *ArchiveRetentionReconciler) getRetainedFiles(ctx context.Context, namespace string) (map[string]bool, error) {
+	recordings := &operatorv1beta1.RecordingList{}
+	err := r.Client.List(ctx, recordings, client.InNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	retained := map[string]bool{}
+	for _, recording := range recordings.Items {
+		if recording.Annotations[operatorv1beta1.RecordingRetainAnnotation] != "true" {
+			continue
+		}
+		jfrFiles, err := archivedFilenames(&recording)
+		if err != nil {
+			return nil, err
+		}
+		for jfrFile := range jfrFiles {
+			retained[jfrFile] = true
+		}
+	}
+	return retained, nil
+}
+
+func (r *ArchiveRetentionReconciler) emitPrunedEvent(cr *operatorv1beta1.Cryostat, deleted []string) {
+	if len(deleted) == 0 {
+		return
+	}
+	r.EventRecorder.Event(cr, corev1.EventTypeNormal, eventArchivedRecordingsPruned,
+		fmt.Sprintf("Deleted %d archived recording(s) to enforce the retention policy: %s", len(deleted),
+			strings.Join(deleted, ", ")))
+}
+
+// newArchivedFiles returns the saved recordings sorted from oldest to newest.
+// Files with an unknown archive time are treated as the newest.
+func newArchivedFiles(saved []cryostatClient.SavedRecording, retained map[string]bool) []archivedFile {
+	files := make([]archivedFile, 0, len(saved))
+	for _, recording := range saved {
+		files = append(files, archivedFile{
+			SavedRecording: recording,
+			archivedTime:   getArchivedTime(&recording),
+			retained: retained[recording.Name] ||
+				recording.Metadata.Labels[operatorv1beta1.RecordingRetainLabel] == "true",
+		})
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[j].archivedTime.IsZero() {
+			return !files[i].archivedTime.IsZero()
+		}
+		return !files[i].archivedTime.IsZero() && files[i].archivedTime.Before(files[j].archivedTime)
+	})
+	return files
+}
+
+func getArchivedTime(recording *cryostatClient.SavedRecording) time.Time {
+	if recording.ArchivedTime > 0 {
+		return time.Unix(0, recording.ArchivedTime*int64(time.Millisecond))
+	}
+	// Older versions of Cryostat don't report when the file was archived
+	match := archivedTimestampRegexp.FindStringSubmatch(recording.Name)
+	if match == nil {
+		return time.Time{}
+	}
+	archived, err := time.Parse(archivedTimestampLayout, match[1])
+	if err != nil {
+		return time.Time{}
+	}
+	return archived
+}
+
+// selectExpiredFiles returns the files that must be deleted to satisfy the policy,
+// oldest first, and whether the policy will still be exceeded due to retained files
+func selectExpiredFiles(files []archivedFile, policy *operatorv1beta1.ArchiveRetentionPolicy,
+	now time.Time) (expired []archivedFile, exceeded bool) {
+	remaining := []archivedFile{}
+	for _, file := range files {
+		if !file.retained && policy.MaxAge != nil && !file.archivedTime.IsZero() &&
+			now.Sub(file.archivedTime) > policy.MaxAge.Duration {
+			expired = append(expired, file)
+		} else {
+			remaining = append(remaining, file)
+		}
+	}
+
+	count := int64(len(remaining))
+	var size int64
+	for _, file := range remaining {
+		size += file.Size
+	}
+	withinLimits := func() bool {
+		return (policy.MaxFiles == nil || count <= int64(*policy.MaxFiles)) &&
+			(policy.MaxTotalSize == nil || size <= policy.MaxTotalSize.Value())
+	}
+	for _, file := range remaining {
+		if withinLimits() {
+			break
+		}
+		if file.retained {
+			continue
+		}
+		expired = append(expired, file)
+		count--
+		size -= file.Size
+	}
+	return expired, !withinLimits()
+}
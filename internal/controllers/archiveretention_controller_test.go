@@ -0,0 +1,342 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+)
+
+type archiveRetentionTestInput struct {
+	controller *controllers.ArchiveRetentionReconciler
+	objs       []runtime.Object
+	handlers   []http.HandlerFunc
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("ArchiveRetentionController", func() {
+	var t *archiveRetentionTestInput
+	var files []string
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.Server = test.NewServer(t.Client, t.handlers, t.TLS)
+		t.controller = &controllers.ArchiveRetentionReconciler{
+			Client:        t.Client,
+			Scheme:        s,
+			Log:           logger,
+			EventRecorder: record.NewFakeRecorder(1024),
+			Reconciler:    test.NewTestReconciler(&t.TestReconcilerConfig),
+		}
+	})
+
+	JustAfterEach(func() {
+		t.Server.VerifyRequestsReceived(t.handlers)
+		t.Server.Close()
+	})
+
+	BeforeEach(func() {
+		now := time.Date(2021, time.May, 3, 3, 0, 0, 0, time.UTC)
+		t = &archiveRetentionTestInput{
+			objs: []runtime.Object{
+				test.NewCACert(), test.NewCryostatService(),
+			},
+			TestReconcilerConfig: test.TestReconcilerConfig{
+				TLS: true,
+				Now: &now,
+			},
+		}
+		files = []string{}
+		for _, file := range test.NewArchivedFiles() {
+			files = append(files, file.Name)
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("without a retention policy", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat())
+			})
+			It("should not requeue", func() {
+				result := t.reconcileRetention()
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+		Context("with archived recordings within the policy", func() {
+			BeforeEach(func() {
+				maxFiles := int32(3)
+				t.objs = append(t.objs, test.NewCryostatWithRetention(&operatorv1beta1.ArchiveRetentionPolicy{
+					MaxFiles: &maxFiles,
+					Interval: &metav1.Duration{Duration: 10 * time.Minute},
+				}))
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewArchivedFiles()),
+				}
+			})
+			It("should requeue after the interval", func() {
+				result := t.reconcileRetention()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Minute}))
+			})
+			It("should not emit an Event", func() {
+				t.reconcileRetention()
+				Expect(t.getEvents()).To(BeEmpty())
+			})
+		})
+		Context("with more archived recordings than allowed", func() {
+			BeforeEach(func() {
+				maxFiles := int32(2)
+				t.objs = append(t.objs, test.NewCryostatWithRetention(&operatorv1beta1.ArchiveRetentionPolicy{
+					MaxFiles: &maxFiles,
+				}))
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewArchivedFiles()),
+					test.NewDeleteArchivedFileHandler(files[0]),
+				}
+			})
+			It("should delete the oldest recording and requeue after an hour", func() {
+				result := t.reconcileRetention()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Hour}))
+			})
+			It("should emit an Event", func() {
+				t.reconcileRetention()
+				events := t.getEvents()
+				Expect(events).To(HaveLen(1))
+				Expect(events[0]).To(ContainSubstring("ArchivedRecordingsPruned"))
+				Expect(events[0]).To(ContainSubstring(files[0]))
+			})
+		})
+		Context("with archived recordings larger than allowed", func() {
+			BeforeEach(func() {
+				maxSize := resource.MustParse("150Mi")
+				t.objs = append(t.objs, test.NewCryostatWithRetention(&operatorv1beta1.ArchiveRetentionPolicy{
+					MaxTotalSize: &maxSize,
+				}))
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewArchivedFiles()),
+					test.NewDeleteArchivedFileHandler(files[0]),
+					test.NewDeleteArchivedFileHandler(files[1]),
+				}
+			})
+			It("should delete the oldest recordings", func() {
+				t.reconcileRetention()
+			})
+		})
+		Context("with archived recordings older than allowed", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithRetention(&operatorv1beta1.ArchiveRetentionPolicy{
+					MaxAge: &metav1.Duration{Duration: 36 * time.Hour},
+				}))
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewArchivedFiles()),
+					test.NewDeleteArchivedFileHandler(files[0]),
+				}
+			})
+			It("should delete the expired recording", func() {
+				t.reconcileRetention()
+			})
+		})
+		Context("with a retained archived recording", func() {
+			BeforeEach(func() {
+				maxSize := resource.MustParse("50Mi")
+				saved := test.NewArchivedFiles()
+				recording := test.NewArchivedRecording()
+				recording.Annotations = map[string]string{
+					operatorv1beta1.RecordingRetainAnnotation: "true",
+				}
+				recording.Status.DownloadURL = &saved[0].DownloadURL
+				t.objs = append(t.objs, recording, test.NewCryostatWithRetention(&operatorv1beta1.ArchiveRetentionPolicy{
+					MaxTotalSize: &maxSize,
+				}))
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(saved),
+					test.NewDeleteArchivedFileHandler(files[1]),
+					test.NewDeleteArchivedFileHandler(files[2]),
+				}
+			})
+			It("should delete the other recordings", func() {
+				t.reconcileRetention()
+			})
+			It("should warn that the policy is exceeded", func() {
+				t.reconcileRetention()
+				events := t.getEvents()
+				Expect(events).To(HaveLen(2))
+				Expect(events[1]).To(ContainSubstring("RetentionPolicyExceeded"))
+			})
+		})
+		Context("with the archived recording of an existing Recording", func() {
+			BeforeEach(func() {
+				maxFiles := int32(2)
+				saved := test.NewArchivedFiles()
+				recording := test.NewArchivedRecording()
+				recording.Status.DownloadURL = &saved[0].DownloadURL
+				t.objs = append(t.objs, recording, test.NewCryostatWithRetention(&operatorv1beta1.ArchiveRetentionPolicy{
+					MaxFiles: &maxFiles,
+				}))
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(saved),
+					test.NewDeleteArchivedFileHandler(files[0]),
+				}
+			})
+			It("should delete it like any other recording", func() {
+				t.reconcileRetention()
+			})
+		})
+		Context("with a file kept after its Recording was deleted with the Retain policy", func() {
+			var saved []cryostatClient.SavedRecording
+			BeforeEach(func() {
+				maxFiles := int32(0)
+				saved = test.NewSavedRecordings()
+				t.objs = append(t.objs, test.NewCryostatWithRetention(&operatorv1beta1.ArchiveRetentionPolicy{
+					MaxFiles: &maxFiles,
+				}), test.NewFlightRecorder(), test.NewTargetPod(), test.NewJMXAuthSecret(),
+					test.NewDeletedArchivedRecordingWithPolicy(operatorv1beta1.RecordingDeletionPolicyRetain))
+				t.handlers = []http.HandlerFunc{
+					// Handled by the Recording controller
+					test.NewListSavedHandler(saved),
+					ghttp.CombineHandlers(
+						func(w http.ResponseWriter, r *http.Request) {
+							// Remember the labels sent by the Recording controller
+							body, err := ioutil.ReadAll(r.Body)
+							Expect(err).ToNot(HaveOccurred())
+							r.Body = ioutil.NopCloser(bytes.NewReader(body))
+							Expect(json.Unmarshal(body, &saved[0].Metadata.Labels)).To(Succeed())
+						},
+						test.NewRetainSavedHandler(),
+					),
+					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 30000)),
+					test.NewDeleteHandler(),
+					// Handled by the archive retention controller
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(http.MethodGet, "/api/v1/recordings"),
+						func(w http.ResponseWriter, r *http.Request) {
+							ghttp.RespondWithJSONEncoded(http.StatusOK, saved)(w, r)
+						},
+					),
+				}
+			})
+			It("should not delete the retained file", func() {
+				recordings := &controllers.RecordingReconciler{
+					Client:        t.Client,
+					Scheme:        t.controller.Scheme,
+					Log:           t.controller.Log,
+					EventRecorder: record.NewFakeRecorder(1024),
+					Reconciler:    test.NewTestReconciler(&t.TestReconcilerConfig),
+				}
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "my-recording", Namespace: "default"}}
+				_, err := recordings.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+
+				t.reconcileRetention()
+				events := t.getEvents()
+				Expect(events).To(HaveLen(1))
+				Expect(events[0]).To(ContainSubstring("RetentionPolicyExceeded"))
+			})
+		})
+		Context("when deleting an archived recording fails", func() {
+			BeforeEach(func() {
+				maxFiles := int32(2)
+				t.objs = append(t.objs, test.NewCryostatWithRetention(&operatorv1beta1.ArchiveRetentionPolicy{
+					MaxFiles: &maxFiles,
+				}))
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewArchivedFiles()),
+					test.NewDeleteArchivedFileFailHandler(files[0]),
+				}
+			})
+			It("should requeue with error", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				_, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).To(HaveOccurred())
+			})
+			It("should emit a Warning Event", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				t.controller.Reconcile(context.Background(), req)
+				events := t.getEvents()
+				Expect(events).To(HaveLen(1))
+				Expect(events[0]).To(ContainSubstring("ArchivePruneFailed"))
+			})
+		})
+	})
+})
+
+func (t *archiveRetentionTestInput) reconcileRetention() reconcile.Result {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	return result
+}
+
+func (t *archiveRetentionTestInput) getEvents() []string {
+	recorder := t.controller.EventRecorder.(*record.FakeRecorder)
+	events := []string{}
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	return events
+}
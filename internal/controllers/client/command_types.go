@@ -65,6 +65,28 @@ type RecordingDescriptor struct {
 	DownloadURL string `json:"downloadUrl"`
 	// URL to the automated analysis report for this recording
 	ReportURL string `json:"reportUrl"`
+	// Additional information attached to the recording by Cryostat
+	Metadata RecordingMetadata `json:"metadata,omitempty"`
+}
+
+// RecordingMetadata contains additional information attached to a
+// flight recording by Cryostat
+type RecordingMetadata struct {
+	// Labels attached to the recording when it was created
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// RecordingOptions contains optional settings to use when creating a
+// new flight recording. Nil fields are left to the JVM's defaults.
+type RecordingOptions struct {
+	// Whether the recording should be buffered to disk in the host containing the JVM
+	ToDisk *bool
+	// The maximum size of the recording file, in bytes
+	MaxSize *int64
+	// The maximum age of recorded events, in seconds
+	MaxAge *int64
+	// Labels to attach to the recording's metadata in Cryostat
+	Labels map[string]string
 }
 
 // SavedRecording represents a recording file that has been archived in
@@ -73,6 +95,51 @@ type SavedRecording struct {
 	Name        string `json:"name"`
 	DownloadURL string `json:"downloadUrl"`
 	ReportURL   string `json:"reportUrl"`
+	// Size of the recording file in bytes, if reported by Cryostat
+	Size int64 `json:"size,omitempty"`
+	// Time when the recording was archived, in milliseconds since Unix epoch,
+	// if reported by Cryostat
+	ArchivedTime int64 `json:"archivedTime,omitempty"`
+	// Additional information attached to the recording by Cryostat
+	Metadata RecordingMetadata `json:"metadata,omitempty"`
+}
+
+// MBeanMetrics contains details of a JVM read by Cryostat from its MBeans
+type MBeanMetrics struct {
+	// Details from the JVM's RuntimeMXBean
+	Runtime RuntimeMetrics `json:"runtime"`
+	// Details from the JVM's MemoryMXBean
+	Memory MemoryMetrics `json:"memory"`
+}
+
+// RuntimeMetrics contains details of a JVM's runtime
+type RuntimeMetrics struct {
+	// Name of the JVM, usually of the form "pid@hostname"
+	Name string `json:"name"`
+	// Vendor of the JVM implementation
+	VMVendor string `json:"vmVendor"`
+	// Version of the JVM implementation
+	VMVersion string `json:"vmVersion"`
+	// How long the JVM has been running, in milliseconds
+	Uptime int64 `json:"uptime"`
+}
+
+// MemoryMetrics contains the memory usage of a JVM
+type MemoryMetrics struct {
+	// Usage of the heap
+	HeapMemoryUsage MemoryUsage `json:"heapMemoryUsage"`
+	// Usage of memory outside of the heap
+	NonHeapMemoryUsage MemoryUsage `json:"nonHeapMemoryUsage"`
+}
+
+// MemoryUsage contains the usage of a pool of memory in a JVM, in bytes
+type MemoryUsage struct {
+	// Memory currently used
+	Used int64 `json:"used"`
+	// Memory guaranteed to be available to the JVM
+	Committed int64 `json:"committed"`
+	// The maximum memory that can be used, or -1 if undefined
+	Max int64 `json:"max"`
 }
 
 // TargetAddress contains an address that Container JFR can use to connect
@@ -37,6 +37,7 @@
 package client
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
@@ -45,6 +46,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -85,15 +87,35 @@ type JMXAuthCredentials struct {
 // REST API
 type CryostatClient interface {
 	ListRecordings(target *TargetAddress) ([]RecordingDescriptor, error)
-	DumpRecording(target *TargetAddress, name string, seconds int, events []string) error
-	StartRecording(target *TargetAddress, name string, events []string) error
+	DumpRecording(target *TargetAddress, name string, seconds int, events []string, options *RecordingOptions) error
+	StartRecording(target *TargetAddress, name string, events []string, options *RecordingOptions) error
 	StopRecording(target *TargetAddress, name string) error
 	DeleteRecording(target *TargetAddress, name string) error
 	SaveRecording(target *TargetAddress, name string) (*string, error)
+	CreateSnapshot(target *TargetAddress) (*string, error)
 	ListSavedRecordings() ([]SavedRecording, error)
 	DeleteSavedRecording(jfrFile string) error
+	UpdateSavedRecordingLabels(jfrFile string, labels map[string]string) error
 	ListEventTypes(target *TargetAddress) ([]operatorv1beta1.EventInfo, error)
 	ListTemplates(target *TargetAddress) ([]operatorv1beta1.TemplateInfo, error)
+	GetMBeanMetrics(target *TargetAddress) (*MBeanMetrics, error)
+	UploadTemplate(filename string, content string) error
+	DeleteTemplate(name string) error
+}
+
+// ErrorResponse is returned when Cryostat responds to a request with a
+// status code outside of the 2xx range
+type ErrorResponse struct {
+	// HTTP status code of the response
+	StatusCode int
+	// HTTP status of the response, such as "404 Not Found"
+	Status string
+	// Error message from the response body
+	Message string
+}
+
+func (e *ErrorResponse) Error() string {
+	return fmt.Sprintf("server returned status: %s", e.Status)
 }
 
 type httpClient struct {
@@ -102,18 +124,32 @@ type httpClient struct {
 }
 
 type apiPath struct {
+	// API version, defaults to "v1"
+	version  string
 	resource string
 	target   *TargetAddress
 	name     *string
+	// Path appended after the resource name
+	subPath *string
 }
 
 const (
 	resRecordings     = "recordings"
 	resEvents         = "events"
 	resTemplates      = "templates"
+	resSnapshot       = "snapshot"
+	resMBeanMetrics   = "mbeanMetrics"
+	apiV1             = "v1"
+	apiBeta           = "beta"
+	subMetadataLabels = "metadata/labels"
 	attrRecordingName = "recordingName"
 	attrEvents        = "events"
 	attrDuration      = "duration"
+	attrToDisk        = "toDisk"
+	attrMaxSize       = "maxSize"
+	attrMaxAge        = "maxAge"
+	attrMetadata      = "metadata"
+	attrTemplate      = "template"
 	cmdStop           = "stop"
 	cmdSave           = "save"
 )
@@ -166,16 +202,25 @@ func (c *httpClient) ListRecordings(target *TargetAddress) ([]RecordingDescripto
 }
 
 // DumpRecording instructs Cryostat to create a new recording of fixed duration
-func (c *httpClient) DumpRecording(target *TargetAddress, name string, seconds int, events []string) error {
-	return c.postRecording(target, name, seconds, events)
+func (c *httpClient) DumpRecording(target *TargetAddress, name string, seconds int, events []string,
+	options *RecordingOptions) error {
+	return c.postRecording(target, name, seconds, events, options)
 }
 
 // StartRecording instructs Cryostat to create a new continuous recording
-func (c *httpClient) StartRecording(target *TargetAddress, name string, events []string) error {
-	return c.postRecording(target, name, 0, events)
+func (c *httpClient) StartRecording(target *TargetAddress, name string, events []string,
+	options *RecordingOptions) error {
+	return c.postRecording(target, name, 0, events, options)
 }
 
-func (c *httpClient) postRecording(target *TargetAddress, name string, seconds int, events []string) error {
+// TemplateEvents returns the events to pass to DumpRecording or StartRecording
+// in order to create a recording from an event template
+func TemplateEvents(name string, templateType operatorv1beta1.TemplateType) []string {
+	return []string{fmt.Sprintf("template=%s,type=%s", name, templateType)}
+}
+
+func (c *httpClient) postRecording(target *TargetAddress, name string, seconds int, events []string,
+	options *RecordingOptions) error {
 	path := &apiPath{
 		resource: resRecordings,
 		target:   target,
@@ -186,6 +231,24 @@ func (c *httpClient) postRecording(target *TargetAddress, name string, seconds i
 	if seconds > 0 {
 		values.Add(attrDuration, strconv.Itoa(seconds))
 	}
+	if options != nil {
+		if options.ToDisk != nil {
+			values.Add(attrToDisk, strconv.FormatBool(*options.ToDisk))
+		}
+		if options.MaxSize != nil {
+			values.Add(attrMaxSize, strconv.FormatInt(*options.MaxSize, 10))
+		}
+		if options.MaxAge != nil {
+			values.Add(attrMaxAge, strconv.FormatInt(*options.MaxAge, 10))
+		}
+		if len(options.Labels) > 0 {
+			metadata, err := json.Marshal(&RecordingMetadata{Labels: options.Labels})
+			if err != nil {
+				return err
+			}
+			values.Add(attrMetadata, string(metadata))
+		}
+	}
 	result := RecordingDescriptor{} // TODO use this in reconciler to avoid get call
 	err := c.httpPostForm(path, values, &result)
 	return err
@@ -223,6 +286,19 @@ func (c *httpClient) SaveRecording(target *TargetAddress, name string) (*string,
 	return &result, err
 }
 
+// CreateSnapshot instructs Cryostat to create a new recording in the target JVM
+// containing the data collected so far by all of its running recordings, and
+// returns the name of the new recording
+func (c *httpClient) CreateSnapshot(target *TargetAddress) (*string, error) {
+	path := &apiPath{
+		resource: resSnapshot,
+		target:   target,
+	}
+	var result string
+	err := c.httpPostForm(path, url.Values{}, &result)
+	return &result, err
+}
+
 // ListSavedRecordings returns a list of recordings contained in persistent storage
 func (c *httpClient) ListSavedRecordings() ([]SavedRecording, error) {
 	path := &apiPath{
@@ -243,6 +319,19 @@ func (c *httpClient) DeleteSavedRecording(jfrFile string) error {
 	return c.httpDelete(path, nil)
 }
 
+// UpdateSavedRecordingLabels replaces the labels of a recording in the
+// persistent storage managed by Cryostat
+func (c *httpClient) UpdateSavedRecordingLabels(jfrFile string, labels map[string]string) error {
+	subPath := subMetadataLabels
+	path := &apiPath{
+		version:  apiBeta,
+		resource: resRecordings,
+		name:     &jfrFile,
+		subPath:  &subPath,
+	}
+	return c.httpPostJSON(path, labels, nil)
+}
+
 // ListEventTypes returns a list of events available in the target JVM
 func (c *httpClient) ListEventTypes(target *TargetAddress) ([]operatorv1beta1.EventInfo, error) {
 	path := &apiPath{
@@ -265,6 +354,40 @@ func (c *httpClient) ListTemplates(target *TargetAddress) ([]operatorv1beta1.Tem
 	return result, err
 }
 
+// UploadTemplate adds a custom event template to Cryostat, which is then
+// available for all target JVMs
+func (c *httpClient) UploadTemplate(filename string, content string) error {
+	path := &apiPath{
+		resource: resTemplates,
+	}
+	return c.httpPostFile(path, attrTemplate, filename, content, nil)
+}
+
+// DeleteTemplate removes a custom event template from Cryostat
+func (c *httpClient) DeleteTemplate(name string) error {
+	escaped := url.PathEscape(name)
+	path := &apiPath{
+		resource: resTemplates,
+		name:     &escaped,
+	}
+	return c.httpDelete(path, nil)
+}
+
+// GetMBeanMetrics returns details of the target JVM read from its MBeans
+func (c *httpClient) GetMBeanMetrics(target *TargetAddress) (*MBeanMetrics, error) {
+	path := &apiPath{
+		version:  apiBeta,
+		resource: resMBeanMetrics,
+		target:   target,
+	}
+	result := &MBeanMetrics{}
+	err := c.httpGet(path, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *httpClient) httpGet(path *apiPath, result interface{}) error {
 	return c.sendRequest(http.MethodGet, path, nil, nil, result)
 }
@@ -281,6 +404,35 @@ func (c *httpClient) httpPostForm(path *apiPath, formData url.Values, result int
 		&contentType, result)
 }
 
+func (c *httpClient) httpPostJSON(path *apiPath, body interface{}, result interface{}) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	contentType := "application/json"
+	return c.sendRequest(http.MethodPost, path, bytes.NewReader(buf), &contentType, result)
+}
+
+func (c *httpClient) httpPostFile(path *apiPath, field string, filename string, content string,
+	result interface{}) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, filename)
+	if err != nil {
+		return err
+	}
+	_, err = part.Write([]byte(content))
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+	contentType := writer.FormDataContentType()
+	return c.sendRequest(http.MethodPost, path, body, &contentType, result)
+}
+
 func (c *httpClient) httpDelete(path *apiPath, result interface{}) error {
 	return c.sendRequest(http.MethodDelete, path, nil, nil, result)
 }
@@ -329,7 +481,11 @@ func (c *httpClient) sendRequest(method string, path *apiPath, body io.Reader, c
 			httpLogger.Error(err, "failed to read error message from response body")
 			return err
 		}
-		err = fmt.Errorf("server returned status: %s", resp.Status)
+		err = &ErrorResponse{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Message:    string(errMsg),
+		}
 		httpLogger.Error(err, "request failed", "message", string(errMsg))
 		return err
 	}
@@ -365,18 +521,25 @@ func decodeResponse(body io.Reader, result interface{}, httpLogger logr.Logger)
 }
 
 func (p *apiPath) URL() (*url.URL, error) {
+	version := p.version
+	if len(version) == 0 {
+		version = apiV1
+	}
 	// Build path based on what fields are defined in the receiver
 	var strPath string
 	if p.target != nil {
 		if p.name != nil {
-			strPath = fmt.Sprintf("/api/v1/targets/%s/%s/%s", url.PathEscape(p.target.String()), p.resource, *p.name)
+			strPath = fmt.Sprintf("/api/%s/targets/%s/%s/%s", version, url.PathEscape(p.target.String()), p.resource, *p.name)
 		} else {
-			strPath = fmt.Sprintf("/api/v1/targets/%s/%s", url.PathEscape(p.target.String()), p.resource)
+			strPath = fmt.Sprintf("/api/%s/targets/%s/%s", version, url.PathEscape(p.target.String()), p.resource)
 		}
 	} else if p.name != nil {
-		strPath = fmt.Sprintf("/api/v1/%s/%s", p.resource, *p.name)
+		strPath = fmt.Sprintf("/api/%s/%s/%s", version, p.resource, *p.name)
 	} else {
-		strPath = fmt.Sprintf("/api/v1/%s", p.resource)
+		strPath = fmt.Sprintf("/api/%s/%s", version, p.resource)
+	}
+	if p.subPath != nil {
+		strPath += "/" + *p.subPath
 	}
 	return url.Parse(strPath)
 }
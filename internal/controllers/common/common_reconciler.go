@@ -41,6 +41,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"time"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
 	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
@@ -72,6 +73,7 @@ type Reconciler interface {
 	FindCryostat(ctx context.Context, namespace string) (*operatorv1beta1.Cryostat, error)
 	GetCryostatClient(ctx context.Context, namespace string, jmxAuth *operatorv1beta1.JMXAuthSecret) (cryostatClient.CryostatClient, error)
 	GetPodTarget(targetPod *corev1.Pod, jmxPort int32) (*cryostatClient.TargetAddress, error)
+	Now() time.Time
 	ReconcilerTLS
 }
 
@@ -168,6 +170,11 @@ func (r *commonReconciler) GetPodTarget(targetPod *corev1.Pod, jmxPort int32) (*
 	}, nil
 }
 
+// Now returns the current time, as reported by the configured OSUtils
+func (r *commonReconciler) Now() time.Time {
+	return r.OS.Now()
+}
+
 func (r *commonReconciler) FindCryostat(ctx context.Context, namespace string) (*operatorv1beta1.Cryostat, error) {
 	// TODO Consider how to find Cryostat object if this operator becomes cluster-scoped
 	// Look up the Cryostat object for this operator, which will help us find its services
@@ -39,6 +39,7 @@ package common
 import (
 	"io/ioutil"
 	"os"
+	"time"
 
 	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
 )
@@ -52,6 +53,7 @@ type CryostatClientFactory interface {
 type OSUtils interface {
 	GetEnv(name string) string
 	GetFileContents(path string) ([]byte, error)
+	Now() time.Time
 }
 
 type defaultClientFactory struct{}
@@ -72,3 +74,8 @@ func (o *defaultOSUtils) GetEnv(name string) string {
 func (o *defaultOSUtils) GetFileContents(path string) ([]byte, error) {
 	return ioutil.ReadFile(path)
 }
+
+// Now returns the current local time
+func (o *defaultOSUtils) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,343 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	cronlib "github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+)
+
+// CronRecordingReconciler reconciles a CronRecording object
+type CronRecordingReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	common.Reconciler
+}
+
+// Reasons used for CronRecording conditions
+const (
+	reasonValidSchedule   = "ValidSchedule"
+	reasonInvalidSchedule = "InvalidSchedule"
+)
+
+// Annotation containing the time a Recording was scheduled for by its CronRecording
+const cronScheduledTimeAnnotation = "operator.cryostat.io/scheduled-at"
+
+// History limits used when not specified in the CronRecording
+const (
+	defaultSuccessfulRecordingsHistoryLimit int32 = 3
+	defaultFailedRecordingsHistoryLimit     int32 = 1
+)
+
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cronrecordings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cronrecordings/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cronrecordings/finalizers,verbs=update
+
+// Reconcile processes a CronRecording and creates Recordings according to its schedule.
+// The Recordings themselves are managed by the RecordingReconciler.
+func (r *CronRecordingReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling CronRecording")
+
+	// Fetch the CronRecording instance
+	instance := &operatorv1beta1.CronRecording{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			reqLogger.Info("CronRecording does not exist")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// Sort the Recordings created by this CronRecording by their progress
+	children, err := r.getChildRecordings(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	active, successful, failed := classifyChildRecordings(children)
+
+	instance.Status.Active = nil
+	for idx := range active {
+		instance.Status.Active = append(instance.Status.Active, recordingReference(&active[idx]))
+	}
+	for idx := range successful {
+		scheduledTime := getScheduledTime(&successful[idx])
+		if instance.Status.LastSuccessfulTime == nil || instance.Status.LastSuccessfulTime.Before(scheduledTime) {
+			instance.Status.LastSuccessfulTime = scheduledTime
+		}
+	}
+
+	// Clean up Recordings beyond the history limits
+	err = r.deleteOldRecordings(ctx, successful,
+		getHistoryLimit(instance.Spec.SuccessfulRecordingsHistoryLimit, defaultSuccessfulRecordingsHistoryLimit))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	err = r.deleteOldRecordings(ctx, failed,
+		getHistoryLimit(instance.Spec.FailedRecordingsHistoryLimit, defaultFailedRecordingsHistoryLimit))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	schedule, err := cronlib.ParseStandard(instance.Spec.Schedule)
+	if err != nil {
+		// Requeuing won't help until the schedule is fixed
+		reqLogger.Error(err, "unable to parse schedule", "schedule", instance.Spec.Schedule)
+		setCronCondition(instance, operatorv1beta1.CronRecordingConditionScheduleValid, metav1.ConditionFalse,
+			reasonInvalidSchedule, fmt.Sprintf("Unable to parse schedule \"%s\": %s", instance.Spec.Schedule, err.Error()))
+		return reconcile.Result{}, r.Client.Status().Update(ctx, instance)
+	}
+	setCronCondition(instance, operatorv1beta1.CronRecordingConditionScheduleValid, metav1.ConditionTrue,
+		reasonValidSchedule, "Schedule is valid")
+
+	now := r.Now()
+	missedRun, nextRun := getScheduledRuns(instance, schedule, now)
+	result := reconcile.Result{RequeueAfter: nextRun.Sub(now)}
+	if missedRun.IsZero() {
+		reqLogger.Info("no scheduled runs are due yet", "next", nextRun)
+		return result, r.Client.Status().Update(ctx, instance)
+	}
+
+	// Check whether Recordings from previous runs are still active
+	if len(active) > 0 {
+		switch instance.Spec.ConcurrencyPolicy {
+		case operatorv1beta1.ForbidConcurrent:
+			reqLogger.Info("concurrency policy blocks concurrent runs, skipping", "active", len(active))
+			return result, r.Client.Status().Update(ctx, instance)
+		case operatorv1beta1.ReplaceConcurrent:
+			for idx := range active {
+				err = r.Client.Delete(ctx, &active[idx])
+				if err != nil && !kerrors.IsNotFound(err) {
+					return reconcile.Result{}, err
+				}
+				reqLogger.Info("deleted active recording to replace it", "recording", active[idx].Name)
+			}
+			instance.Status.Active = nil
+		}
+	}
+
+	// Create a Recording for this run
+	recording, err := r.newRecordingForCron(instance, missedRun)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	err = r.Client.Create(ctx, recording)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		reqLogger.Error(err, "failed to create recording", "recording", recording.Name)
+		return reconcile.Result{}, err
+	}
+	reqLogger.Info("created recording for scheduled run", "recording", recording.Name, "scheduledTime", missedRun)
+
+	instance.Status.Active = append(instance.Status.Active, recordingReference(recording))
+	instance.Status.LastScheduleTime = &metav1.Time{Time: missedRun}
+	err = r.Client.Status().Update(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("CronRecording successfully updated", "Namespace", instance.Namespace, "Name", instance.Name)
+	return result, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CronRecordingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1beta1.CronRecording{}).
+		Owns(&operatorv1beta1.Recording{}).
+		Complete(r)
+}
+
+func (r *CronRecordingReconciler) getChildRecordings(ctx context.Context,
+	cron *operatorv1beta1.CronRecording) ([]operatorv1beta1.Recording, error) {
+	recordings := &operatorv1beta1.RecordingList{}
+	err := r.Client.List(ctx, recordings, client.InNamespace(cron.Namespace),
+		client.MatchingLabels{operatorv1beta1.CronRecordingLabel: recordingOwnerLabelValue(cron.Name)})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ignore any Recordings not controlled by this CronRecording
+	result := []operatorv1beta1.Recording{}
+	for _, recording := range recordings.Items {
+		if metav1.IsControlledBy(&recording, cron) {
+			result = append(result, recording)
+		}
+	}
+	return result, nil
+}
+
+func (r *CronRecordingReconciler) deleteOldRecordings(ctx context.Context, recordings []operatorv1beta1.Recording,
+	limit int32) error {
+	if int32(len(recordings)) <= limit {
+		return nil
+	}
+
+	// Delete the oldest Recordings first
+	sort.Slice(recordings, func(i, j int) bool {
+		return getScheduledTime(&recordings[i]).Before(getScheduledTime(&recordings[j]))
+	})
+	for idx := range recordings[:int32(len(recordings))-limit] {
+		recording := &recordings[idx]
+		if recording.GetDeletionTimestamp() != nil {
+			continue
+		}
+		err := r.Client.Delete(ctx, recording)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+		r.Log.Info("deleted recording beyond history limit", "namespace", recording.Namespace,
+			"name", recording.Name)
+	}
+	return nil
+}
+
+func (r *CronRecordingReconciler) newRecordingForCron(cron *operatorv1beta1.CronRecording,
+	scheduledTime time.Time) (*operatorv1beta1.Recording, error) {
+	recording, err := newRecordingFromTemplate(cron, &cron.Spec.RecordingTemplate, cron.Name,
+		operatorv1beta1.CronRecordingLabel, scheduledTime, r.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	recording.Annotations[cronScheduledTimeAnnotation] = scheduledTime.UTC().Format(time.RFC3339)
+	return recording, nil
+}
+
+// getScheduledRuns returns the most recent scheduled run that has not yet been
+// started, or a zero time if there is none, along with the next scheduled run
+func getScheduledRuns(cron *operatorv1beta1.CronRecording, schedule cronlib.Schedule,
+	now time.Time) (missed time.Time, next time.Time) {
+	start := cron.CreationTimestamp.Time
+	if cron.Status.LastScheduleTime != nil {
+		start = cron.Status.LastScheduleTime.Time
+	}
+	// Only the latest missed run is started, any earlier ones are skipped
+	for run := schedule.Next(start); !run.After(now); run = schedule.Next(run) {
+		missed = run
+	}
+	return missed, schedule.Next(now)
+}
+
+// classifyChildRecordings sorts Recordings by whether they have finished. Recordings
+// only count as failed once they can no longer make progress, since the Failed condition
+// is also set for transient errors that are retried.
+func classifyChildRecordings(recordings []operatorv1beta1.Recording) (active []operatorv1beta1.Recording,
+	successful []operatorv1beta1.Recording, failed []operatorv1beta1.Recording) {
+	for _, recording := range recordings {
+		if isRecordingComplete(&recording) {
+			successful = append(successful, recording)
+		} else if isRecordingFailed(&recording) {
+			failed = append(failed, recording)
+		} else {
+			active = append(active, recording)
+		}
+	}
+	return active, successful, failed
+}
+
+// isRecordingFailed returns whether a Recording that has not completed has failed
+// for good, either because its spec was rejected before it started in the JVM,
+// or because it was stopped without being archived
+func isRecordingFailed(recording *operatorv1beta1.Recording) bool {
+	failed := meta.FindStatusCondition(recording.Status.Conditions, operatorv1beta1.RecordingConditionFailed)
+	if failed == nil || failed.Status != metav1.ConditionTrue {
+		return false
+	}
+	state := recording.Status.State
+	if state == nil {
+		return isInvalidRecordingReason(failed.Reason)
+	}
+	return *state == operatorv1beta1.RecordingStateStopped
+}
+
+func setCronCondition(cron *operatorv1beta1.CronRecording, condType string, status metav1.ConditionStatus,
+	reason string, message string) {
+	meta.SetStatusCondition(&cron.Status.Conditions, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		ObservedGeneration: cron.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+func getScheduledTime(recording *operatorv1beta1.Recording) *metav1.Time {
+	scheduled, err := time.Parse(time.RFC3339, recording.Annotations[cronScheduledTimeAnnotation])
+	if err != nil {
+		// Fall back to when the Recording was created
+		return &recording.CreationTimestamp
+	}
+	return &metav1.Time{Time: scheduled}
+}
+
+func getHistoryLimit(limit *int32, defaultLimit int32) int32 {
+	if limit == nil {
+		return defaultLimit
+	}
+	return *limit
+}
+
+func recordingReference(recording *operatorv1beta1.Recording) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: operatorv1beta1.GroupVersion.String(),
+		Kind:       "Recording",
+		Namespace:  recording.Namespace,
+		Name:       recording.Name,
+		UID:        recording.UID,
+	}
+}
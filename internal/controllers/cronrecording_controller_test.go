@@ -0,0 +1,431 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+)
+
+type cronRecordingTestInput struct {
+	controller *controllers.CronRecordingReconciler
+	objs       []runtime.Object
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("CronRecordingController", func() {
+	var t *cronRecordingTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.controller = &controllers.CronRecordingReconciler{
+			Client:     t.Client,
+			Scheme:     s,
+			Log:        logger,
+			Reconciler: test.NewTestReconciler(&t.TestReconcilerConfig),
+		}
+	})
+
+	BeforeEach(func() {
+		t = &cronRecordingTestInput{
+			objs: []runtime.Object{
+				test.NewCronRecording(),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("before the first scheduled run", func() {
+			BeforeEach(func() {
+				t.setNow(time.Date(2021, time.May, 1, 1, 0, 0, 0, time.UTC))
+			})
+			It("should not create a recording", func() {
+				t.reconcileCronRecording()
+				Expect(t.getChildRecordings()).To(BeEmpty())
+			})
+			It("should requeue at the next scheduled run", func() {
+				result := t.reconcileCronRecording()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Hour}))
+			})
+		})
+		Context("at a scheduled run", func() {
+			BeforeEach(func() {
+				t.setNow(time.Date(2021, time.May, 1, 2, 0, 30, 0, time.UTC))
+			})
+			It("should create a recording from the template", func() {
+				t.reconcileCronRecording()
+				expected := test.NewCronRecordingChild(1, nil, "")
+				recording := t.getRecording(expected.Name)
+				Expect(recording.Labels).To(Equal(expected.Labels))
+				Expect(recording.Annotations).To(Equal(expected.Annotations))
+				Expect(recording.OwnerReferences).To(HaveLen(1))
+				Expect(metav1.IsControlledBy(recording, test.NewCronRecording())).To(BeTrue())
+				Expect(recording.Spec).To(Equal(expected.Spec))
+			})
+			It("should update status", func() {
+				t.reconcileCronRecording()
+				cron := t.getCronRecording()
+				Expect(cron.Status.LastScheduleTime).ToNot(BeNil())
+				Expect(cron.Status.LastScheduleTime.Time).To(BeTemporally("==",
+					time.Date(2021, time.May, 1, 2, 0, 0, 0, time.UTC)))
+				Expect(cron.Status.Active).To(HaveLen(1))
+				Expect(cron.Status.Active[0].Name).To(Equal(test.NewCronRecordingChild(1, nil, "").Name))
+			})
+			It("should requeue at the next scheduled run", func() {
+				result := t.reconcileCronRecording()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 24*time.Hour - 30*time.Second}))
+			})
+			It("should only create one recording when reconciled again", func() {
+				t.reconcileCronRecording()
+				t.reconcileCronRecording()
+				Expect(t.getChildRecordings()).To(HaveLen(1))
+			})
+		})
+		Context("with a long name at a scheduled run", func() {
+			var cron *operatorv1beta1.CronRecording
+			BeforeEach(func() {
+				cron = test.NewCronRecording()
+				cron.Name = strings.Repeat("a", 100)
+				t.objs = []runtime.Object{cron}
+				t.setNow(time.Date(2021, time.May, 1, 2, 0, 30, 0, time.UTC))
+			})
+			It("should create a recording with a valid label", func() {
+				t.reconcileCronRecordingNamed(cron.Name)
+				recordings := &operatorv1beta1.RecordingList{}
+				err := t.Client.List(context.Background(), recordings, client.InNamespace("default"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(recordings.Items).To(HaveLen(1))
+				value := recordings.Items[0].Labels[operatorv1beta1.CronRecordingLabel]
+				Expect(validation.IsValidLabelValue(value)).To(BeEmpty())
+				Expect(value).To(MatchRegexp("^a+-[0-9a-f]{8}$"))
+			})
+			It("should list the recording as active", func() {
+				t.reconcileCronRecordingNamed(cron.Name)
+				Expect(t.getCronRecordingNamed(cron.Name).Status.Active).To(HaveLen(1))
+			})
+		})
+		Context("after missing several scheduled runs", func() {
+			BeforeEach(func() {
+				t.setNow(time.Date(2021, time.May, 3, 12, 0, 0, 0, time.UTC))
+			})
+			It("should only create a recording for the latest run", func() {
+				t.reconcileCronRecording()
+				recordings := t.getChildRecordings()
+				Expect(recordings).To(HaveLen(1))
+				Expect(recordings[0].Name).To(Equal(test.NewCronRecordingChild(3, nil, "").Name))
+			})
+		})
+		Context("with an active recording from a previous run", func() {
+			BeforeEach(func() {
+				running := operatorv1beta1.RecordingStateRunning
+				t.objs = append(t.objs, test.NewCronRecordingChild(1, &running, ""))
+				t.setNow(time.Date(2021, time.May, 2, 2, 0, 0, 0, time.UTC))
+			})
+			Context("and concurrent runs allowed", func() {
+				It("should create a recording", func() {
+					t.reconcileCronRecording()
+					Expect(t.getChildRecordings()).To(HaveLen(2))
+				})
+			})
+			Context("and concurrent runs forbidden", func() {
+				BeforeEach(func() {
+					t.updateCronSpec(func(spec *operatorv1beta1.CronRecordingSpec) {
+						spec.ConcurrencyPolicy = operatorv1beta1.ForbidConcurrent
+					})
+				})
+				It("should not create a recording", func() {
+					t.reconcileCronRecording()
+					recordings := t.getChildRecordings()
+					Expect(recordings).To(HaveLen(1))
+					Expect(recordings[0].Name).To(Equal(test.NewCronRecordingChild(1, nil, "").Name))
+				})
+				It("should list the active recording in status", func() {
+					t.reconcileCronRecording()
+					cron := t.getCronRecording()
+					Expect(cron.Status.Active).To(HaveLen(1))
+					Expect(cron.Status.Active[0].Name).To(Equal(test.NewCronRecordingChild(1, nil, "").Name))
+				})
+			})
+			Context("and concurrent runs replaced", func() {
+				BeforeEach(func() {
+					t.updateCronSpec(func(spec *operatorv1beta1.CronRecordingSpec) {
+						spec.ConcurrencyPolicy = operatorv1beta1.ReplaceConcurrent
+					})
+				})
+				It("should replace the active recording", func() {
+					t.reconcileCronRecording()
+					recordings := t.getChildRecordings()
+					Expect(recordings).To(HaveLen(1))
+					Expect(recordings[0].Name).To(Equal(test.NewCronRecordingChild(2, nil, "").Name))
+				})
+			})
+		})
+		Context("with completed recordings beyond the history limit", func() {
+			BeforeEach(func() {
+				stopped := operatorv1beta1.RecordingStateStopped
+				t.objs = append(t.objs,
+					test.NewCronRecordingChild(1, &stopped, ""),
+					test.NewCronRecordingChild(2, &stopped, ""),
+					test.NewCronRecordingChild(3, &stopped, ""))
+				t.updateCronSpec(func(spec *operatorv1beta1.CronRecordingSpec) {
+					limit := int32(1)
+					spec.SuccessfulRecordingsHistoryLimit = &limit
+				})
+				t.setNow(time.Date(2021, time.May, 3, 12, 0, 0, 0, time.UTC))
+				t.setLastScheduleTime(time.Date(2021, time.May, 3, 2, 0, 0, 0, time.UTC))
+			})
+			It("should delete the oldest recordings", func() {
+				t.reconcileCronRecording()
+				recordings := t.getChildRecordings()
+				Expect(recordings).To(HaveLen(1))
+				Expect(recordings[0].Name).To(Equal(test.NewCronRecordingChild(3, nil, "").Name))
+			})
+			It("should update the last successful time", func() {
+				t.reconcileCronRecording()
+				cron := t.getCronRecording()
+				Expect(cron.Status.LastSuccessfulTime).ToNot(BeNil())
+				Expect(cron.Status.LastSuccessfulTime.Time).To(BeTemporally("==",
+					time.Date(2021, time.May, 3, 2, 0, 0, 0, time.UTC)))
+				Expect(cron.Status.Active).To(BeEmpty())
+			})
+		})
+		Context("with a running recording that reported a transient failure", func() {
+			BeforeEach(func() {
+				running := operatorv1beta1.RecordingStateRunning
+				t.objs = append(t.objs, test.NewCronRecordingChild(1, &running, "ListFailed"))
+				t.updateCronSpec(func(spec *operatorv1beta1.CronRecordingSpec) {
+					limit := int32(0)
+					spec.FailedRecordingsHistoryLimit = &limit
+				})
+				t.setNow(time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC))
+				t.setLastScheduleTime(time.Date(2021, time.May, 1, 2, 0, 0, 0, time.UTC))
+			})
+			It("should keep it as an active recording", func() {
+				t.reconcileCronRecording()
+				Expect(t.getChildRecordings()).To(HaveLen(1))
+				cron := t.getCronRecording()
+				Expect(cron.Status.Active).To(HaveLen(1))
+				Expect(cron.Status.Active[0].Name).To(Equal(test.NewCronRecordingChild(1, nil, "").Name))
+			})
+		})
+		Context("with a recording that is retried after Cryostat was unavailable", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCronRecordingChild(1, nil, "CryostatUnavailable"))
+				t.updateCronSpec(func(spec *operatorv1beta1.CronRecordingSpec) {
+					limit := int32(0)
+					spec.FailedRecordingsHistoryLimit = &limit
+					spec.ConcurrencyPolicy = operatorv1beta1.ForbidConcurrent
+				})
+				t.setNow(time.Date(2021, time.May, 2, 2, 0, 0, 0, time.UTC))
+				t.setLastScheduleTime(time.Date(2021, time.May, 1, 2, 0, 0, 0, time.UTC))
+			})
+			It("should keep it as an active recording", func() {
+				t.reconcileCronRecording()
+				cron := t.getCronRecording()
+				Expect(cron.Status.Active).To(HaveLen(1))
+				Expect(cron.Status.Active[0].Name).To(Equal(test.NewCronRecordingChild(1, nil, "").Name))
+			})
+			It("should not start another run", func() {
+				t.reconcileCronRecording()
+				recordings := t.getChildRecordings()
+				Expect(recordings).To(HaveLen(1))
+				Expect(recordings[0].Name).To(Equal(test.NewCronRecordingChild(1, nil, "").Name))
+			})
+		})
+		Context("with a recording that was rejected as invalid", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCronRecordingChild(1, nil, "RecordingConflict"))
+				t.updateCronSpec(func(spec *operatorv1beta1.CronRecordingSpec) {
+					limit := int32(0)
+					spec.FailedRecordingsHistoryLimit = &limit
+				})
+				t.setNow(time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC))
+				t.setLastScheduleTime(time.Date(2021, time.May, 1, 2, 0, 0, 0, time.UTC))
+			})
+			It("should count it as failed", func() {
+				t.reconcileCronRecording()
+				Expect(t.getChildRecordings()).To(BeEmpty())
+				Expect(t.getCronRecording().Status.Active).To(BeEmpty())
+			})
+		})
+		Context("with a stopped recording that failed to archive", func() {
+			BeforeEach(func() {
+				stopped := operatorv1beta1.RecordingStateStopped
+				child := test.NewCronRecordingChild(1, &stopped, "ArchiveFailed")
+				child.Spec.Archive = true
+				t.objs = append(t.objs, child)
+				t.updateCronSpec(func(spec *operatorv1beta1.CronRecordingSpec) {
+					limit := int32(0)
+					spec.FailedRecordingsHistoryLimit = &limit
+				})
+				t.setNow(time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC))
+				t.setLastScheduleTime(time.Date(2021, time.May, 1, 2, 0, 0, 0, time.UTC))
+			})
+			It("should count it as failed", func() {
+				t.reconcileCronRecording()
+				Expect(t.getChildRecordings()).To(BeEmpty())
+				Expect(t.getCronRecording().Status.Active).To(BeEmpty())
+			})
+		})
+		Context("with failed recordings beyond the history limit", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs,
+					test.NewCronRecordingChild(1, nil, "InvalidSpec"),
+					test.NewCronRecordingChild(2, nil, "InvalidSpec"))
+				t.setNow(time.Date(2021, time.May, 2, 12, 0, 0, 0, time.UTC))
+				t.setLastScheduleTime(time.Date(2021, time.May, 2, 2, 0, 0, 0, time.UTC))
+			})
+			It("should keep only the most recent failure", func() {
+				t.reconcileCronRecording()
+				recordings := t.getChildRecordings()
+				Expect(recordings).To(HaveLen(1))
+				Expect(recordings[0].Name).To(Equal(test.NewCronRecordingChild(2, nil, "").Name))
+			})
+		})
+		Context("with an invalid schedule", func() {
+			BeforeEach(func() {
+				t.updateCronSpec(func(spec *operatorv1beta1.CronRecordingSpec) {
+					spec.Schedule = "not a schedule"
+				})
+				t.setNow(time.Date(2021, time.May, 1, 2, 0, 30, 0, time.UTC))
+			})
+			It("should not create a recording or requeue", func() {
+				result := t.reconcileCronRecording()
+				Expect(result).To(Equal(reconcile.Result{}))
+				Expect(t.getChildRecordings()).To(BeEmpty())
+			})
+			It("should report the schedule is invalid", func() {
+				t.reconcileCronRecording()
+				cron := t.getCronRecording()
+				condition := meta.FindStatusCondition(cron.Status.Conditions,
+					operatorv1beta1.CronRecordingConditionScheduleValid)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.Reason).To(Equal("InvalidSchedule"))
+				Expect(condition.Message).To(ContainSubstring("not a schedule"))
+			})
+		})
+		Context("that no longer exists", func() {
+			BeforeEach(func() {
+				t.objs = nil
+			})
+			It("should do nothing", func() {
+				result := t.reconcileCronRecording()
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+	})
+})
+
+func (t *cronRecordingTestInput) setNow(now time.Time) {
+	t.Now = &now
+}
+
+func (t *cronRecordingTestInput) updateCronSpec(update func(spec *operatorv1beta1.CronRecordingSpec)) {
+	cron := t.objs[0].(*operatorv1beta1.CronRecording)
+	update(&cron.Spec)
+}
+
+func (t *cronRecordingTestInput) setLastScheduleTime(scheduled time.Time) {
+	cron := t.objs[0].(*operatorv1beta1.CronRecording)
+	cron.Status.LastScheduleTime = &metav1.Time{Time: scheduled}
+}
+
+func (t *cronRecordingTestInput) reconcileCronRecording() reconcile.Result {
+	return t.reconcileCronRecordingNamed("nightly")
+}
+
+func (t *cronRecordingTestInput) reconcileCronRecordingNamed(name string) reconcile.Result {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	return result
+}
+
+func (t *cronRecordingTestInput) getCronRecording() *operatorv1beta1.CronRecording {
+	return t.getCronRecordingNamed("nightly")
+}
+
+func (t *cronRecordingTestInput) getCronRecordingNamed(name string) *operatorv1beta1.CronRecording {
+	cron := &operatorv1beta1.CronRecording{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, cron)
+	Expect(err).ToNot(HaveOccurred())
+	return cron
+}
+
+func (t *cronRecordingTestInput) getRecording(name string) *operatorv1beta1.Recording {
+	recording := &operatorv1beta1.Recording{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, recording)
+	Expect(err).ToNot(HaveOccurred())
+	return recording
+}
+
+func (t *cronRecordingTestInput) getChildRecordings() []operatorv1beta1.Recording {
+	recordings := &operatorv1beta1.RecordingList{}
+	err := t.Client.List(context.Background(), recordings, client.InNamespace("default"),
+		client.MatchingLabels{operatorv1beta1.CronRecordingLabel: "nightly"})
+	Expect(err).ToNot(HaveOccurred())
+	return recordings.Items
+}
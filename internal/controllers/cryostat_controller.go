@@ -40,6 +40,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"reflect"
 	"regexp"
 	"strconv"
 	"time"
@@ -67,8 +68,11 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -96,6 +100,23 @@ const datasourceImageTagEnv = "RELATED_IMAGE_DATASOURCE"
 // Environment variable to override the Grafana dashboard image
 const grafanaImageTagEnv = "RELATED_IMAGE_GRAFANA"
 
+// Reasons for the conditions reported in the Cryostat status
+const (
+	reasonCertificatesReady      = "CertificatesReady"
+	reasonWaitingForCertificates = "WaitingForCertificates"
+	reasonTLSSetupFailed         = "TLSSetupFailed"
+	reasonCertManagerDisabled    = "CertManagerDisabled"
+	reasonIngressAvailable       = "IngressAvailable"
+	reasonIngressNotReady        = "IngressNotReady"
+	reasonIngressNotConfigured   = "IngressNotConfigured"
+	reasonIngressFailed          = "IngressFailed"
+	reasonClaimBound             = "ClaimBound"
+	reasonClaimPending           = "ClaimPending"
+	reasonClaimLost              = "ClaimLost"
+	reasonDeploymentPending      = "DeploymentPending"
+	reasonCrashLoopBackOff       = "CrashLoopBackOff"
+)
+
 // Regular expression for the start of a GID range in the OpenShift
 // supplemental groups SCC annotation
 var supGroupRegexp = regexp.MustCompile(`^\d+`)
@@ -181,6 +202,9 @@ func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request
 	if err = r.createObjectIfNotExists(context.Background(), types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, &corev1.PersistentVolumeClaim{}, pvc); err != nil {
 		return reconcile.Result{}, err
 	}
+	if err = r.setStorageCondition(ctx, instance, pvc); err != nil {
+		return reconcile.Result{}, err
+	}
 
 	grafanaSecret := resources.NewGrafanaSecretForCR(instance)
 	if err := controllerutil.SetControllerReference(instance, grafanaSecret, r.Scheme); err != nil {
@@ -205,11 +229,18 @@ func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request
 		tlsConfig, err = r.setupTLS(context.Background(), instance)
 		if err != nil {
 			if err == common.ErrCertNotReady {
-				return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+				setCryostatCondition(instance, operatorv1beta1.CryostatConditionTLSReady, metav1.ConditionFalse,
+					reasonWaitingForCertificates, "Waiting for cert-manager to issue certificates")
+				return reconcile.Result{RequeueAfter: 5 * time.Second}, r.Client.Status().Update(ctx, instance)
 			}
 			reqLogger.Error(err, "Failed to set up TLS for Cryostat")
+			setCryostatCondition(instance, operatorv1beta1.CryostatConditionTLSReady, metav1.ConditionFalse,
+				reasonTLSSetupFailed, err.Error())
+			r.updateStatusOrLog(ctx, instance)
 			return reconcile.Result{}, err
 		}
+		setCryostatCondition(instance, operatorv1beta1.CryostatConditionTLSReady, metav1.ConditionTrue,
+			reasonCertificatesReady, "All certificates have been issued")
 
 		// Get CA certificate from secret and set as destination CA in route
 		caCert, err := r.GetCryostatCABytes(context.Background(), instance)
@@ -220,6 +251,9 @@ func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request
 			Termination:              openshiftv1.TLSTerminationReencrypt,
 			DestinationCACertificate: string(caCert),
 		}
+	} else {
+		setCryostatCondition(instance, operatorv1beta1.CryostatConditionTLSReady, metav1.ConditionFalse,
+			reasonCertManagerDisabled, "TLS between Cryostat components is disabled")
 	}
 
 	// Create RBAC resources for Cryostat
@@ -233,7 +267,7 @@ func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request
 		grafanaSvc := resources.NewGrafanaService(instance)
 		url, err := r.createService(context.Background(), instance, grafanaSvc, &grafanaSvc.Spec.Ports[0], routeTLS)
 		if err != nil {
-			return requeueIfIngressNotReady(reqLogger, err)
+			return r.requeueIfIngressNotReady(ctx, instance, reqLogger, err)
 		}
 		serviceSpecs.GrafanaURL = url
 
@@ -305,17 +339,25 @@ func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request
 	exporterSvc := resources.NewExporterService(instance)
 	url, err := r.createService(context.Background(), instance, exporterSvc, &exporterSvc.Spec.Ports[0], routeTLS)
 	if err != nil {
-		return requeueIfIngressNotReady(reqLogger, err)
+		return r.requeueIfIngressNotReady(ctx, instance, reqLogger, err)
 	}
 	serviceSpecs.CoreURL = url
 
 	cmdChanSvc := resources.NewCommandChannelService(instance)
 	url, err = r.createService(context.Background(), instance, cmdChanSvc, &cmdChanSvc.Spec.Ports[0], routeTLS)
 	if err != nil {
-		return requeueIfIngressNotReady(reqLogger, err)
+		return r.requeueIfIngressNotReady(ctx, instance, reqLogger, err)
 	}
 	serviceSpecs.CommandURL = url
 
+	if serviceSpecs.CoreURL != nil {
+		setCryostatCondition(instance, operatorv1beta1.CryostatConditionIngressReady, metav1.ConditionTrue,
+			reasonIngressAvailable, "Services are exposed outside of the cluster")
+	} else {
+		setCryostatCondition(instance, operatorv1beta1.CryostatConditionIngressReady, metav1.ConditionFalse,
+			reasonIngressNotConfigured, "No ingress configuration was provided for the Cryostat service")
+	}
+
 	imageTags := r.getImageTags()
 	fsGroup, err := r.getFSGroup(ctx, instance.Namespace)
 	if err != nil {
@@ -336,12 +378,17 @@ func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request
 	}
 	reqLogger.Info(fmt.Sprintf("Deployment %s", op))
 
-	if serviceSpecs.CoreURL != nil {
-		instance.Status.ApplicationURL = serviceSpecs.CoreURL.String()
-		err = r.Client.Status().Update(context.Background(), instance)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
+	err = r.setDeploymentCondition(ctx, instance, deployment)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	instance.Status.ApplicationURL = urlOrEmpty(serviceSpecs.CoreURL)
+	instance.Status.GrafanaURL = urlOrEmpty(serviceSpecs.GrafanaURL)
+	instance.Status.CommandURL = urlOrEmpty(serviceSpecs.CommandURL)
+	err = r.Client.Status().Update(context.Background(), instance)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
 	// OpenShift-specific
@@ -375,7 +422,56 @@ func (r *CryostatReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		})
 	}
 
-	return c.Complete(r)
+	return r.watchPods(c).Complete(r)
+}
+
+// watchPods requeues a Cryostat when the containers of its pods change state.
+// A crash-looping container leaves the Deployment unchanged, so it would
+// otherwise go unnoticed until the next unrelated reconcile.
+func (r *CryostatReconciler) watchPods(b *builder.Builder) *builder.Builder {
+	podPredicate := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, okOld := e.ObjectOld.(*corev1.Pod)
+			newPod, okNew := e.ObjectNew.(*corev1.Pod)
+			if !okOld || !okNew || len(getCryostatNameForPod(newPod)) == 0 {
+				return false
+			}
+			return !reflect.DeepEqual(oldPod.Status.ContainerStatuses, newPod.Status.ContainerStatuses)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+
+	mapFunc := func(obj client.Object) []reconcile.Request {
+		name := getCryostatNameForPod(obj)
+		if len(name) == 0 {
+			return nil
+		}
+		return []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}},
+		}
+	}
+
+	return b.Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(mapFunc),
+		builder.WithPredicates(podPredicate))
+}
+
+// getCryostatNameForPod returns the name of the Cryostat whose Deployment
+// created the pod, from the labels of its pod template, or an empty string
+// if the pod does not belong to a Cryostat
+func getCryostatNameForPod(pod client.Object) string {
+	labels := pod.GetLabels()
+	if labels["kind"] != "cryostat" {
+		return ""
+	}
+	return labels["app"]
 }
 
 func (r *CryostatReconciler) createService(ctx context.Context, controller *operatorv1beta1.Cryostat, svc *corev1.Service, exposePort *corev1.ServicePort,
@@ -653,14 +749,107 @@ func getProtocol(tlsConfig *openshiftv1.TLSConfig) string {
 	return "https"
 }
 
-func requeueIfIngressNotReady(log logr.Logger, err error) (reconcile.Result, error) {
+func (r *CryostatReconciler) requeueIfIngressNotReady(ctx context.Context, cr *operatorv1beta1.Cryostat,
+	log logr.Logger, err error) (reconcile.Result, error) {
 	if err == ErrIngressNotReady {
 		log.Info(err.Error())
-		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		setCryostatCondition(cr, operatorv1beta1.CryostatConditionIngressReady, metav1.ConditionFalse,
+			reasonIngressNotReady, err.Error())
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, r.Client.Status().Update(ctx, cr)
 	}
+	setCryostatCondition(cr, operatorv1beta1.CryostatConditionIngressReady, metav1.ConditionFalse,
+		reasonIngressFailed, err.Error())
+	r.updateStatusOrLog(ctx, cr)
 	return reconcile.Result{}, err
 }
 
+func (r *CryostatReconciler) setStorageCondition(ctx context.Context, cr *operatorv1beta1.Cryostat,
+	pvc *corev1.PersistentVolumeClaim) error {
+	found := &corev1.PersistentVolumeClaim{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, found)
+	if err != nil {
+		return err
+	}
+
+	switch found.Status.Phase {
+	case corev1.ClaimBound:
+		setCryostatCondition(cr, operatorv1beta1.CryostatConditionStorageBound, metav1.ConditionTrue,
+			reasonClaimBound, fmt.Sprintf("PersistentVolumeClaim %s is bound to volume %s", found.Name,
+				found.Spec.VolumeName))
+	case corev1.ClaimLost:
+		setCryostatCondition(cr, operatorv1beta1.CryostatConditionStorageBound, metav1.ConditionFalse,
+			reasonClaimLost, fmt.Sprintf("PersistentVolumeClaim %s has lost its volume", found.Name))
+	default:
+		// Claims using a storage class with WaitForFirstConsumer binding
+		// will remain pending until the Cryostat pod is scheduled
+		setCryostatCondition(cr, operatorv1beta1.CryostatConditionStorageBound, metav1.ConditionFalse,
+			reasonClaimPending, fmt.Sprintf("PersistentVolumeClaim %s is waiting to be bound", found.Name))
+	}
+	return nil
+}
+
+func (r *CryostatReconciler) setDeploymentCondition(ctx context.Context, cr *operatorv1beta1.Cryostat,
+	deployment *appsv1.Deployment) error {
+	// A crash-looping container is only reported by the Deployment as a lack
+	// of available replicas, so look at its pods for a more useful reason
+	pods := &corev1.PodList{}
+	err := r.Client.List(ctx, pods, client.InNamespace(deployment.Namespace),
+		client.MatchingLabels(deployment.Spec.Selector.MatchLabels))
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting != nil && status.State.Waiting.Reason == reasonCrashLoopBackOff {
+				setCryostatCondition(cr, operatorv1beta1.CryostatConditionDeploymentAvailable, metav1.ConditionFalse,
+					reasonCrashLoopBackOff, fmt.Sprintf("Container %s in pod %s is crash-looping: %s",
+						status.Name, pod.Name, status.State.Waiting.Message))
+				return nil
+			}
+		}
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			reason := condition.Reason
+			if len(reason) == 0 {
+				reason = reasonDeploymentPending
+			}
+			setCryostatCondition(cr, operatorv1beta1.CryostatConditionDeploymentAvailable,
+				metav1.ConditionStatus(condition.Status), reason, condition.Message)
+			return nil
+		}
+	}
+	setCryostatCondition(cr, operatorv1beta1.CryostatConditionDeploymentAvailable, metav1.ConditionFalse,
+		reasonDeploymentPending, "Waiting for the Deployment to report its availability")
+	return nil
+}
+
+func (r *CryostatReconciler) updateStatusOrLog(ctx context.Context, cr *operatorv1beta1.Cryostat) {
+	err := r.Client.Status().Update(ctx, cr)
+	if err != nil {
+		r.Log.Error(err, "failed to update Cryostat status", "namespace", cr.Namespace, "name", cr.Name)
+	}
+}
+
+func setCryostatCondition(cr *operatorv1beta1.Cryostat, condType string, status metav1.ConditionStatus,
+	reason string, message string) {
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		ObservedGeneration: cr.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+func urlOrEmpty(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
+
 func getNetworkConfig(controller *operatorv1beta1.Cryostat, svc *corev1.Service) (*operatorv1beta1.NetworkConfiguration, error) {
 	if svc.Name == controller.Name {
 		return controller.Spec.NetworkOptions.CoreConfig, nil
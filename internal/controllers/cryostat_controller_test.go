@@ -225,6 +225,121 @@ var _ = Describe("CryostatController", func() {
 				Expect(result).To(Equal(reconcile.Result{}))
 			})
 		})
+		Context("reporting status", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat())
+			})
+			It("should set service URLs in CR Status", func() {
+				t.reconcileCryostatFully()
+
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.GrafanaURL).To(Equal("https://cryostat-grafana.example.com"))
+				Expect(cr.Status.CommandURL).To(Equal("https://cryostat-command.example.com"))
+			})
+			It("should set conditions", func() {
+				t.reconcileCryostatFully()
+
+				cr := t.getCryostatInstance()
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionTLSReady, metav1.ConditionTrue, "CertificatesReady")
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionIngressReady, metav1.ConditionTrue, "IngressAvailable")
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionStorageBound, metav1.ConditionFalse, "ClaimPending")
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionDeploymentAvailable, metav1.ConditionFalse, "DeploymentPending")
+			})
+			It("should set TLSReady to false while waiting for certificates", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+
+				cr := t.getCryostatInstance()
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionTLSReady, metav1.ConditionFalse, "WaitingForCertificates")
+			})
+			It("should set IngressReady to false while waiting for routes", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				_, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				t.makeCertificatesReady()
+				t.initializeSecrets()
+
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+
+				cr := t.getCryostatInstance()
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionTLSReady, metav1.ConditionTrue, "CertificatesReady")
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionIngressReady, metav1.ConditionFalse, "IngressNotReady")
+			})
+			It("should set StorageBound to true once the PVC is bound", func() {
+				t.reconcileCryostatFully()
+
+				pvc := &corev1.PersistentVolumeClaim{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, pvc)
+				Expect(err).ToNot(HaveOccurred())
+				pvc.Status.Phase = corev1.ClaimBound
+				err = t.Client.Status().Update(context.Background(), pvc)
+				Expect(err).ToNot(HaveOccurred())
+
+				t.reconcileCryostatAgain()
+				cr := t.getCryostatInstance()
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionStorageBound, metav1.ConditionTrue, "ClaimBound")
+			})
+			It("should set DeploymentAvailable from the Deployment status", func() {
+				t.reconcileCryostatFully()
+
+				deployment := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deployment)
+				Expect(err).ToNot(HaveOccurred())
+				deployment.Status.Conditions = []appsv1.DeploymentCondition{
+					{
+						Type:    appsv1.DeploymentAvailable,
+						Status:  corev1.ConditionTrue,
+						Reason:  "MinimumReplicasAvailable",
+						Message: "Deployment has minimum availability.",
+					},
+				}
+				err = t.Client.Status().Update(context.Background(), deployment)
+				Expect(err).ToNot(HaveOccurred())
+
+				t.reconcileCryostatAgain()
+				cr := t.getCryostatInstance()
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionDeploymentAvailable, metav1.ConditionTrue, "MinimumReplicasAvailable")
+			})
+			It("should set DeploymentAvailable to false when a container is crash-looping", func() {
+				t.reconcileCryostatFully()
+
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cryostat-abcde",
+						Namespace: "default",
+						Labels: map[string]string{
+							"app":  "cryostat",
+							"kind": "cryostat",
+						},
+					},
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name: "cryostat",
+								State: corev1.ContainerState{
+									Waiting: &corev1.ContainerStateWaiting{
+										Reason:  "CrashLoopBackOff",
+										Message: "back-off 5m0s restarting failed container",
+									},
+								},
+							},
+						},
+					},
+				}
+				err := t.Client.Create(context.Background(), pod)
+				Expect(err).ToNot(HaveOccurred())
+
+				t.reconcileCryostatAgain()
+				cr := t.getCryostatInstance()
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionDeploymentAvailable, metav1.ConditionFalse, "CrashLoopBackOff")
+				condition := meta.FindStatusCondition(cr.Status.Conditions, operatorv1beta1.CryostatConditionDeploymentAvailable)
+				Expect(condition.Message).To(ContainSubstring("cryostat-abcde"))
+			})
+		})
 		Context("Switching from a minimal to a non-minimal deployment", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, test.NewMinimalCryostat())
@@ -526,6 +641,11 @@ var _ = Describe("CryostatController", func() {
 			It("should create routes with edge TLS termination", func() {
 				t.expectRoutes()
 			})
+			It("should set TLSReady to false", func() {
+				t.reconcileCryostatFully()
+				cr := t.getCryostatInstance()
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionTLSReady, metav1.ConditionFalse, "CertManagerDisabled")
+			})
 		})
 		Context("with cert-manager not configured in CR", func() {
 			BeforeEach(func() {
@@ -690,6 +810,12 @@ var _ = Describe("CryostatController", func() {
 				t.expectNoIngresses()
 				t.expectNoRoutes()
 			})
+			It("should set IngressReady to false", func() {
+				t.reconcileCryostatFully()
+				cr := t.getCryostatInstance()
+				expectCryostatCondition(cr, operatorv1beta1.CryostatConditionIngressReady, metav1.ConditionFalse, "IngressNotConfigured")
+				Expect(cr.Status.ApplicationURL).To(BeEmpty())
+			})
 		})
 		Context("networkConfig for one of the services is nil", func() {
 			BeforeEach(func() {
@@ -863,6 +989,13 @@ func (t *cryostatTestInput) reconcileCryostatFully() {
 	Expect(result).To(Equal(reconcile.Result{}))
 }
 
+func (t *cryostatTestInput) reconcileCryostatAgain() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
 func (t *cryostatTestInput) reconcileDeletedCryostat() {
 	// Simulate deletion by setting DeletionTimestamp
 	cr := &operatorv1beta1.Cryostat{}
@@ -1085,6 +1218,21 @@ func (t *cryostatTestInput) expectStatusApplicationURL() {
 	Expect(instance.Status.ApplicationURL).To(Equal("https://cryostat.example.com"))
 }
 
+func (t *cryostatTestInput) getCryostatInstance() *operatorv1beta1.Cryostat {
+	cr := &operatorv1beta1.Cryostat{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, cr)
+	Expect(err).ToNot(HaveOccurred())
+	return cr
+}
+
+func expectCryostatCondition(cr *operatorv1beta1.Cryostat, condType string, status metav1.ConditionStatus, reason string) {
+	condition := meta.FindStatusCondition(cr.Status.Conditions, condType)
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+	Expect(condition.Message).ToNot(BeEmpty())
+}
+
 func (t *cryostatTestInput) expectCommandChannel() {
 	service := &corev1.Service{}
 	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-command", Namespace: "default"}, service)
@@ -0,0 +1,206 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+)
+
+// storeEventCatalog saves the events in a ConfigMap shared with other FlightRecorders
+// whose JVMs have the same events, and refers to it from the status in place of the events
+func (r *FlightRecorderReconciler) storeEventCatalog(ctx context.Context, jfr *operatorv1beta1.FlightRecorder,
+	events []operatorv1beta1.EventInfo) error {
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	hash := fnv.New64a()
+	hash.Write(data)
+	name := fmt.Sprintf("flightrecorder-events-%016x", hash.Sum64())
+
+	// The catalog is immutable and named after its contents, so it only needs to be created once.
+	// It is deleted once no FlightRecorder status refers to it.
+	cm := &corev1.ConfigMap{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: jfr.Namespace, Name: name}, cm)
+	if kerrors.IsNotFound(err) {
+		immutable := true
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: jfr.Namespace,
+				Labels:    map[string]string{operatorv1beta1.EventCatalogLabel: "true"},
+			},
+			Data:      map[string]string{operatorv1beta1.EventCatalogKey: string(data)},
+			Immutable: &immutable,
+		}
+		r.Log.Info("creating event catalog", "name", name, "namespace", jfr.Namespace)
+		err = r.Client.Create(ctx, cm)
+		if kerrors.IsAlreadyExists(err) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Stop using the previous catalog, if the events have changed
+	previous := jfr.Status.EventCatalog
+	if previous != nil && previous.ConfigMapName != name {
+		err = r.releaseEventCatalog(ctx, jfr, previous.ConfigMapName)
+		if err != nil {
+			return err
+		}
+	}
+
+	jfr.Status.Events = nil
+	jfr.Status.EventCatalog = &operatorv1beta1.EventCatalogReference{
+		ConfigMapName: name,
+		EventCount:    int32(len(events)),
+	}
+	return nil
+}
+
+// releaseEventCatalog deletes the event catalog if no FlightRecorder other than
+// this one refers to it
+func (r *FlightRecorderReconciler) releaseEventCatalog(ctx context.Context, jfr *operatorv1beta1.FlightRecorder,
+	name string) error {
+	inUse, err := r.getEventCatalogsInUse(ctx, jfr.Namespace, jfr.Name)
+	if err != nil {
+		return err
+	}
+	if inUse[name] {
+		return nil
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: jfr.Namespace,
+		},
+	}
+	r.Log.Info("deleting unused event catalog", "name", name, "namespace", jfr.Namespace)
+	return client.IgnoreNotFound(r.Client.Delete(ctx, cm))
+}
+
+// deleteUnusedEventCatalogs deletes the event catalogs in the namespace
+// that no FlightRecorder refers to
+func (r *FlightRecorderReconciler) deleteUnusedEventCatalogs(ctx context.Context, namespace string) error {
+	cms := &corev1.ConfigMapList{}
+	err := r.Client.List(ctx, cms, client.InNamespace(namespace),
+		client.MatchingLabels{operatorv1beta1.EventCatalogLabel: "true"})
+	if err != nil || len(cms.Items) == 0 {
+		return err
+	}
+	inUse, err := r.getEventCatalogsInUse(ctx, namespace, "")
+	if err != nil {
+		return err
+	}
+	for idx := range cms.Items {
+		cm := &cms.Items[idx]
+		if inUse[cm.Name] {
+			continue
+		}
+		r.Log.Info("deleting unused event catalog", "name", cm.Name, "namespace", namespace)
+		err = r.Client.Delete(ctx, cm)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// getEventCatalogsInUse returns the names of the event catalogs referred to by
+// the FlightRecorders in the namespace, other than the one named by exclude
+func (r *FlightRecorderReconciler) getEventCatalogsInUse(ctx context.Context, namespace string,
+	exclude string) (map[string]bool, error) {
+	jfrs := &operatorv1beta1.FlightRecorderList{}
+	err := r.Client.List(ctx, jfrs, client.InNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+	inUse := map[string]bool{}
+	for _, jfr := range jfrs.Items {
+		if jfr.Name != exclude && jfr.Status.EventCatalog != nil {
+			inUse[jfr.Status.EventCatalog.ConfigMapName] = true
+		}
+	}
+	return inUse, nil
+}
+
+// isEventCatalog returns whether the object is an event catalog ConfigMap
+func isEventCatalog(obj client.Object) bool {
+	return obj.GetLabels()[operatorv1beta1.EventCatalogLabel] == "true"
+}
+
+// getEventTypes returns the events available in the FlightRecorder's target JVM,
+// reading them from its event catalog if the status refers to one
+func getEventTypes(ctx context.Context, c client.Client,
+	jfr *operatorv1beta1.FlightRecorder) ([]operatorv1beta1.EventInfo, error) {
+	catalog := jfr.Status.EventCatalog
+	if catalog == nil {
+		return jfr.Status.Events, nil
+	}
+	cm := &corev1.ConfigMap{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: jfr.Namespace, Name: catalog.ConfigMapName}, cm)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("event catalog \"%s\" for FlightRecorder \"%s\" does not exist",
+				catalog.ConfigMapName, jfr.Name)
+		}
+		return nil, err
+	}
+	data, ok := cm.Data[operatorv1beta1.EventCatalogKey]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap \"%s\" does not contain an event catalog", catalog.ConfigMapName)
+	}
+	events := []operatorv1beta1.EventInfo{}
+	err = json.Unmarshal([]byte(data), &events)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -38,6 +38,14 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -50,22 +58,58 @@ import (
 	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // FlightRecorderReconciler reconciles a FlightRecorder object
 type FlightRecorderReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+	// How often to delete recordings that the operator created in the target JVM,
+	// once their Recording no longer exists. Orphaned recordings are left alone if zero.
+	OrphanSweepInterval time.Duration
+	// How often to refresh the status from the target JVM. The status is only refreshed
+	// when the FlightRecorder or its target pod changes if zero.
+	ResyncInterval time.Duration
+	// Whether to store the events of each FlightRecorder in a ConfigMap shared by those
+	// with the same events, rather than in its status
+	CompactEventCatalog bool
 	common.Reconciler
 }
 
+// Reasons used for FlightRecorder conditions and Events
+const (
+	reasonConnected            = "Connected"
+	reasonAuthenticationFailed = "AuthenticationFailed"
+	reasonTLSUntrusted         = "TLSUntrusted"
+	reasonUnreachable          = "Unreachable"
+)
+
+// Cryostat responds with this status code when the target JVM requires JMX authentication
+const statusJMXAuthenticationRequired = 427
+
 // +kubebuilder:rbac:namespace=system,groups="",resources=pods;services;secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:namespace=system,groups=cert-manager.io,resources=issuers;certificates,verbs=create;get;list;update;watch
+// +kubebuilder:rbac:namespace=system,groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cryostats;flightrecorders,verbs=*
 // +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=flightrecorders/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=flightrecorders/finalizers,verbs=update
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=recordings,verbs=get;list;watch;create
 
 // Reconcile processes a FlightRecorder CR and retrieves event/template information from Cryostat
 func (r *FlightRecorderReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
@@ -81,12 +125,26 @@ func (r *FlightRecorderReconciler) Reconcile(ctx context.Context, request ctrl.R
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
 			reqLogger.Info("FlightRecorder does not exist")
+			// Event catalogs are shared, so delete any that this FlightRecorder was the last to use
+			err = r.deleteUnusedEventCatalogs(ctx, request.Namespace)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
+	// Check if this FlightRecorder is being deleted
+	if instance.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(instance, flightRecorderFinalizer) {
+			return r.deleteFlightRecorder(ctx, instance)
+		}
+		// Ready for deletion
+		return reconcile.Result{}, nil
+	}
+
 	// Check for a valid target reference
 	targetRef := instance.Status.Target
 	if targetRef == nil {
@@ -117,40 +175,483 @@ func (r *FlightRecorderReconciler) Reconcile(ctx context.Context, request ctrl.R
 		return reconcile.Result{}, err
 	}
 
+	// Add our finalizer while there are custom templates to delete along with the FlightRecorder
+	templateRefs := r.getTemplateRefs(instance, targetPod)
+	err = r.updateTemplateFinalizer(ctx, instance, templateRefs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// A different identity means the status may describe a JVM that is gone
+	identity := getJVMIdentity(targetPod)
+	if instance.Status.JVMIdentity != nil && *instance.Status.JVMIdentity != *identity {
+		reqLogger.Info("target JVM has restarted or moved, refreshing", "name", targetPod.Name,
+			"namespace", targetPod.Namespace, "podIP", identity.PodIP, "restartCount", identity.RestartCount)
+	}
+
 	// Retrieve list of available events
 	reqLogger.Info("Listing event types for pod", "name", targetPod.Name, "namespace", targetPod.Namespace)
 	events, err := cryostat.ListEventTypes(targetAddr)
 	if err != nil {
 		reqLogger.Error(err, "failed to list event types")
-		return reconcile.Result{}, err
+		return r.connectionFailed(ctx, instance, "list event types", err)
 	}
 
-	// Update Status with events
-	instance.Status.Events = events
+	// Update Status with events, or a reference to the shared catalog containing them
+	if r.CompactEventCatalog {
+		err = r.storeEventCatalog(ctx, instance, events)
+		if err != nil {
+			reqLogger.Error(err, "failed to store event catalog")
+			return reconcile.Result{}, err
+		}
+	} else {
+		if instance.Status.EventCatalog != nil {
+			err = r.releaseEventCatalog(ctx, instance, instance.Status.EventCatalog.ConfigMapName)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			instance.Status.EventCatalog = nil
+		}
+		instance.Status.Events = events
+	}
+
+	// Upload custom templates before listing them, so they are included
+	err = r.syncTemplates(ctx, instance, templateRefs, cryostat)
+	if err != nil {
+		reqLogger.Error(err, "failed to upload event templates")
+		updateErr := r.Client.Status().Update(ctx, instance)
+		if updateErr != nil {
+			reqLogger.Error(updateErr, "failed to update FlightRecorder status")
+		}
+		return reconcile.Result{}, err
+	}
 
 	// Retrieve list of available templates
 	reqLogger.Info("Listing templates for pod", "name", targetPod.Name, "namespace", targetPod.Namespace)
 	templates, err := cryostat.ListTemplates(targetAddr)
 	if err != nil {
 		reqLogger.Error(err, "failed to list templates")
-		return reconcile.Result{}, err
+		return r.connectionFailed(ctx, instance, "list templates", err)
 	}
 
 	// Update Status with templates
 	instance.Status.Templates = templates
 
+	// Retrieve details of the JVM itself, which not all versions of Cryostat can provide.
+	// Without them the status is still useful, so this never fails the refresh.
+	reqLogger.Info("Reading JVM details for pod", "name", targetPod.Name, "namespace", targetPod.Namespace)
+	metrics, err := cryostat.GetMBeanMetrics(targetAddr)
+	if err != nil {
+		var errResp *cryostatClient.ErrorResponse
+		if errors.As(err, &errResp) && errResp.StatusCode == http.StatusNotFound {
+			reqLogger.Info("Cryostat does not provide JVM details, omitting them")
+		} else {
+			reqLogger.Error(err, "failed to read JVM details")
+		}
+		instance.Status.JVM = nil
+	} else {
+		instance.Status.JVM = getJVMInfo(metrics, events)
+	}
+
+	// Retrieve list of recordings in the JVM, including those not created by the operator
+	reqLogger.Info("Listing recordings for pod", "name", targetPod.Name, "namespace", targetPod.Namespace)
+	descriptors, err := cryostat.ListRecordings(targetAddr)
+	if err != nil {
+		reqLogger.Error(err, "failed to list recordings")
+		return r.connectionFailed(ctx, instance, "list recordings", err)
+	}
+	sweep := r.orphanSweepDue(instance)
+	recordings, err := r.getJVMRecordings(ctx, instance, cryostat, targetAddr, descriptors, sweep)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Update Status with recordings
+	instance.Status.Recordings = recordings
+
+	r.setConnectedCondition(instance, metav1.ConditionTrue, reasonConnected,
+		fmt.Sprintf("Cryostat connected to the JVM in pod \"%s\"", targetPod.Name))
+
+	// Record when and from which JVM the status was read
+	refreshTime := metav1.NewTime(r.Now())
+	instance.Status.LastRefreshTime = &refreshTime
+	instance.Status.JVMIdentity = identity
+	if sweep {
+		instance.Status.LastOrphanSweepTime = &refreshTime
+	}
+
 	err = r.Client.Status().Update(ctx, instance)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
 	reqLogger.Info("FlightRecorder successfully updated", "Namespace", instance.Namespace, "Name", instance.Name)
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: r.getRequeueInterval()}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *FlightRecorderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Refreshing updates the status, so ignore status changes other than a new target
+	jfrPredicate := predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{},
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldJFR, okOld := e.ObjectOld.(*operatorv1beta1.FlightRecorder)
+				newJFR, okNew := e.ObjectNew.(*operatorv1beta1.FlightRecorder)
+				if !okOld || !okNew {
+					return false
+				}
+				return !reflect.DeepEqual(oldJFR.Status.Target, newJFR.Status.Target) ||
+					oldJFR.Status.Port != newJFR.Status.Port
+			},
+		})
+
+	// Refresh as soon as the target JVM may have restarted or moved, or its templates have changed
+	podPredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, okOld := e.ObjectOld.(*corev1.Pod)
+			newPod, okNew := e.ObjectNew.(*corev1.Pod)
+			if !okOld || !okNew {
+				return false
+			}
+			return *getJVMIdentity(oldPod) != *getJVMIdentity(newPod) ||
+				oldPod.Annotations[operatorv1beta1.EventTemplatesAnnotation] !=
+					newPod.Annotations[operatorv1beta1.EventTemplatesAnnotation]
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+
+	// Only the contents of template ConfigMaps are of interest. Event catalogs are
+	// never templates, and are recreated on the next refresh if deleted.
+	configMapPredicate := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return !isEventCatalog(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCM, okOld := e.ObjectOld.(*corev1.ConfigMap)
+			newCM, okNew := e.ObjectNew.(*corev1.ConfigMap)
+			if !okOld || !okNew || isEventCatalog(newCM) {
+				return false
+			}
+			return !reflect.DeepEqual(oldCM.Data, newCM.Data) || !reflect.DeepEqual(oldCM.BinaryData, newCM.BinaryData)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return !isEventCatalog(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+
+	// FlightRecorders share the name of their target pod
+	mapFunc := func(obj client.Object) []reconcile.Request {
+		return []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}},
+		}
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&operatorv1beta1.FlightRecorder{}).
+		For(&operatorv1beta1.FlightRecorder{}, builder.WithPredicates(jfrPredicate)).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(mapFunc),
+			builder.WithPredicates(podPredicate)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.getFlightRecordersForConfigMap),
+			builder.WithPredicates(configMapPredicate)).
 		Complete(r)
 }
+
+// connectionFailed records why Cryostat could not communicate with the target JVM,
+// and returns the error so that the request is requeued
+func (r *FlightRecorderReconciler) connectionFailed(ctx context.Context, jfr *operatorv1beta1.FlightRecorder,
+	action string, err error) (reconcile.Result, error) {
+	message := fmt.Sprintf("Failed to %s: %s", action, err.Error())
+	var errResp *cryostatClient.ErrorResponse
+	if errors.As(err, &errResp) && len(errResp.Message) > 0 {
+		message += ": " + errResp.Message
+	}
+	r.setConnectedCondition(jfr, metav1.ConditionFalse, getConnectionFailureReason(err), message)
+	updateErr := r.Client.Status().Update(ctx, jfr)
+	if updateErr != nil {
+		r.Log.Error(updateErr, "failed to update FlightRecorder status", "namespace", jfr.Namespace, "name", jfr.Name)
+	}
+	return reconcile.Result{}, err
+}
+
+// setConnectedCondition sets the Connected condition, and emits an Event if
+// the connection has failed or recovered since the last refresh
+func (r *FlightRecorderReconciler) setConnectedCondition(jfr *operatorv1beta1.FlightRecorder,
+	status metav1.ConditionStatus, reason string, message string) {
+	previous := meta.FindStatusCondition(jfr.Status.Conditions, operatorv1beta1.FlightRecorderConditionConnected)
+	changed := previous == nil || previous.Status != status || previous.Reason != reason
+	meta.SetStatusCondition(&jfr.Status.Conditions, metav1.Condition{
+		Type:               operatorv1beta1.FlightRecorderConditionConnected,
+		Status:             status,
+		ObservedGeneration: jfr.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	if !changed {
+		return
+	}
+	if status == metav1.ConditionFalse {
+		r.EventRecorder.Event(jfr, corev1.EventTypeWarning, reason, message)
+	} else if previous != nil {
+		r.EventRecorder.Event(jfr, corev1.EventTypeNormal, reason, message)
+	}
+}
+
+// getConnectionFailureReason classifies an error returned by Cryostat while
+// communicating with the target JVM
+func getConnectionFailureReason(err error) string {
+	var errResp *cryostatClient.ErrorResponse
+	if !errors.As(err, &errResp) {
+		// No response from Cryostat at all
+		return reasonUnreachable
+	}
+	message := strings.ToLower(errResp.Message)
+	switch {
+	case errResp.StatusCode == http.StatusUnauthorized, errResp.StatusCode == http.StatusForbidden,
+		errResp.StatusCode == statusJMXAuthenticationRequired:
+		return reasonAuthenticationFailed
+	case errResp.StatusCode == http.StatusBadGateway, strings.Contains(message, "ssl"),
+		strings.Contains(message, "certificate"):
+		return reasonTLSUntrusted
+	default:
+		return reasonUnreachable
+	}
+}
+
+// getRequeueInterval returns how long to wait before refreshing again, so that
+// both the resync and the orphaned recording sweep happen on time
+func (r *FlightRecorderReconciler) getRequeueInterval() time.Duration {
+	if r.ResyncInterval == 0 || (r.OrphanSweepInterval > 0 && r.OrphanSweepInterval < r.ResyncInterval) {
+		return r.OrphanSweepInterval
+	}
+	return r.ResyncInterval
+}
+
+// orphanSweepDue returns whether orphaned recordings should be deleted from the
+// FlightRecorder's target JVM, which happens at most once per sweep interval
+func (r *FlightRecorderReconciler) orphanSweepDue(jfr *operatorv1beta1.FlightRecorder) bool {
+	if r.OrphanSweepInterval <= 0 {
+		return false
+	}
+	lastSweep := jfr.Status.LastOrphanSweepTime
+	return lastSweep == nil || !r.Now().Before(lastSweep.Add(r.OrphanSweepInterval))
+}
+
+// getJVMInfo converts the JVM details read from Cryostat for the status
+func getJVMInfo(metrics *cryostatClient.MBeanMetrics, events []operatorv1beta1.EventInfo) *operatorv1beta1.JVMInfo {
+	info := &operatorv1beta1.JVMInfo{
+		Vendor:       metrics.Runtime.VMVendor,
+		Version:      metrics.Runtime.VMVersion,
+		Uptime:       metav1.Duration{Duration: time.Duration(metrics.Runtime.Uptime) * time.Millisecond},
+		HeapUsage:    getJVMMemoryUsage(&metrics.Memory.HeapMemoryUsage),
+		NonHeapUsage: getJVMMemoryUsage(&metrics.Memory.NonHeapMemoryUsage),
+		JFRAvailable: len(events) > 0,
+	}
+	// The runtime's name is usually "pid@hostname"
+	pid, err := strconv.ParseInt(strings.SplitN(metrics.Runtime.Name, "@", 2)[0], 10, 64)
+	if err == nil {
+		info.PID = &pid
+	}
+	return info
+}
+
+func getJVMMemoryUsage(usage *cryostatClient.MemoryUsage) operatorv1beta1.JVMMemoryUsage {
+	result := operatorv1beta1.JVMMemoryUsage{
+		Used:      *resource.NewQuantity(usage.Used, resource.BinarySI),
+		Committed: *resource.NewQuantity(usage.Committed, resource.BinarySI),
+	}
+	if usage.Max >= 0 {
+		result.Max = resource.NewQuantity(usage.Max, resource.BinarySI)
+	}
+	return result
+}
+
+// getJVMIdentity returns the identity of the JVM currently running in the pod
+func getJVMIdentity(pod *corev1.Pod) *operatorv1beta1.JVMIdentity {
+	restarts := int32(0)
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return &operatorv1beta1.JVMIdentity{
+		PodIP:        pod.Status.PodIP,
+		RestartCount: restarts,
+	}
+}
+
+// getJVMRecordings converts the recordings found in the target JVM for the Status,
+// matching each to the Recording that manages it. If requested, a Recording is
+// created to adopt any JVM recording not yet managed by one. If sweep is set, recordings
+// whose Recording no longer exists are deleted.
+func (r *FlightRecorderReconciler) getJVMRecordings(ctx context.Context, jfr *operatorv1beta1.FlightRecorder,
+	cryostat cryostatClient.CryostatClient, target *cryostatClient.TargetAddress,
+	descriptors []cryostatClient.RecordingDescriptor, sweep bool) ([]operatorv1beta1.JVMRecordingInfo, error) {
+	existing := &operatorv1beta1.RecordingList{}
+	err := r.Client.List(ctx, existing, client.InNamespace(jfr.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	// Index Recordings by name, and those targeting this FlightRecorder by their JVM recording name
+	byName := map[string]*operatorv1beta1.Recording{}
+	managed := map[string]string{}
+	for idx := range existing.Items {
+		recording := &existing.Items[idx]
+		byName[recording.Name] = recording
+		if recordingTargetsFlightRecorder(recording, jfr.Name) {
+			managed[recording.Spec.Name] = recording.Name
+		}
+	}
+	adopt := jfr.Annotations[operatorv1beta1.AdoptRecordingsAnnotation] == "true"
+
+	recordings := make([]operatorv1beta1.JVMRecordingInfo, 0, len(descriptors))
+	for _, descriptor := range descriptors {
+		state, err := validateRecordingState(descriptor.State)
+		if err != nil {
+			return nil, err
+		}
+		info := operatorv1beta1.JVMRecordingInfo{
+			Name:      descriptor.Name,
+			State:     *state,
+			StartTime: metav1.Unix(0, descriptor.StartTime*int64(time.Millisecond)),
+			Duration: metav1.Duration{
+				Duration: time.Duration(descriptor.Duration) * time.Millisecond,
+			},
+		}
+
+		owner, created := descriptor.Metadata.Labels[operatorv1beta1.RecordingOwnerLabel]
+		if created && isOrphanedRecording(&descriptor, byName[owner]) {
+			// The operator created this recording, but its Recording is gone
+			if sweep {
+				r.Log.Info("deleting orphaned recording from target JVM", "name", descriptor.Name, "recording", owner,
+					"namespace", jfr.Namespace)
+				err = cryostat.DeleteRecording(target, descriptor.Name)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+		} else if created {
+			info.Recording = owner
+		} else if len(managed[descriptor.Name]) > 0 {
+			info.Recording = managed[descriptor.Name]
+		} else if _, captured := descriptor.Metadata.Labels[operatorv1beta1.IncidentCaptureLabel]; adopt && !captured {
+			// Recordings of an IncidentCapture are managed by it instead
+			recording, err := r.adoptRecording(ctx, jfr, &descriptor)
+			if err != nil {
+				return nil, err
+			}
+			info.Recording = recording.Name
+		}
+		recordings = append(recordings, info)
+	}
+	return recordings, nil
+}
+
+func recordingTargetsFlightRecorder(recording *operatorv1beta1.Recording, jfrName string) bool {
+	if recording.Spec.FlightRecorder != nil {
+		return recording.Spec.FlightRecorder.Name == jfrName
+	}
+	for _, target := range recording.Status.Targets {
+		if target.FlightRecorder == jfrName {
+			return true
+		}
+	}
+	return false
+}
+
+// isOrphanedRecording returns whether a recording the operator created in a target JVM
+// no longer belongs to a Recording, given the Recording named in its labels, if any
+func isOrphanedRecording(descriptor *cryostatClient.RecordingDescriptor, owner *operatorv1beta1.Recording) bool {
+	return owner == nil || owner.Spec.Name != descriptor.Name
+}
+
+// adoptRecording creates a Recording for a recording in the target JVM. Different JVM
+// recording names may map to the same object name, so if a Recording with that name
+// exists for a different recording, a name with a hash of the JVM recording name is used.
+func (r *FlightRecorderReconciler) adoptRecording(ctx context.Context, jfr *operatorv1beta1.FlightRecorder,
+	descriptor *cryostatClient.RecordingDescriptor) (*operatorv1beta1.Recording, error) {
+	names := []string{
+		adoptedRecordingName(jfr.Name, descriptor.Name, false),
+		adoptedRecordingName(jfr.Name, descriptor.Name, true),
+	}
+	for _, name := range names {
+		recording := newAdoptedRecording(jfr, descriptor, name)
+		r.Log.Info("adopting recording from target JVM", "name", descriptor.Name, "recording", recording.Name,
+			"namespace", recording.Namespace)
+		err := r.Client.Create(ctx, recording)
+		if err == nil {
+			return recording, nil
+		} else if !kerrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+
+		// Check whether the existing Recording already adopted this recording
+		existing := &operatorv1beta1.Recording{}
+		err = r.Client.Get(ctx, types.NamespacedName{Namespace: recording.Namespace, Name: recording.Name}, existing)
+		if err != nil {
+			return nil, err
+		}
+		if existing.Spec.Name == descriptor.Name && recordingTargetsFlightRecorder(existing, jfr.Name) {
+			return existing, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to adopt recording \"%s\", Recordings named %s already exist for other recordings",
+		descriptor.Name, strings.Join(names, " and "))
+}
+
+func newAdoptedRecording(jfr *operatorv1beta1.FlightRecorder, descriptor *cryostatClient.RecordingDescriptor,
+	name string) *operatorv1beta1.Recording {
+	return &operatorv1beta1.Recording{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: jfr.Namespace,
+			Labels: map[string]string{
+				operatorv1beta1.RecordingLabel: jfr.Name,
+			},
+			Annotations: map[string]string{
+				operatorv1beta1.RecordingAdoptedAnnotation: "true",
+			},
+		},
+		Spec: operatorv1beta1.RecordingSpec{
+			Name: descriptor.Name,
+			Duration: metav1.Duration{
+				Duration: time.Duration(descriptor.Duration) * time.Millisecond,
+			},
+			FlightRecorder: &corev1.LocalObjectReference{
+				Name: jfr.Name,
+			},
+		},
+	}
+}
+
+var invalidNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// adoptedRecordingName derives a valid object name from a FlightRecorder name
+// and the name of a recording in its JVM, which may contain any characters.
+// If unique is set, a hash of the recording name is appended, to tell apart
+// recording names that only differ in characters that are not allowed.
+func adoptedRecordingName(jfrName string, recordingName string, unique bool) string {
+	suffix := invalidNameChars.ReplaceAllString(strings.ToLower(recordingName), "-")
+	name := strings.Trim(fmt.Sprintf("%s-%s", jfrName, suffix), "-")
+	maxLen := 253
+	hash := ""
+	if unique {
+		h := fnv.New32a()
+		h.Write([]byte(recordingName))
+		hash = fmt.Sprintf("-%08x", h.Sum32())
+		maxLen -= len(hash)
+	}
+	if len(name) > maxLen {
+		name = strings.TrimRight(name[:maxLen], "-")
+	}
+	return name + hash
+}
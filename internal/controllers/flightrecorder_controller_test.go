@@ -38,13 +38,19 @@ package controllers_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -56,9 +62,12 @@ import (
 )
 
 type flightRecorderTestInput struct {
-	controller *controllers.FlightRecorderReconciler
-	objs       []runtime.Object
-	handlers   []http.HandlerFunc
+	controller     *controllers.FlightRecorderReconciler
+	objs           []runtime.Object
+	handlers       []http.HandlerFunc
+	sweepInterval  time.Duration
+	resyncInterval time.Duration
+	compactEvents  bool
 	test.TestReconcilerConfig
 }
 
@@ -73,10 +82,14 @@ var _ = Describe("FlightRecorderController", func() {
 		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
 		t.Server = test.NewServer(t.Client, t.handlers, t.TLS)
 		t.controller = &controllers.FlightRecorderReconciler{
-			Client:     t.Client,
-			Scheme:     s,
-			Log:        logger,
-			Reconciler: test.NewTestReconciler(&t.TestReconcilerConfig),
+			Client:              t.Client,
+			Scheme:              s,
+			Log:                 logger,
+			EventRecorder:       record.NewFakeRecorder(1024),
+			OrphanSweepInterval: t.sweepInterval,
+			ResyncInterval:      t.resyncInterval,
+			CompactEventCatalog: t.compactEvents,
+			Reconciler:          test.NewTestReconciler(&t.TestReconcilerConfig),
 		}
 	})
 
@@ -108,22 +121,697 @@ var _ = Describe("FlightRecorderController", func() {
 				t.handlers = []http.HandlerFunc{
 					test.NewListEventTypesHandler(),
 					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
 				}
 			})
 			It("should update event type list", func() {
 				t.expectFlightRecorderReconcileSuccess()
 			})
+			It("should list recordings in the JVM", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				t.expectJVMRecordings(obj, test.NewJVMRecordings(""))
+			})
+			It("should not adopt recordings", func() {
+				t.reconcileFlightRecorderAndGet()
+				t.expectRecordingCount(0)
+			})
+			It("should publish details of the JVM", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				Expect(obj.Status.JVM).ToNot(BeNil())
+				expected := test.NewJVMInfo()
+				Expect(obj.Status.JVM.Vendor).To(Equal(expected.Vendor))
+				Expect(obj.Status.JVM.Version).To(Equal(expected.Version))
+				Expect(obj.Status.JVM.PID).To(Equal(expected.PID))
+				Expect(obj.Status.JVM.Uptime).To(Equal(expected.Uptime))
+				expectMemoryUsage(obj.Status.JVM.HeapUsage, expected.HeapUsage)
+				expectMemoryUsage(obj.Status.JVM.NonHeapUsage, expected.NonHeapUsage)
+				Expect(obj.Status.JVM.JFRAvailable).To(BeTrue())
+			})
+			Context("when Cryostat does not provide JVM details", func() {
+				BeforeEach(func() {
+					t.handlers[2] = test.NewMBeanMetricsNotFoundHandler()
+				})
+				It("should still update the FlightRecorder without them", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Status.JVM).To(BeNil())
+					Expect(obj.Status.Events).To(Equal(test.NewEventTypes()))
+				})
+				It("should report the target is connected without an Event", func() {
+					t.reconcileFlightRecorderAndGet()
+					t.expectConnectedCondition(metav1.ConditionTrue, "Connected")
+					Expect(t.getEvents()).To(BeEmpty())
+				})
+			})
+			Context("when the JVM details cannot be read", func() {
+				BeforeEach(func() {
+					t.handlers[2] = test.NewMBeanMetricsFailHandler()
+				})
+				It("should still update the FlightRecorder without them", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Status.JVM).To(BeNil())
+					Expect(obj.Status.Events).To(Equal(test.NewEventTypes()))
+					t.expectConnectedCondition(metav1.ConditionTrue, "Connected")
+				})
+			})
+			It("should report the target is connected without an Event", func() {
+				t.reconcileFlightRecorderAndGet()
+				t.expectConnectedCondition(metav1.ConditionTrue, "Connected")
+				Expect(t.getEvents()).To(BeEmpty())
+			})
+			It("should record when and from which JVM the status was read", func() {
+				now := time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC)
+				t.Now = &now
+				obj := t.reconcileFlightRecorderAndGet()
+				Expect(obj.Status.LastRefreshTime).ToNot(BeNil())
+				Expect(obj.Status.LastRefreshTime.Time).To(BeTemporally("==", now))
+				Expect(obj.Status.JVMIdentity).To(Equal(&operatorv1beta1.JVMIdentity{
+					PodIP: "1.2.3.4",
+				}))
+			})
+			Context("with a resync interval", func() {
+				BeforeEach(func() {
+					t.resyncInterval = 5 * time.Minute
+				})
+				It("should requeue after the resync interval", func() {
+					t.expectFlightRecorderRequeue(5 * time.Minute)
+				})
+				Context("longer than the sweep interval", func() {
+					BeforeEach(func() {
+						t.sweepInterval = 2 * time.Minute
+					})
+					It("should requeue after the sweep interval", func() {
+						t.expectFlightRecorderRequeue(2 * time.Minute)
+					})
+				})
+			})
+			Context("after the target JVM has restarted", func() {
+				BeforeEach(func() {
+					jfr := test.NewFlightRecorder()
+					jfr.Status.JVMIdentity = &operatorv1beta1.JVMIdentity{
+						PodIP: "1.2.3.4",
+					}
+					t.objs = []runtime.Object{
+						test.NewCryostat(), test.NewCACert(), jfr, test.NewRestartedTargetPod(2),
+						test.NewCryostatService(), test.NewJMXAuthSecret(),
+					}
+				})
+				It("should record the new JVM identity", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Status.JVMIdentity).To(Equal(&operatorv1beta1.JVMIdentity{
+						PodIP:        "1.2.3.4",
+						RestartCount: 2,
+					}))
+					Expect(obj.Status.Events).To(Equal(test.NewEventTypes()))
+				})
+			})
+		})
+		Context("with a Recording managing the JVM recording", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRunningRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should reference the Recording", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				t.expectJVMRecordings(obj, test.NewJVMRecordings("my-recording"))
+			})
+		})
+		Context("with adoption requested", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewAdoptingFlightRecorder(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should create a Recording for the JVM recording", func() {
+				t.reconcileFlightRecorderAndGet()
+				expected := test.NewAdoptedRecording()
+				recording := &operatorv1beta1.Recording{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, recording)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(recording.Labels).To(Equal(expected.Labels))
+				Expect(recording.Annotations).To(Equal(expected.Annotations))
+				Expect(recording.Spec).To(Equal(expected.Spec))
+			})
+			It("should reference the new Recording", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				t.expectJVMRecordings(obj, test.NewJVMRecordings("test-pod-test-recording"))
+			})
+			Context("when the JVM recording name is not a valid object name", func() {
+				BeforeEach(func() {
+					descriptors := test.NewRecordingDescriptors("RUNNING", 30000)
+					descriptors[0].Name = "My Recording #2"
+					t.handlers = []http.HandlerFunc{
+						test.NewListEventTypesHandler(),
+						test.NewListTemplatesHandler(),
+						test.NewMBeanMetricsHandler(),
+						test.NewListHandler(descriptors),
+					}
+				})
+				It("should create a Recording with a valid name", func() {
+					t.reconcileFlightRecorderAndGet()
+					recording := &operatorv1beta1.Recording{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-pod-my-recording-2", Namespace: "default"}, recording)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(recording.Spec.Name).To(Equal("My Recording #2"))
+				})
+			})
+			Context("with a Recording of the same name for another JVM recording", func() {
+				BeforeEach(func() {
+					other := test.NewAdoptedRecording()
+					other.Spec.Name = "Test Recording"
+					t.objs = append(t.objs, other)
+				})
+				It("should create a Recording with a hash suffix", func() {
+					t.reconcileFlightRecorderAndGet()
+					recording := &operatorv1beta1.Recording{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-pod-test-recording-e5da9633", Namespace: "default"}, recording)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(recording.Spec.Name).To(Equal("test-recording"))
+				})
+				It("should reference the new Recording", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					t.expectJVMRecordings(obj, test.NewJVMRecordings("test-pod-test-recording-e5da9633"))
+				})
+				It("should not modify the existing Recording", func() {
+					t.reconcileFlightRecorderAndGet()
+					recording := &operatorv1beta1.Recording{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-pod-test-recording", Namespace: "default"}, recording)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(recording.Spec.Name).To(Equal("Test Recording"))
+				})
+			})
+			Context("with a JVM recording created by an IncidentCapture", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListEventTypesHandler(),
+						test.NewListTemplatesHandler(),
+						test.NewMBeanMetricsHandler(),
+						test.NewListHandler(test.NewIncidentDescriptors("RUNNING")),
+					}
+				})
+				It("should not adopt it", func() {
+					t.reconcileFlightRecorderAndGet()
+					t.expectRecordingCount(0)
+				})
+			})
+			Context("with a Recording managing the JVM recording", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewRunningRecording())
+				})
+				It("should not create another Recording", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					t.expectJVMRecordings(obj, test.NewJVMRecordings("my-recording"))
+					t.expectRecordingCount(1)
+				})
+			})
+			Context("after the JVM recording was already adopted", func() {
+				BeforeEach(func() {
+					t.handlers = append(t.handlers,
+						test.NewListEventTypesHandler(),
+						test.NewListTemplatesHandler(),
+						test.NewMBeanMetricsHandler(),
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+					)
+				})
+				It("should not create another Recording", func() {
+					t.reconcileFlightRecorderAndGet()
+					obj := t.reconcileFlightRecorderAndGet()
+					t.expectJVMRecordings(obj, test.NewJVMRecordings("test-pod-test-recording"))
+					t.expectRecordingCount(1)
+				})
+			})
+		})
+		Context("with a recording created by the operator", func() {
+			BeforeEach(func() {
+				t.sweepInterval = 10 * time.Minute
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewOwnedRecordingDescriptors("RUNNING", 30000, "my-recording")),
+				}
+			})
+			Context("whose Recording exists", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewRunningRecording())
+				})
+				It("should reference the Recording", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					t.expectJVMRecordings(obj, test.NewJVMRecordings("my-recording"))
+				})
+			})
+			Context("whose Recording was deleted", func() {
+				BeforeEach(func() {
+					t.handlers = append(t.handlers, test.NewDeleteHandler())
+				})
+				It("should delete the recording from the JVM", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Status.Recordings).To(BeEmpty())
+					Expect(obj.Status.LastOrphanSweepTime).ToNot(BeNil())
+				})
+				It("should requeue after the sweep interval", func() {
+					req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pod", Namespace: "default"}}
+					result, err := t.controller.Reconcile(context.Background(), req)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Minute}))
+				})
+			})
+			Context("whose Recording was deleted after the last sweep", func() {
+				var now time.Time
+				BeforeEach(func() {
+					now = time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC)
+					t.Now = &now
+					jfr := test.NewFlightRecorder()
+					lastSweep := metav1.NewTime(now.Add(-time.Minute))
+					jfr.Status.LastOrphanSweepTime = &lastSweep
+					t.objs = []runtime.Object{
+						test.NewCryostat(), test.NewCACert(), jfr, test.NewTargetPod(),
+						test.NewCryostatService(), test.NewJMXAuthSecret(),
+					}
+				})
+				It("should wait for the sweep interval before deleting it", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					t.expectJVMRecordings(obj, test.NewJVMRecordings(""))
+					Expect(obj.Status.LastOrphanSweepTime.Time).To(BeTemporally("==", now.Add(-time.Minute)))
+				})
+				Context("once the sweep interval has passed", func() {
+					BeforeEach(func() {
+						now = now.Add(10 * time.Minute)
+						t.handlers = append(t.handlers, test.NewDeleteHandler())
+					})
+					It("should delete the recording from the JVM", func() {
+						obj := t.reconcileFlightRecorderAndGet()
+						Expect(obj.Status.Recordings).To(BeEmpty())
+					})
+					It("should record when it swept", func() {
+						obj := t.reconcileFlightRecorderAndGet()
+						Expect(obj.Status.LastOrphanSweepTime).ToNot(BeNil())
+						Expect(obj.Status.LastOrphanSweepTime.Time).To(BeTemporally("==", now))
+					})
+				})
+			})
+			Context("whose Recording was deleted with sweeping disabled", func() {
+				BeforeEach(func() {
+					t.sweepInterval = 0
+				})
+				It("should only list the recording", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					t.expectJVMRecordings(obj, test.NewJVMRecordings(""))
+					Expect(obj.Status.LastOrphanSweepTime).To(BeNil())
+				})
+			})
+			Context("whose Recording was deleted with adoption requested", func() {
+				BeforeEach(func() {
+					t.objs = []runtime.Object{
+						test.NewCryostat(), test.NewCACert(), test.NewAdoptingFlightRecorder(), test.NewTargetPod(),
+						test.NewCryostatService(), test.NewJMXAuthSecret(),
+					}
+					t.sweepInterval = 0
+				})
+				It("should not adopt the recording", func() {
+					t.reconcileFlightRecorderAndGet()
+					t.expectRecordingCount(0)
+				})
+			})
+		})
+		Context("with custom event templates", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithEventTemplates(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewCustomTemplateConfigMap(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewUploadTemplateHandler(),
+					test.NewListTemplatesWithCustomHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should upload the template to Cryostat", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				t.expectUploadedTemplate(obj)
+			})
+			It("should add a finalizer", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				Expect(obj.Finalizers).To(ConsistOf("operator.cryostat.io/flightrecorder.finalizer"))
+			})
+			It("should list the uploaded template", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				Expect(obj.Status.Templates).To(Equal(test.NewTemplatesWithCustom()))
+			})
+			Context("after the template was already uploaded", func() {
+				BeforeEach(func() {
+					t.handlers = append(t.handlers,
+						test.NewListEventTypesHandler(),
+						test.NewListTemplatesWithCustomHandler(),
+						test.NewMBeanMetricsHandler(),
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+					)
+				})
+				It("should not upload it again", func() {
+					before := t.reconcileFlightRecorderAndGet()
+					after := t.reconcileFlightRecorderAndGet()
+					Expect(after.Status.UploadedTemplates).To(Equal(before.Status.UploadedTemplates))
+				})
+			})
+			Context("after the ConfigMap has changed", func() {
+				BeforeEach(func() {
+					recorder := test.NewFlightRecorderWithEventTemplates()
+					recorder.Status.UploadedTemplates = []operatorv1beta1.UploadedTemplate{test.NewUploadedTemplate("stale")}
+					t.objs[2] = recorder
+					t.handlers = append([]http.HandlerFunc{
+						test.NewListEventTypesHandler(),
+						test.NewUploadTemplateExistsHandler(),
+						test.NewDeleteTemplateHandler(),
+					}, t.handlers[1:]...)
+				})
+				It("should replace the template", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					t.expectUploadedTemplate(obj)
+					Expect(obj.Status.UploadedTemplates[0].Hash).ToNot(Equal("stale"))
+				})
+			})
+			Context("when the ConfigMap does not exist", func() {
+				BeforeEach(func() {
+					t.objs = t.objs[:len(t.objs)-1]
+					t.handlers = []http.HandlerFunc{
+						test.NewListEventTypesHandler(),
+						test.NewListTemplatesHandler(),
+						test.NewMBeanMetricsHandler(),
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+					}
+				})
+				It("should emit a warning Event", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Status.UploadedTemplates).To(BeEmpty())
+					Expect(t.getEvents()).To(ConsistOf("Warning TemplateInvalid ConfigMap \"custom-template\" does not exist"))
+				})
+			})
+			Context("when Cryostat rejects the template", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListEventTypesHandler(),
+						test.NewUploadTemplateFailHandler(),
+						test.NewDeleteTemplateNotFoundHandler(),
+						test.NewListTemplatesHandler(),
+						test.NewMBeanMetricsHandler(),
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+					}
+				})
+				It("should emit a warning Event", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Status.UploadedTemplates).To(BeEmpty())
+					Expect(t.getEvents()).To(ConsistOf("Warning TemplateUploadFailed Cryostat rejected template " +
+						"\"Custom Profiling\" from ConfigMap \"custom-template\": Invalid XML"))
+				})
+			})
+			Context("when another FlightRecorder uploaded a different template with the same name", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewOtherFlightRecorderWithUploadedTemplate("other-template", "other"))
+					t.handlers = []http.HandlerFunc{
+						test.NewListEventTypesHandler(),
+						test.NewListTemplatesHandler(),
+						test.NewMBeanMetricsHandler(),
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+					}
+				})
+				It("should not replace the template", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Status.UploadedTemplates).To(BeEmpty())
+					Expect(t.getEvents()).To(ConsistOf("Warning TemplateConflict Template \"Custom Profiling\" from " +
+						"ConfigMap \"custom-template\" conflicts with a different template of the same name from " +
+						"ConfigMap \"other-template\""))
+				})
+			})
+		})
+		Context("with custom event templates listed on the target pod", func() {
+			BeforeEach(func() {
+				pod := test.NewTargetPod()
+				pod.Annotations = map[string]string{
+					"operator.cryostat.io/event-templates": "custom-template/custom.jfc, not-a-reference",
+				}
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorder(), pod,
+					test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewCustomTemplateConfigMap(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewUploadTemplateHandler(),
+					test.NewListTemplatesWithCustomHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should upload the template to Cryostat", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				t.expectUploadedTemplate(obj)
+			})
+			It("should emit a warning Event for invalid entries", func() {
+				t.reconcileFlightRecorderAndGet()
+				Expect(t.getEvents()).To(ConsistOf("Warning TemplateInvalid \"not-a-reference\" in annotation " +
+					"operator.cryostat.io/event-templates of pod \"test-pod\" is not of the form <ConfigMap name>/<filename>"))
+			})
+		})
+		Context("that is deleted after uploading a template", func() {
+			BeforeEach(func() {
+				recorder := test.NewFlightRecorderWithUploadedTemplate("abc")
+				recorder.Finalizers = []string{"operator.cryostat.io/flightrecorder.finalizer"}
+				now := metav1.Now()
+				recorder.DeletionTimestamp = &now
+				t.objs[2] = recorder
+				t.handlers = []http.HandlerFunc{
+					test.NewDeleteTemplateHandler(),
+				}
+			})
+			It("should delete the template and the finalizer", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				Expect(obj.Finalizers).To(BeEmpty())
+			})
+			Context("that another FlightRecorder still uses", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewOtherFlightRecorderWithUploadedTemplate("custom-template", "abc"))
+					t.handlers = nil
+				})
+				It("should only delete the finalizer", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Finalizers).To(BeEmpty())
+				})
+			})
+			Context("after Cryostat was deleted", func() {
+				BeforeEach(func() {
+					t.objs = t.objs[1:]
+					t.handlers = nil
+				})
+				It("should only delete the finalizer", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Finalizers).To(BeEmpty())
+				})
+			})
+			Context("with a Cryostat only in another namespace", func() {
+				BeforeEach(func() {
+					other := test.NewCryostat()
+					other.Namespace = "other"
+					t.objs[0] = other
+					t.handlers = nil
+				})
+				It("should only delete the finalizer", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Finalizers).To(BeEmpty())
+				})
+			})
+			Context("when deleting the template fails", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewDeleteTemplateFailHandler(),
+					}
+				})
+				It("should keep the finalizer", func() {
+					t.expectFlightRecorderReconcileError()
+					obj := &operatorv1beta1.FlightRecorder{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-pod", Namespace: "default"}, obj)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(obj.Finalizers).To(ConsistOf("operator.cryostat.io/flightrecorder.finalizer"))
+				})
+			})
+			Context("after the finalizer timeout has elapsed", func() {
+				BeforeEach(func() {
+					t.objs[0] = test.NewCryostatWithFinalizerTimeout(10 * time.Minute)
+					recorder := t.objs[2].(*operatorv1beta1.FlightRecorder)
+					deleted := metav1.NewTime(time.Now().Add(-time.Hour))
+					recorder.DeletionTimestamp = &deleted
+					t.handlers = nil
+				})
+				It("should delete the finalizer without deleting the template", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Finalizers).To(BeEmpty())
+				})
+				It("should emit an Event naming the template", func() {
+					t.reconcileFlightRecorderAndGet()
+					Expect(t.getEvents()).To(ConsistOf("Warning CleanupAbandoned Deleting without cleaning up in Cryostat, " +
+						"because cleanup did not succeed within 10m0s. These may have been left behind: " +
+						"event template \"Custom Profiling\""))
+				})
+			})
+			Context("with deletion forced", func() {
+				BeforeEach(func() {
+					recorder := t.objs[2].(*operatorv1beta1.FlightRecorder)
+					recorder.Annotations = map[string]string{operatorv1beta1.RecordingForceDeleteAnnotation: "true"}
+					t.handlers = nil
+				})
+				It("should delete the finalizer without deleting the template", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Finalizers).To(BeEmpty())
+					Expect(t.getEvents()).To(HaveLen(1))
+				})
+			})
+		})
+		Context("with an uploaded template that is no longer referenced", func() {
+			BeforeEach(func() {
+				t.objs[2] = test.NewFlightRecorderWithUploadedTemplate("abc")
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewDeleteTemplateHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should delete the template from Cryostat", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				Expect(obj.Status.UploadedTemplates).To(BeEmpty())
+			})
+			Context("that another FlightRecorder still uses", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewOtherFlightRecorderWithUploadedTemplate("custom-template", "abc"))
+					t.handlers = append(t.handlers[:1], t.handlers[2:]...)
+				})
+				It("should not delete the template from Cryostat", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Status.UploadedTemplates).To(BeEmpty())
+				})
+			})
+		})
+		Context("with a compact event catalog", func() {
+			BeforeEach(func() {
+				t.compactEvents = true
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should refer to the event catalog instead of listing events", func() {
+				obj := t.reconcileFlightRecorderAndGet()
+				Expect(obj.Status.Events).To(BeEmpty())
+				Expect(obj.Status.EventCatalog).To(Equal(&operatorv1beta1.EventCatalogReference{
+					ConfigMapName: test.NewEventCatalogName(),
+					EventCount:    int32(len(test.NewEventTypes())),
+				}))
+			})
+			It("should store the events in the event catalog", func() {
+				t.reconcileFlightRecorderAndGet()
+				cm := t.getEventCatalog(test.NewEventCatalogName())
+				Expect(cm.Labels).To(HaveKeyWithValue(operatorv1beta1.EventCatalogLabel, "true"))
+				Expect(cm.Immutable).ToNot(BeNil())
+				Expect(*cm.Immutable).To(BeTrue())
+				Expect(cm.Data).To(HaveKey(operatorv1beta1.EventCatalogKey))
+				events := []operatorv1beta1.EventInfo{}
+				err := json.Unmarshal([]byte(cm.Data[operatorv1beta1.EventCatalogKey]), &events)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(events).To(Equal(test.NewEventTypes()))
+				Expect(cm.OwnerReferences).To(BeEmpty())
+			})
+			Context("shared with another FlightRecorder", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewEventCatalogConfigMap(),
+						test.NewOtherFlightRecorderWithEventCatalog(test.NewEventCatalogName()))
+				})
+				It("should refer to the existing event catalog", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Status.EventCatalog.ConfigMapName).To(Equal(test.NewEventCatalogName()))
+				})
+				It("should not update the event catalog", func() {
+					before := t.getEventCatalog(test.NewEventCatalogName())
+					t.reconcileFlightRecorderAndGet()
+					cm := t.getEventCatalog(test.NewEventCatalogName())
+					Expect(cm.ResourceVersion).To(Equal(before.ResourceVersion))
+					Expect(cm.OwnerReferences).To(BeEmpty())
+				})
+			})
+			Context("after the events have changed", func() {
+				BeforeEach(func() {
+					t.objs[2] = test.NewFlightRecorderWithOldEventCatalog()
+					t.objs = append(t.objs, test.NewOldEventCatalogConfigMap())
+				})
+				It("should delete the previous event catalog", func() {
+					obj := t.reconcileFlightRecorderAndGet()
+					Expect(obj.Status.EventCatalog.ConfigMapName).To(Equal(test.NewEventCatalogName()))
+					t.expectNoEventCatalog("flightrecorder-events-old")
+				})
+				Context("when another FlightRecorder uses the previous event catalog", func() {
+					BeforeEach(func() {
+						t.objs = append(t.objs, test.NewOtherFlightRecorderWithEventCatalog("flightrecorder-events-old"))
+					})
+					It("should keep the previous event catalog", func() {
+						t.reconcileFlightRecorderAndGet()
+						t.getEventCatalog("flightrecorder-events-old")
+					})
+				})
+			})
+		})
+		Context("with an event catalog after compact mode is disabled", func() {
+			BeforeEach(func() {
+				t.objs[2] = test.NewFlightRecorderWithEventCatalog()
+				t.objs = append(t.objs, test.NewEventCatalogConfigMap())
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should list events in the status again", func() {
+				t.expectFlightRecorderReconcileSuccess()
+				obj := &operatorv1beta1.FlightRecorder{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-pod", Namespace: "default"}, obj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(obj.Status.EventCatalog).To(BeNil())
+			})
+			It("should delete the event catalog", func() {
+				t.expectFlightRecorderReconcileSuccess()
+				t.expectNoEventCatalog(test.NewEventCatalogName())
+			})
 		})
 		Context("after FlightRecorder already reconciled successfully", func() {
 			BeforeEach(func() {
 				t.handlers = []http.HandlerFunc{
 					test.NewListEventTypesHandler(),
 					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
 					test.NewListEventTypesHandler(),
 					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
 				}
 			})
 			It("should be idempotent", func() {
+				now := time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC)
+				t.Now = &now
 				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pod", Namespace: "default"}}
 				result, err := t.controller.Reconcile(context.Background(), req)
 				Expect(err).ToNot(HaveOccurred())
@@ -152,6 +840,23 @@ var _ = Describe("FlightRecorderController", func() {
 				Expect(err).ToNot(HaveOccurred())
 				Expect(result).To(Equal(reconcile.Result{}))
 			})
+			Context("with event catalogs", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewEventCatalogConfigMap(), test.NewOldEventCatalogConfigMap(),
+						test.NewOtherFlightRecorderWithEventCatalog("flightrecorder-events-old"))
+				})
+				JustBeforeEach(func() {
+					req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "does-not-exist", Namespace: "default"}}
+					_, err := t.controller.Reconcile(context.Background(), req)
+					Expect(err).ToNot(HaveOccurred())
+				})
+				It("should delete event catalogs no longer in use", func() {
+					t.expectNoEventCatalog(test.NewEventCatalogName())
+				})
+				It("should keep event catalogs still in use", func() {
+					t.getEventCatalog("flightrecorder-events-old")
+				})
+			})
 		})
 		Context("FlightRecorder Status not updated yet", func() {
 			BeforeEach(func() {
@@ -178,6 +883,64 @@ var _ = Describe("FlightRecorderController", func() {
 			It("should requeue with error", func() {
 				t.expectFlightRecorderReconcileError()
 			})
+			It("should report an authentication failure", func() {
+				t.expectFlightRecorderReconcileError()
+				t.expectConnectedCondition(metav1.ConditionFalse, "AuthenticationFailed")
+				Expect(t.getEvents()).To(ConsistOf("Warning AuthenticationFailed Failed to list event types: " +
+					"server returned status: 401 Unauthorized"))
+			})
+			Context("after it already failed", func() {
+				BeforeEach(func() {
+					t.handlers = append(t.handlers, test.NewListEventTypesFailHandler())
+				})
+				It("should not emit another Event", func() {
+					t.expectFlightRecorderReconcileError()
+					t.expectFlightRecorderReconcileError()
+					Expect(t.getEvents()).To(HaveLen(1))
+				})
+			})
+			Context("and then succeeds", func() {
+				BeforeEach(func() {
+					t.handlers = append(t.handlers,
+						test.NewListEventTypesHandler(),
+						test.NewListTemplatesHandler(),
+						test.NewMBeanMetricsHandler(),
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+					)
+				})
+				It("should report the connection has recovered", func() {
+					t.expectFlightRecorderReconcileError()
+					t.reconcileFlightRecorderAndGet()
+					t.expectConnectedCondition(metav1.ConditionTrue, "Connected")
+					events := t.getEvents()
+					Expect(events).To(HaveLen(2))
+					Expect(events[1]).To(Equal("Normal Connected Cryostat connected to the JVM in pod \"test-pod\""))
+				})
+			})
+		})
+		Context("list-event-types command fails with an untrusted certificate", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesErrorHandler(http.StatusBadGateway, "Target SSL Untrusted"),
+				}
+			})
+			It("should report the certificate is untrusted", func() {
+				t.expectFlightRecorderReconcileError()
+				t.expectConnectedCondition(metav1.ConditionFalse, "TLSUntrusted")
+				Expect(t.getEvents()).To(ConsistOf("Warning TLSUntrusted Failed to list event types: " +
+					"server returned status: 502 Bad Gateway: Target SSL Untrusted"))
+			})
+		})
+		Context("list-event-types command fails with an unreachable target", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesErrorHandler(http.StatusInternalServerError, "Connection refused"),
+				}
+			})
+			It("should report the target is unreachable", func() {
+				t.expectFlightRecorderReconcileError()
+				t.expectConnectedCondition(metav1.ConditionFalse, "Unreachable")
+			})
 		})
 		Context("list-templates command fails", func() {
 			BeforeEach(func() {
@@ -190,6 +953,19 @@ var _ = Describe("FlightRecorderController", func() {
 				t.expectFlightRecorderReconcileError()
 			})
 		})
+		Context("list-recordings command fails", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListFailHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should requeue with error", func() {
+				t.expectFlightRecorderReconcileError()
+			})
+		})
 		Context("Cryostat CR is missing", func() {
 			BeforeEach(func() {
 				t.objs = []runtime.Object{
@@ -245,6 +1021,8 @@ var _ = Describe("FlightRecorderController", func() {
 				t.handlers = []http.HandlerFunc{
 					test.NewListEventTypesNoJMXAuthHandler(),
 					test.NewListTemplatesNoJMXAuthHandler(),
+					test.NewMBeanMetricsNoJMXAuthHandler(),
+					test.NewListNoJMXAuthHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
 				}
 			})
 			It("should update event type list and template list", func() {
@@ -289,6 +1067,8 @@ var _ = Describe("FlightRecorderController", func() {
 				t.handlers = []http.HandlerFunc{
 					test.NewListEventTypesHandler(),
 					test.NewListTemplatesHandler(),
+					test.NewMBeanMetricsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
 				}
 				disableTLS := true
 				t.EnvDisableTLS = &disableTLS
@@ -313,9 +1093,97 @@ func (t *flightRecorderTestInput) expectFlightRecorderReconcileSuccess() {
 	Expect(obj.Status.Templates).To(Equal(test.NewTemplates()))
 }
 
+func (t *flightRecorderTestInput) expectFlightRecorderRequeue(after time.Duration) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pod", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{RequeueAfter: after}))
+}
+
 func (t *flightRecorderTestInput) expectFlightRecorderReconcileError() {
 	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pod", Namespace: "default"}}
 	result, err := t.controller.Reconcile(context.Background(), req)
 	Expect(err).To(HaveOccurred())
 	Expect(result).To(Equal(reconcile.Result{}))
 }
+
+func (t *flightRecorderTestInput) reconcileFlightRecorderAndGet() *operatorv1beta1.FlightRecorder {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pod", Namespace: "default"}}
+	_, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+
+	obj := &operatorv1beta1.FlightRecorder{}
+	err = t.Client.Get(context.Background(), req.NamespacedName, obj)
+	Expect(err).ToNot(HaveOccurred())
+	return obj
+}
+
+func (t *flightRecorderTestInput) expectConnectedCondition(status metav1.ConditionStatus, reason string) {
+	obj := &operatorv1beta1.FlightRecorder{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-pod", Namespace: "default"}, obj)
+	Expect(err).ToNot(HaveOccurred())
+	condition := meta.FindStatusCondition(obj.Status.Conditions, operatorv1beta1.FlightRecorderConditionConnected)
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
+
+func (t *flightRecorderTestInput) expectUploadedTemplate(obj *operatorv1beta1.FlightRecorder) {
+	Expect(obj.Status.UploadedTemplates).To(HaveLen(1))
+	template := obj.Status.UploadedTemplates[0]
+	Expect(template.Name).To(Equal("Custom Profiling"))
+	Expect(template.ConfigMapName).To(Equal("custom-template"))
+	Expect(template.Filename).To(Equal("custom.jfc"))
+	Expect(template.Hash).ToNot(BeEmpty())
+}
+
+func (t *flightRecorderTestInput) getEventCatalog(name string) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, cm)
+	Expect(err).ToNot(HaveOccurred())
+	return cm
+}
+
+func (t *flightRecorderTestInput) expectNoEventCatalog(name string) {
+	cm := &corev1.ConfigMap{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, cm)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
+func expectMemoryUsage(usage operatorv1beta1.JVMMemoryUsage, expected operatorv1beta1.JVMMemoryUsage) {
+	Expect(usage.Used.Cmp(expected.Used)).To(BeZero())
+	Expect(usage.Committed.Cmp(expected.Committed)).To(BeZero())
+	if expected.Max == nil {
+		Expect(usage.Max).To(BeNil())
+	} else {
+		Expect(usage.Max).ToNot(BeNil())
+		Expect(usage.Max.Cmp(*expected.Max)).To(BeZero())
+	}
+}
+
+func (t *flightRecorderTestInput) getEvents() []string {
+	recorder := t.controller.EventRecorder.(*record.FakeRecorder)
+	events := []string{}
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	return events
+}
+
+func (t *flightRecorderTestInput) expectJVMRecordings(obj *operatorv1beta1.FlightRecorder,
+	expected []operatorv1beta1.JVMRecordingInfo) {
+	Expect(obj.Status.Recordings).To(HaveLen(len(expected)))
+	for i, recording := range obj.Status.Recordings {
+		// Converted to RFC3339 during serialization (sub-second precision lost)
+		Expect(recording.StartTime.Time).To(BeTemporally("~", expected[i].StartTime.Time, time.Second))
+		recording.StartTime = expected[i].StartTime
+		Expect(recording).To(Equal(expected[i]))
+	}
+}
+
+func (t *flightRecorderTestInput) expectRecordingCount(count int) {
+	recordings := &operatorv1beta1.RecordingList{}
+	err := t.Client.List(context.Background(), recordings)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(recordings.Items).To(HaveLen(count))
+}
@@ -0,0 +1,465 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+)
+
+// Reasons used for Events about custom event templates
+const (
+	eventTemplateInvalid      = "TemplateInvalid"
+	eventTemplateConflict     = "TemplateConflict"
+	eventTemplateUploadFailed = "TemplateUploadFailed"
+)
+
+// templateFile is the root element of a JFR event template (.jfc) file
+type templateFile struct {
+	Label string `xml:"label,attr"`
+}
+
+// desiredTemplate is a template read from a ConfigMap referenced by a FlightRecorder
+type desiredTemplate struct {
+	operatorv1beta1.UploadedTemplate
+	content string
+}
+
+// Finalizer used to delete the custom event templates a FlightRecorder uploaded
+const flightRecorderFinalizer = "operator.cryostat.io/flightrecorder.finalizer"
+
+// syncTemplates uploads the custom event templates referenced by the FlightRecorder
+// to Cryostat, replacing those whose ConfigMap has changed and deleting those no
+// longer referenced. The status records the templates uploaded, even on error.
+func (r *FlightRecorderReconciler) syncTemplates(ctx context.Context, jfr *operatorv1beta1.FlightRecorder,
+	refs []operatorv1beta1.TemplateConfigMap, cryostat cryostatClient.CryostatClient) error {
+	desired, err := r.getDesiredTemplates(ctx, jfr, refs)
+	if err != nil {
+		return err
+	}
+	others, err := r.getTemplatesUploadedByOthers(ctx, jfr)
+	if err != nil {
+		return err
+	}
+
+	tracked := map[string]operatorv1beta1.UploadedTemplate{}
+	for _, template := range jfr.Status.UploadedTemplates {
+		tracked[template.Name] = template
+	}
+	defer func() {
+		jfr.Status.UploadedTemplates = sortTemplates(tracked)
+	}()
+
+	// Delete templates no longer referenced, unless another FlightRecorder still uses them
+	for _, name := range templateNames(tracked) {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+		if _, shared := others[name]; !shared {
+			r.Log.Info("deleting event template", "name", name, "namespace", jfr.Namespace)
+			err := deleteTemplate(cryostat, name)
+			if err != nil {
+				return err
+			}
+		}
+		delete(tracked, name)
+	}
+
+	// Upload templates that are new or whose file has changed
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		template := desired[name]
+		current, ok := tracked[name]
+		if ok && current.Hash == template.Hash {
+			continue
+		}
+		if other, shared := others[name]; shared {
+			if other.Hash == template.Hash {
+				// Already uploaded by another FlightRecorder
+				tracked[name] = template.UploadedTemplate
+				continue
+			}
+			if other.ConfigMapName != template.ConfigMapName || other.Filename != template.Filename {
+				r.EventRecorder.Eventf(jfr, corev1.EventTypeWarning, eventTemplateConflict,
+					"Template \"%s\" from ConfigMap \"%s\" conflicts with a different template of the same name from ConfigMap \"%s\"",
+					name, template.ConfigMapName, other.ConfigMapName)
+				delete(tracked, name)
+				continue
+			}
+		}
+
+		r.Log.Info("uploading event template", "name", name, "namespace", jfr.Namespace)
+		errResp, err := r.uploadTemplate(cryostat, template)
+		if err != nil {
+			return err
+		}
+		if errResp != nil {
+			// Cryostat rejected the file, so retrying will not help until the ConfigMap changes
+			r.EventRecorder.Eventf(jfr, corev1.EventTypeWarning, eventTemplateUploadFailed,
+				"Cryostat rejected template \"%s\" from ConfigMap \"%s\": %s", name, template.ConfigMapName,
+				errResp.Message)
+			delete(tracked, name)
+			continue
+		}
+		tracked[name] = template.UploadedTemplate
+	}
+	return nil
+}
+
+// uploadTemplate uploads a template to Cryostat. Cryostat cannot replace a template in
+// place, so if the upload is rejected and a template of the same name exists, that
+// template is deleted and the upload retried. If the file itself is rejected, the
+// error response is returned instead of an error.
+func (r *FlightRecorderReconciler) uploadTemplate(cryostat cryostatClient.CryostatClient,
+	template *desiredTemplate) (*cryostatClient.ErrorResponse, error) {
+	err := cryostat.UploadTemplate(template.Filename, template.content)
+	errResp := getBadRequest(err)
+	if errResp == nil {
+		return nil, err
+	}
+	existed, err := removeTemplate(cryostat, template.Name)
+	if err != nil || !existed {
+		return errResp, err
+	}
+	r.Log.Info("replacing existing event template", "name", template.Name)
+	err = cryostat.UploadTemplate(template.Filename, template.content)
+	errResp = getBadRequest(err)
+	if errResp != nil {
+		return errResp, nil
+	}
+	return nil, err
+}
+
+// getTemplateRefs returns the custom event templates to upload for the FlightRecorder,
+// from both its spec and the annotation on its target pod
+func (r *FlightRecorderReconciler) getTemplateRefs(jfr *operatorv1beta1.FlightRecorder,
+	pod *corev1.Pod) []operatorv1beta1.TemplateConfigMap {
+	refs := append([]operatorv1beta1.TemplateConfigMap{}, jfr.Spec.EventTemplates...)
+	annotation, ok := pod.Annotations[operatorv1beta1.EventTemplatesAnnotation]
+	if !ok {
+		return refs
+	}
+	for _, entry := range strings.Split(annotation, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		parts := strings.SplitN(entry, "/", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			r.EventRecorder.Eventf(jfr, corev1.EventTypeWarning, eventTemplateInvalid,
+				"\"%s\" in annotation %s of pod \"%s\" is not of the form <ConfigMap name>/<filename>",
+				entry, operatorv1beta1.EventTemplatesAnnotation, pod.Name)
+			continue
+		}
+		ref := operatorv1beta1.TemplateConfigMap{ConfigMapName: parts[0], Filename: parts[1]}
+		if !containsTemplateRef(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// updateTemplateFinalizer adds the finalizer while the FlightRecorder may have templates
+// to delete, and removes it once it has none. It must be called before modifying the
+// status, since updating the FlightRecorder replaces it.
+func (r *FlightRecorderReconciler) updateTemplateFinalizer(ctx context.Context, jfr *operatorv1beta1.FlightRecorder,
+	refs []operatorv1beta1.TemplateConfigMap) error {
+	needed := len(refs) > 0 || len(jfr.Status.UploadedTemplates) > 0
+	present := controllerutil.ContainsFinalizer(jfr, flightRecorderFinalizer)
+	if needed && !present {
+		return common.AddFinalizer(ctx, r.Client, jfr, flightRecorderFinalizer)
+	} else if !needed && present {
+		return common.RemoveFinalizer(ctx, r.Client, jfr, flightRecorderFinalizer)
+	}
+	return nil
+}
+
+// deleteFlightRecorder deletes the templates uploaded by a FlightRecorder that is being
+// deleted from Cryostat, unless another FlightRecorder still uses them, and then allows
+// the FlightRecorder to be deleted
+func (r *FlightRecorderReconciler) deleteFlightRecorder(ctx context.Context,
+	jfr *operatorv1beta1.FlightRecorder) (reconcile.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", jfr.Namespace, "Request.Name", jfr.Name)
+	cryostats := &operatorv1beta1.CryostatList{}
+	err := r.Client.List(ctx, cryostats, client.InNamespace(jfr.Namespace))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(cryostats.Items) > 0 && len(jfr.Status.UploadedTemplates) > 0 {
+		// Stop trying to clean up in Cryostat once forced to, or the finalizer has timed out
+		cause := getFinalizerAbandonCause(jfr, &cryostats.Items[0], r.Now())
+		if cause != nil {
+			return r.abandonTemplateCleanup(ctx, jfr, *cause)
+		}
+		// Templates are not specific to a target, so no JMX credentials are needed
+		cryostat, err := r.GetCryostatClient(ctx, jfr.Namespace, nil)
+		if err != nil {
+			if err == common.ErrCertNotReady {
+				reqLogger.Info("Waiting for CA certificate")
+				return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+			}
+			return reconcile.Result{}, err
+		}
+		others, err := r.getTemplatesUploadedByOthers(ctx, jfr)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		for _, template := range jfr.Status.UploadedTemplates {
+			if _, shared := others[template.Name]; shared {
+				continue
+			}
+			reqLogger.Info("deleting event template", "name", template.Name)
+			_, err = removeTemplate(cryostat, template.Name)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	}
+
+	err = common.RemoveFinalizer(ctx, r.Client, jfr, flightRecorderFinalizer)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	reqLogger.Info("FlightRecorder successfully deleted")
+	return reconcile.Result{}, nil
+}
+
+// abandonTemplateCleanup removes the finalizer from a FlightRecorder being deleted
+// without deleting its templates from Cryostat, and emits a Warning Event naming them
+func (r *FlightRecorderReconciler) abandonTemplateCleanup(ctx context.Context, jfr *operatorv1beta1.FlightRecorder,
+	cause string) (reconcile.Result, error) {
+	leaked := make([]string, 0, len(jfr.Status.UploadedTemplates))
+	for _, template := range jfr.Status.UploadedTemplates {
+		leaked = append(leaked, fmt.Sprintf("event template \"%s\"", template.Name))
+	}
+	r.Log.Info("giving up on deleting event templates from Cryostat", "namespace", jfr.Namespace,
+		"name", jfr.Name, "cause", cause, "leaked", leaked)
+	emitCleanupAbandoned(r.EventRecorder, jfr, cause, strings.Join(leaked, ", "))
+
+	err := common.RemoveFinalizer(ctx, r.Client, jfr, flightRecorderFinalizer)
+	return reconcile.Result{}, err
+}
+
+// getDesiredTemplates reads each template referenced by the FlightRecorder, indexed
+// by template name. Templates that cannot be read are skipped with a warning Event.
+func (r *FlightRecorderReconciler) getDesiredTemplates(ctx context.Context, jfr *operatorv1beta1.FlightRecorder,
+	refs []operatorv1beta1.TemplateConfigMap) (map[string]*desiredTemplate, error) {
+	desired := map[string]*desiredTemplate{}
+	for _, ref := range refs {
+		cm := &corev1.ConfigMap{}
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: jfr.Namespace, Name: ref.ConfigMapName}, cm)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				r.EventRecorder.Eventf(jfr, corev1.EventTypeWarning, eventTemplateInvalid,
+					"ConfigMap \"%s\" does not exist", ref.ConfigMapName)
+				continue
+			}
+			return nil, err
+		}
+		content, ok := cm.Data[ref.Filename]
+		if !ok {
+			r.EventRecorder.Eventf(jfr, corev1.EventTypeWarning, eventTemplateInvalid,
+				"ConfigMap \"%s\" does not contain \"%s\"", ref.ConfigMapName, ref.Filename)
+			continue
+		}
+		template := &templateFile{}
+		err = xml.Unmarshal([]byte(content), template)
+		if err != nil || len(template.Label) == 0 {
+			r.EventRecorder.Eventf(jfr, corev1.EventTypeWarning, eventTemplateInvalid,
+				"\"%s\" in ConfigMap \"%s\" is not an event template with a label", ref.Filename, ref.ConfigMapName)
+			continue
+		}
+		if _, ok := desired[template.Label]; ok {
+			r.EventRecorder.Eventf(jfr, corev1.EventTypeWarning, eventTemplateConflict,
+				"Template \"%s\" is defined more than once, ignoring \"%s\" in ConfigMap \"%s\"",
+				template.Label, ref.Filename, ref.ConfigMapName)
+			continue
+		}
+		hash := fnv.New64a()
+		hash.Write([]byte(content))
+		desired[template.Label] = &desiredTemplate{
+			UploadedTemplate: operatorv1beta1.UploadedTemplate{
+				Name:          template.Label,
+				ConfigMapName: ref.ConfigMapName,
+				Filename:      ref.Filename,
+				Hash:          fmt.Sprintf("%016x", hash.Sum64()),
+			},
+			content: content,
+		}
+	}
+	return desired, nil
+}
+
+// getTemplatesUploadedByOthers returns the templates uploaded by other FlightRecorders
+// in the same namespace, indexed by template name
+func (r *FlightRecorderReconciler) getTemplatesUploadedByOthers(ctx context.Context,
+	jfr *operatorv1beta1.FlightRecorder) (map[string]operatorv1beta1.UploadedTemplate, error) {
+	jfrs := &operatorv1beta1.FlightRecorderList{}
+	err := r.Client.List(ctx, jfrs, client.InNamespace(jfr.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	others := map[string]operatorv1beta1.UploadedTemplate{}
+	for _, other := range jfrs.Items {
+		if other.Name == jfr.Name {
+			continue
+		}
+		for _, template := range other.Status.UploadedTemplates {
+			others[template.Name] = template
+		}
+	}
+	return others, nil
+}
+
+// getFlightRecordersForConfigMap returns a request for each FlightRecorder
+// that references the ConfigMap for one of its templates, either in its spec
+// or in the annotation of its target pod
+func (r *FlightRecorderReconciler) getFlightRecordersForConfigMap(obj client.Object) []reconcile.Request {
+	jfrs := &operatorv1beta1.FlightRecorderList{}
+	err := r.Client.List(context.Background(), jfrs, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "failed to list FlightRecorders", "namespace", obj.GetNamespace())
+		return nil
+	}
+	pods := &corev1.PodList{}
+	err = r.Client.List(context.Background(), pods, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "failed to list pods", "namespace", obj.GetNamespace())
+		return nil
+	}
+	// FlightRecorders share the name of their target pod
+	annotated := map[string]bool{}
+	for _, pod := range pods.Items {
+		for _, entry := range strings.Split(pod.Annotations[operatorv1beta1.EventTemplatesAnnotation], ",") {
+			if strings.SplitN(strings.TrimSpace(entry), "/", 2)[0] == obj.GetName() {
+				annotated[pod.Name] = true
+				break
+			}
+		}
+	}
+
+	requests := []reconcile.Request{}
+	for _, jfr := range jfrs.Items {
+		referenced := annotated[jfr.Name]
+		for _, ref := range jfr.Spec.EventTemplates {
+			if ref.ConfigMapName == obj.GetName() {
+				referenced = true
+				break
+			}
+		}
+		if referenced {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: jfr.Namespace, Name: jfr.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// deleteTemplate removes a template from Cryostat, if present
+func deleteTemplate(cryostat cryostatClient.CryostatClient, name string) error {
+	_, err := removeTemplate(cryostat, name)
+	return err
+}
+
+// removeTemplate removes a template from Cryostat, and returns whether it was present
+func removeTemplate(cryostat cryostatClient.CryostatClient, name string) (bool, error) {
+	err := cryostat.DeleteTemplate(name)
+	var errResp *cryostatClient.ErrorResponse
+	if errors.As(err, &errResp) && errResp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// getBadRequest returns the error response if Cryostat rejected a request as invalid
+func getBadRequest(err error) *cryostatClient.ErrorResponse {
+	var errResp *cryostatClient.ErrorResponse
+	if errors.As(err, &errResp) && errResp.StatusCode == http.StatusBadRequest {
+		return errResp
+	}
+	return nil
+}
+
+func containsTemplateRef(refs []operatorv1beta1.TemplateConfigMap, ref operatorv1beta1.TemplateConfigMap) bool {
+	for _, existing := range refs {
+		if existing == ref {
+			return true
+		}
+	}
+	return false
+}
+
+func templateNames(templates map[string]operatorv1beta1.UploadedTemplate) []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortTemplates(templates map[string]operatorv1beta1.UploadedTemplate) []operatorv1beta1.UploadedTemplate {
+	var result []operatorv1beta1.UploadedTemplate
+	for _, name := range templateNames(templates) {
+		result = append(result, templates[name])
+	}
+	return result
+}
@@ -0,0 +1,582 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+)
+
+// IncidentCaptureReconciler reconciles an IncidentCapture object
+type IncidentCaptureReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	common.Reconciler
+}
+
+// Reasons used for IncidentCapture conditions
+const (
+	reasonCaptureInProgress = "CaptureInProgress"
+	reasonCaptureComplete   = "CaptureComplete"
+	reasonNoTargetsSelected = "NoTargetsSelected"
+	reasonNoTargetsFailed   = "NoTargetsFailed"
+)
+
+// Finalizer used to stop the recordings of an IncidentCapture that is deleted
+// before they have finished
+const incidentCaptureFinalizer = "operator.cryostat.io/incidentcapture.finalizer"
+
+// captureTask contains what is needed to capture data from a single target
+type captureTask struct {
+	target   *operatorv1beta1.IncidentCaptureTarget
+	jfr      *operatorv1beta1.FlightRecorder
+	address  *cryostatClient.TargetAddress
+	cryostat cryostatClient.CryostatClient
+}
+
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=incidentcaptures,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=incidentcaptures/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=incidentcaptures/finalizers,verbs=update
+
+// Reconcile processes an IncidentCapture, capturing JFR data from each of its
+// target pods at once and archiving it in Cryostat
+func (r *IncidentCaptureReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling IncidentCapture")
+
+	// Fetch the IncidentCapture instance
+	instance := &operatorv1beta1.IncidentCapture{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			reqLogger.Info("IncidentCapture does not exist")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// Check if this IncidentCapture is being deleted
+	if instance.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(instance, incidentCaptureFinalizer) {
+			return r.deleteCapture(ctx, instance)
+		}
+		// Ready for deletion
+		return reconcile.Result{}, nil
+	}
+
+	// Nothing more to do once every target has finished
+	if meta.IsStatusConditionTrue(instance.Status.Conditions, operatorv1beta1.IncidentCaptureConditionComplete) {
+		// No recordings are left to clean up
+		if controllerutil.ContainsFinalizer(instance, incidentCaptureFinalizer) {
+			err = common.RemoveFinalizer(ctx, r.Client, instance, incidentCaptureFinalizer)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Add our finalizer, so we can stop recordings that are still in progress upon deletion
+	if !controllerutil.ContainsFinalizer(instance, incidentCaptureFinalizer) {
+		err = common.AddFinalizer(ctx, r.Client, instance, incidentCaptureFinalizer)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Select the target pods when the capture begins
+	if instance.Status.StartTime == nil {
+		if instance.Spec.Duration.Duration > 0 && instance.Spec.Template == nil {
+			return r.captureInvalid(ctx, instance, "spec.template is required when spec.duration is set")
+		}
+		if instance.Spec.Duration.Duration == 0 && instance.Spec.Template != nil {
+			return r.captureInvalid(ctx, instance, "spec.template cannot be set without spec.duration")
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&instance.Spec.Selector)
+		if err != nil {
+			return r.captureInvalid(ctx, instance, err.Error())
+		}
+		targets, err := r.selectTargets(ctx, instance.Namespace, selector)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		instance.Status.StartTime = &metav1.Time{Time: r.Now()}
+		instance.Status.Targets = targets
+		reqLogger.Info("selected targets for capture", "count", len(targets))
+	}
+
+	// Look up the FlightRecorder and Cryostat client for each unfinished target
+	tasks := []*captureTask{}
+	for idx := range instance.Status.Targets {
+		target := &instance.Status.Targets[idx]
+		if isCaptureTargetFinished(target) {
+			continue
+		}
+		task, err := r.prepareCapture(ctx, instance, target)
+		if err == common.ErrCertNotReady {
+			reqLogger.Info("Waiting for CA certificate")
+			setCaptureCondition(instance, operatorv1beta1.IncidentCaptureConditionComplete, metav1.ConditionFalse,
+				reasonCryostatNotReady, "Waiting for Cryostat CA certificate")
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, r.updateCaptureStatus(ctx, instance)
+		} else if err != nil {
+			r.logCaptureStatusError(r.updateCaptureStatus(ctx, instance), instance)
+			return reconcile.Result{}, err
+		}
+		if task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+
+	// Capture from all targets in parallel, each task only modifies its own target
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		wg.Add(1)
+		go func(task *captureTask) {
+			defer wg.Done()
+			r.captureTarget(instance, task)
+		}(task)
+	}
+	wg.Wait()
+
+	complete := r.setCaptureStatus(instance)
+	err = r.updateCaptureStatus(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if complete {
+		reqLogger.Info("IncidentCapture complete", "Namespace", instance.Namespace, "Name", instance.Name)
+		err = common.RemoveFinalizer(ctx, r.Client, instance, incidentCaptureFinalizer)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Archive new snapshots right away, now that their names are in the status
+	if instance.Spec.Duration.Duration == time.Duration(0) {
+		reqLogger.Info("IncidentCapture successfully updated", "Namespace", instance.Namespace, "Name", instance.Name)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	// Check progress of the recordings after 10 seconds
+	reqLogger.Info("IncidentCapture successfully updated", "Namespace", instance.Namespace, "Name", instance.Name)
+	return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IncidentCaptureReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Progress is checked by requeuing, so status updates can be ignored
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1beta1.IncidentCapture{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// selectTargets returns a pending target for each pod matching the selector that
+// has a FlightRecorder, and a failed target for each that does not
+func (r *IncidentCaptureReconciler) selectTargets(ctx context.Context, namespace string,
+	selector labels.Selector) ([]operatorv1beta1.IncidentCaptureTarget, error) {
+	pods := &corev1.PodList{}
+	err := r.Client.List(ctx, pods, &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	jfrs, err := getFlightRecordersByPod(ctx, r.Client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []operatorv1beta1.IncidentCaptureTarget{}
+	for _, pod := range pods.Items {
+		target := operatorv1beta1.IncidentCaptureTarget{
+			Pod:   pod.Name,
+			State: operatorv1beta1.IncidentCaptureTargetPending,
+		}
+		jfr, pres := jfrs[pod.Name]
+		if pres {
+			target.FlightRecorder = jfr.Name
+		} else {
+			failCaptureTarget(&target, "No FlightRecorder found for this pod")
+		}
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Pod < targets[j].Pod
+	})
+	return targets, nil
+}
+
+// prepareCapture looks up what is needed to communicate with the target's JVM.
+// If the target can no longer be captured, it is marked as failed and nil is returned.
+func (r *IncidentCaptureReconciler) prepareCapture(ctx context.Context, capture *operatorv1beta1.IncidentCapture,
+	target *operatorv1beta1.IncidentCaptureTarget) (*captureTask, error) {
+	jfr := &operatorv1beta1.FlightRecorder{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: capture.Namespace, Name: target.FlightRecorder}, jfr)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			failCaptureTarget(target, fmt.Sprintf("FlightRecorder \"%s\" not found", target.FlightRecorder))
+			return nil, nil
+		}
+		return nil, err
+	}
+	pod := &corev1.Pod{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: capture.Namespace, Name: target.Pod}, pod)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			failCaptureTarget(target, "Target pod no longer exists")
+			return nil, nil
+		}
+		return nil, err
+	}
+	address, err := r.GetPodTarget(pod, jfr.Status.Port)
+	if err != nil {
+		failCaptureTarget(target, err.Error())
+		return nil, nil
+	}
+	cryostat, err := r.GetCryostatClient(ctx, capture.Namespace, jfr.Spec.JMXCredentials)
+	if err != nil {
+		return nil, err
+	}
+	return &captureTask{
+		target:   target,
+		jfr:      jfr,
+		address:  address,
+		cryostat: cryostat,
+	}, nil
+}
+
+func (r *IncidentCaptureReconciler) captureTarget(capture *operatorv1beta1.IncidentCapture, task *captureTask) {
+	var err error
+	switch task.target.State {
+	case operatorv1beta1.IncidentCaptureTargetPending:
+		err = r.startCapture(capture, task)
+	case operatorv1beta1.IncidentCaptureTargetRecording:
+		err = r.checkCapture(task)
+	}
+	if err != nil {
+		r.Log.Error(err, "failed to capture from target", "namespace", capture.Namespace, "name", capture.Name,
+			"pod", task.target.Pod)
+		failCaptureTarget(task.target, err.Error())
+	}
+}
+
+func (r *IncidentCaptureReconciler) startCapture(capture *operatorv1beta1.IncidentCapture, task *captureTask) error {
+	target := task.target
+	if capture.Spec.Duration.Duration == time.Duration(0) {
+		// Take a snapshot of what the JVM has already recorded. It is archived once its
+		// name is recorded in the status, so a failed attempt to archive it is retried
+		// on the same snapshot, rather than leaving a new one behind in the JVM each time.
+		r.Log.Info("creating snapshot for incident capture", "name", capture.Name, "pod", target.Pod)
+		name, err := task.cryostat.CreateSnapshot(task.address)
+		if err != nil {
+			return err
+		}
+		target.RecordingName = *name
+		target.State = operatorv1beta1.IncidentCaptureTargetRecording
+		target.Message = ""
+		return nil
+	}
+
+	events, err := getTemplateEvents(capture.Spec.Template, task.jfr)
+	if err != nil {
+		return err
+	}
+	name := getCaptureRecordingName(capture)
+	options := &cryostatClient.RecordingOptions{
+		Labels: map[string]string{
+			operatorv1beta1.IncidentCaptureLabel: capture.Name,
+		},
+	}
+	r.Log.Info("creating recording for incident capture", "name", name, "pod", target.Pod,
+		"duration", capture.Spec.Duration, "events", events)
+	err = task.cryostat.DumpRecording(task.address, name, int(capture.Spec.Duration.Seconds()), events, options)
+	if err != nil {
+		// The recording may have been created before the status could be updated,
+		// otherwise report the original error
+		existing, listErr := findCaptureRecording(task, capture, name)
+		if listErr != nil || existing == nil {
+			return err
+		}
+		r.Log.Info("using existing recording for incident capture", "name", name, "pod", target.Pod)
+	}
+	target.RecordingName = name
+	target.State = operatorv1beta1.IncidentCaptureTargetRecording
+	target.Message = ""
+	return nil
+}
+
+// deleteCapture deletes the recordings still in progress in the target JVMs,
+// then allows the IncidentCapture to be deleted. Archived files are kept.
+func (r *IncidentCaptureReconciler) deleteCapture(ctx context.Context,
+	capture *operatorv1beta1.IncidentCapture) (reconcile.Result, error) {
+	for idx := range capture.Status.Targets {
+		target := &capture.Status.Targets[idx]
+		if target.State != operatorv1beta1.IncidentCaptureTargetRecording {
+			continue
+		}
+		task, err := r.prepareCapture(ctx, capture, target)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if task == nil {
+			// The target is gone, and its recording with it
+			continue
+		}
+		existing, err := findCaptureRecording(task, capture, target.RecordingName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if existing == nil {
+			continue
+		}
+		r.Log.Info("deleting recording of deleted incident capture", "name", target.RecordingName,
+			"pod", target.Pod)
+		err = task.cryostat.DeleteRecording(task.address, target.RecordingName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	err := common.RemoveFinalizer(ctx, r.Client, capture, incidentCaptureFinalizer)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	r.Log.Info("IncidentCapture successfully deleted", "namespace", capture.Namespace, "name", capture.Name)
+	return reconcile.Result{}, nil
+}
+
+func (r *IncidentCaptureReconciler) checkCapture(task *captureTask) error {
+	target := task.target
+	descriptors, err := task.cryostat.ListRecordings(task.address)
+	if err != nil {
+		return err
+	}
+	for _, descriptor := range descriptors {
+		if descriptor.Name != target.RecordingName {
+			continue
+		}
+		if operatorv1beta1.RecordingState(descriptor.State) != operatorv1beta1.RecordingStateStopped {
+			// Still recording
+			return nil
+		}
+		return r.archiveCapture(task)
+	}
+	return fmt.Errorf("recording \"%s\" no longer exists in the target JVM", target.RecordingName)
+}
+
+func (r *IncidentCaptureReconciler) archiveCapture(task *captureTask) error {
+	target := task.target
+	r.Log.Info("saving recording for incident capture", "name", target.RecordingName, "pod", target.Pod)
+	filename, err := task.cryostat.SaveRecording(task.address, target.RecordingName)
+	if err != nil {
+		// Keep the recording in the JVM, and try again on the next reconcile
+		r.Log.Error(err, "failed to save recording for incident capture", "name", target.RecordingName,
+			"pod", target.Pod)
+		target.Message = err.Error()
+		return nil
+	}
+	target.ArchivedFile = *filename
+
+	// Look up full URLs for filename returned by SaveRecording
+	savedRecordings, err := task.cryostat.ListSavedRecordings()
+	if err != nil {
+		return err
+	}
+	for _, saved := range savedRecordings {
+		if saved.Name == *filename {
+			target.DownloadURL = saved.DownloadURL
+			target.ReportURL = saved.ReportURL
+		}
+	}
+	target.State = operatorv1beta1.IncidentCaptureTargetArchived
+	target.Message = ""
+
+	// The archived copy is all that is needed, so free up the JVM's memory
+	err = task.cryostat.DeleteRecording(task.address, target.RecordingName)
+	if err != nil {
+		r.Log.Error(err, "failed to delete recording after archiving it", "name", target.RecordingName,
+			"pod", target.Pod)
+	}
+	return nil
+}
+
+// setCaptureStatus updates the conditions of the capture, and returns whether
+// every target has finished
+func (r *IncidentCaptureReconciler) setCaptureStatus(capture *operatorv1beta1.IncidentCapture) bool {
+	targets := capture.Status.Targets
+	finished := 0
+	archived := 0
+	failures := []string{}
+	for _, target := range targets {
+		switch target.State {
+		case operatorv1beta1.IncidentCaptureTargetArchived:
+			finished++
+			archived++
+		case operatorv1beta1.IncidentCaptureTargetFailed:
+			finished++
+			failures = append(failures, fmt.Sprintf("%s: %s", target.Pod, target.Message))
+		}
+	}
+
+	if len(targets) == 0 {
+		setCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionFailed, metav1.ConditionTrue,
+			reasonNoTargetsSelected, "No pods matched the selector")
+	} else if len(failures) > 0 {
+		setCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionFailed, metav1.ConditionTrue,
+			reasonTargetsFailed, strings.Join(failures, "; "))
+	} else {
+		setCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionFailed, metav1.ConditionFalse,
+			reasonNoTargetsFailed, "No target pods have failed")
+	}
+
+	complete := finished == len(targets)
+	if complete {
+		capture.Status.CompletionTime = &metav1.Time{Time: r.Now()}
+		setCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionComplete, metav1.ConditionTrue,
+			reasonCaptureComplete, fmt.Sprintf("Archived JFR data from %d of %d target pod(s)", archived, len(targets)))
+	} else {
+		setCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionComplete, metav1.ConditionFalse,
+			reasonCaptureInProgress, fmt.Sprintf("Finished capturing from %d of %d target pod(s)", finished, len(targets)))
+	}
+	return complete
+}
+
+// captureInvalid marks the capture as failed due to a problem with its spec.
+// The capture is reconciled again once its spec changes.
+func (r *IncidentCaptureReconciler) captureInvalid(ctx context.Context, capture *operatorv1beta1.IncidentCapture,
+	message string) (reconcile.Result, error) {
+	r.Log.Info("incident capture spec is invalid", "namespace", capture.Namespace, "name", capture.Name,
+		"message", message)
+	setCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionFailed, metav1.ConditionTrue,
+		reasonInvalidSpec, message)
+	setCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionComplete, metav1.ConditionFalse,
+		reasonInvalidSpec, message)
+	return reconcile.Result{}, r.updateCaptureStatus(ctx, capture)
+}
+
+func (r *IncidentCaptureReconciler) updateCaptureStatus(ctx context.Context, capture *operatorv1beta1.IncidentCapture) error {
+	capture.Status.ObservedGeneration = capture.Generation
+	return r.Client.Status().Update(ctx, capture)
+}
+
+func (r *IncidentCaptureReconciler) logCaptureStatusError(err error, capture *operatorv1beta1.IncidentCapture) {
+	// Don't mask the original error if the status update also fails
+	if err != nil {
+		r.Log.Error(err, "failed to update IncidentCapture status", "namespace", capture.Namespace,
+			"name", capture.Name)
+	}
+}
+
+// getCaptureRecordingName returns the name of the recording created in each target JVM,
+// which includes part of the UID so that it differs from earlier captures with the same name
+func getCaptureRecordingName(capture *operatorv1beta1.IncidentCapture) string {
+	uid := string(capture.UID)
+	if len(uid) == 0 {
+		return capture.Name
+	}
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return capture.Name + "-" + uid
+}
+
+// findCaptureRecording returns the recording with the given name in the target JVM,
+// if it exists and was created by the capture
+func findCaptureRecording(task *captureTask, capture *operatorv1beta1.IncidentCapture,
+	name string) (*cryostatClient.RecordingDescriptor, error) {
+	descriptors, err := task.cryostat.ListRecordings(task.address)
+	if err != nil {
+		return nil, err
+	}
+	for idx, descriptor := range descriptors {
+		if descriptor.Name == name && descriptor.Metadata.Labels[operatorv1beta1.IncidentCaptureLabel] == capture.Name {
+			return &descriptors[idx], nil
+		}
+	}
+	return nil, nil
+}
+
+func isCaptureTargetFinished(target *operatorv1beta1.IncidentCaptureTarget) bool {
+	return target.State == operatorv1beta1.IncidentCaptureTargetArchived ||
+		target.State == operatorv1beta1.IncidentCaptureTargetFailed
+}
+
+func failCaptureTarget(target *operatorv1beta1.IncidentCaptureTarget, message string) {
+	target.State = operatorv1beta1.IncidentCaptureTargetFailed
+	target.Message = message
+}
+
+func setCaptureCondition(capture *operatorv1beta1.IncidentCapture, condType string, status metav1.ConditionStatus,
+	reason string, message string) {
+	meta.SetStatusCondition(&capture.Status.Conditions, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		ObservedGeneration: capture.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
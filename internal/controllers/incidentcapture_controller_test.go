@@ -0,0 +1,454 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+)
+
+type incidentCaptureTestInput struct {
+	controller *controllers.IncidentCaptureReconciler
+	objs       []runtime.Object
+	handlers   []http.HandlerFunc
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("IncidentCaptureController", func() {
+	var t *incidentCaptureTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.Server = test.NewServer(t.Client, t.handlers, t.TLS)
+		t.controller = &controllers.IncidentCaptureReconciler{
+			Client:     t.Client,
+			Scheme:     s,
+			Log:        logger,
+			Reconciler: test.NewTestReconciler(&t.TestReconcilerConfig),
+		}
+	})
+
+	JustAfterEach(func() {
+		t.Server.VerifyRequestsReceived(t.handlers)
+		t.Server.Close()
+	})
+
+	BeforeEach(func() {
+		t = &incidentCaptureTestInput{
+			objs: []runtime.Object{
+				test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithTemplates(),
+				test.NewWorkloadTargetPod(), test.NewPendingWorkloadTargetPod(),
+				test.NewCryostatService(), test.NewJMXAuthSecret(),
+			},
+			TestReconcilerConfig: test.TestReconcilerConfig{
+				TLS: true,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a new capture", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewIncidentCapture())
+				t.handlers = []http.HandlerFunc{
+					test.NewIncidentDumpHandler(),
+				}
+			})
+			It("should start a recording on each target with a FlightRecorder", func() {
+				capture := t.reconcileCaptureAndGet()
+				Expect(capture.Status.StartTime).ToNot(BeNil())
+				Expect(capture.Status.Targets).To(HaveLen(2))
+				target := capture.Status.Targets[0]
+				Expect(target.Pod).To(Equal("test-pod"))
+				Expect(target.FlightRecorder).To(Equal("test-pod"))
+				Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetRecording))
+				Expect(target.RecordingName).To(Equal(test.IncidentRecordingName))
+			})
+			It("should fail targets without a FlightRecorder", func() {
+				capture := t.reconcileCaptureAndGet()
+				target := capture.Status.Targets[1]
+				Expect(target.Pod).To(Equal("test-pod-2"))
+				Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetFailed))
+				Expect(target.Message).To(Equal("No FlightRecorder found for this pod"))
+			})
+			It("should set conditions", func() {
+				capture := t.reconcileCaptureAndGet()
+				expectCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionComplete, metav1.ConditionFalse, "CaptureInProgress")
+				expectCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionFailed, metav1.ConditionTrue, "TargetsFailed")
+			})
+			It("should requeue after 10 seconds", func() {
+				result := t.reconcileCapture()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+			})
+			It("should add a finalizer", func() {
+				capture := t.reconcileCaptureAndGet()
+				Expect(capture.Finalizers).To(ContainElement("operator.cryostat.io/incidentcapture.finalizer"))
+			})
+		})
+		Context("with a recording created before the status was updated", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewIncidentCapture())
+				t.handlers = []http.HandlerFunc{
+					test.NewIncidentDumpFailHandler(),
+					test.NewListHandler(test.NewIncidentDescriptors("RUNNING")),
+				}
+			})
+			It("should use the existing recording", func() {
+				capture := t.reconcileCaptureAndGet()
+				target := capture.Status.Targets[0]
+				Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetRecording))
+				Expect(target.RecordingName).To(Equal(test.IncidentRecordingName))
+				Expect(target.Message).To(BeEmpty())
+			})
+		})
+		Context("with a recording of the same name not created by the capture", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewIncidentCapture())
+				descriptors := test.NewIncidentDescriptors("RUNNING")
+				descriptors[0].Metadata.Labels = nil
+				t.handlers = []http.HandlerFunc{
+					test.NewIncidentDumpFailHandler(),
+					test.NewListHandler(descriptors),
+				}
+			})
+			It("should fail the target", func() {
+				capture := t.reconcileCaptureAndGet()
+				target := capture.Status.Targets[0]
+				Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetFailed))
+				Expect(target.Message).To(ContainSubstring("400"))
+			})
+		})
+		Context("that is deleted with a recording in progress", func() {
+			BeforeEach(func() {
+				capture := test.NewRecordingIncidentCapture()
+				now := metav1.Now()
+				capture.DeletionTimestamp = &now
+				t.objs = append(t.objs, capture)
+			})
+			Context("that still exists", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewIncidentDescriptors("RUNNING")),
+						test.NewDeleteNamedHandler(test.IncidentRecordingName),
+					}
+				})
+				It("should delete the recording and remove the finalizer", func() {
+					capture := t.reconcileCaptureAndGet()
+					Expect(capture.Finalizers).To(BeEmpty())
+				})
+			})
+			Context("that is already gone", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(nil),
+					}
+				})
+				It("should remove the finalizer", func() {
+					capture := t.reconcileCaptureAndGet()
+					Expect(capture.Finalizers).To(BeEmpty())
+				})
+			})
+		})
+		Context("with a recording in progress", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecordingIncidentCapture())
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewIncidentDescriptors("RUNNING")),
+				}
+			})
+			It("should keep recording", func() {
+				capture := t.reconcileCaptureAndGet()
+				Expect(capture.Status.Targets[0].State).To(Equal(operatorv1beta1.IncidentCaptureTargetRecording))
+				Expect(capture.Status.CompletionTime).To(BeNil())
+			})
+			It("should requeue after 10 seconds", func() {
+				result := t.reconcileCapture()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+			})
+		})
+		Context("with a finished recording", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecordingIncidentCapture())
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewIncidentDescriptors("STOPPED")),
+					test.NewSaveNamedHandler(test.IncidentRecordingName),
+					test.NewListSavedHandler(test.NewIncidentSavedRecordings()),
+					test.NewDeleteNamedHandler(test.IncidentRecordingName),
+				}
+			})
+			It("should list the archived file", func() {
+				capture := t.reconcileCaptureAndGet()
+				saved := test.NewIncidentSavedRecordings()[0]
+				target := capture.Status.Targets[0]
+				Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetArchived))
+				Expect(target.ArchivedFile).To(Equal(saved.Name))
+				Expect(target.DownloadURL).To(Equal(saved.DownloadURL))
+				Expect(target.ReportURL).To(Equal(saved.ReportURL))
+			})
+			It("should complete the capture", func() {
+				capture := t.reconcileCaptureAndGet()
+				Expect(capture.Status.CompletionTime).ToNot(BeNil())
+				expectCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionComplete, metav1.ConditionTrue, "CaptureComplete")
+			})
+			It("should not requeue", func() {
+				result := t.reconcileCapture()
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+			It("should remove the finalizer", func() {
+				capture := t.reconcileCaptureAndGet()
+				Expect(capture.Finalizers).To(BeEmpty())
+			})
+		})
+		Context("with a recording that disappeared", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecordingIncidentCapture())
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(nil),
+				}
+			})
+			It("should fail the target", func() {
+				capture := t.reconcileCaptureAndGet()
+				target := capture.Status.Targets[0]
+				Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetFailed))
+				Expect(target.Message).To(ContainSubstring("no longer exists"))
+				expectCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionComplete, metav1.ConditionTrue, "CaptureComplete")
+			})
+		})
+		Context("with a snapshot capture", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewSnapshotIncidentCapture())
+			})
+			Context("that succeeds", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewSnapshotHandler(),
+					}
+				})
+				It("should record the snapshot before archiving it", func() {
+					capture := t.reconcileCaptureAndGet()
+					target := capture.Status.Targets[0]
+					Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetRecording))
+					Expect(target.RecordingName).To(Equal("snapshot-1"))
+					Expect(target.ArchivedFile).To(BeEmpty())
+				})
+				It("should requeue at once", func() {
+					result := t.reconcileCapture()
+					Expect(result).To(Equal(reconcile.Result{Requeue: true}))
+				})
+			})
+			Context("that fails", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewSnapshotFailHandler(),
+					}
+				})
+				It("should fail the target", func() {
+					capture := t.reconcileCaptureAndGet()
+					target := capture.Status.Targets[0]
+					Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetFailed))
+					Expect(target.Message).ToNot(BeEmpty())
+					expectCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionFailed, metav1.ConditionTrue, "TargetsFailed")
+				})
+			})
+		})
+		Context("with a snapshot taken", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewSnapshottedIncidentCapture())
+			})
+			Context("that is saved", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewIncidentSnapshotDescriptors()),
+						test.NewSaveNamedHandler("snapshot-1"),
+						test.NewListSavedHandler(test.NewIncidentSavedRecordings()),
+						test.NewDeleteNamedHandler("snapshot-1"),
+					}
+				})
+				It("should archive the snapshot", func() {
+					capture := t.reconcileCaptureAndGet()
+					saved := test.NewIncidentSavedRecordings()[1]
+					target := capture.Status.Targets[0]
+					Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetArchived))
+					Expect(target.RecordingName).To(Equal("snapshot-1"))
+					Expect(target.ArchivedFile).To(Equal(saved.Name))
+					Expect(target.DownloadURL).To(Equal(saved.DownloadURL))
+				})
+				It("should complete the capture", func() {
+					capture := t.reconcileCaptureAndGet()
+					expectCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionComplete, metav1.ConditionTrue, "CaptureComplete")
+					Expect(meta.FindStatusCondition(capture.Status.Conditions,
+						operatorv1beta1.IncidentCaptureConditionComplete).Message).To(Equal("Archived JFR data from 1 of 2 target pod(s)"))
+				})
+			})
+			Context("that cannot be saved", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewIncidentSnapshotDescriptors()),
+						test.NewSaveNamedFailHandler("snapshot-1"),
+					}
+				})
+				It("should keep the same snapshot for the next attempt", func() {
+					capture := t.reconcileCaptureAndGet()
+					target := capture.Status.Targets[0]
+					Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetRecording))
+					Expect(target.RecordingName).To(Equal("snapshot-1"))
+					Expect(target.Message).ToNot(BeEmpty())
+					Expect(capture.Status.CompletionTime).To(BeNil())
+				})
+				It("should requeue at once", func() {
+					result := t.reconcileCapture()
+					Expect(result).To(Equal(reconcile.Result{Requeue: true}))
+				})
+			})
+		})
+		Context("with a selector matching no pods", func() {
+			BeforeEach(func() {
+				capture := test.NewIncidentCapture()
+				capture.Spec.Selector.MatchLabels["app"] = "other-app"
+				t.objs = append(t.objs, capture)
+			})
+			It("should complete with a failure", func() {
+				capture := t.reconcileCaptureAndGet()
+				Expect(capture.Status.Targets).To(BeEmpty())
+				expectCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionComplete, metav1.ConditionTrue, "CaptureComplete")
+				expectCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionFailed, metav1.ConditionTrue, "NoTargetsSelected")
+			})
+		})
+		Context("with a duration and no template", func() {
+			BeforeEach(func() {
+				capture := test.NewIncidentCapture()
+				capture.Spec.Template = nil
+				t.objs = append(t.objs, capture)
+			})
+			It("should set Failed condition", func() {
+				capture := t.reconcileCaptureAndGet()
+				Expect(capture.Status.StartTime).To(BeNil())
+				expectCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionFailed, metav1.ConditionTrue, "InvalidSpec")
+			})
+		})
+		Context("with a template and no duration", func() {
+			BeforeEach(func() {
+				capture := test.NewIncidentCapture()
+				capture.Spec.Duration = metav1.Duration{}
+				t.objs = append(t.objs, capture)
+			})
+			It("should set Failed condition", func() {
+				capture := t.reconcileCaptureAndGet()
+				Expect(capture.Status.StartTime).To(BeNil())
+				expectCaptureCondition(capture, operatorv1beta1.IncidentCaptureConditionFailed, metav1.ConditionTrue, "InvalidSpec")
+			})
+		})
+		Context("with a template unavailable to the target", func() {
+			BeforeEach(func() {
+				capture := test.NewIncidentCapture()
+				capture.Spec.Template.Name = "Continuous"
+				t.objs = append(t.objs, capture)
+			})
+			It("should fail the target", func() {
+				capture := t.reconcileCaptureAndGet()
+				target := capture.Status.Targets[0]
+				Expect(target.State).To(Equal(operatorv1beta1.IncidentCaptureTargetFailed))
+				Expect(target.Message).To(ContainSubstring("Continuous"))
+			})
+		})
+		Context("that has completed", func() {
+			BeforeEach(func() {
+				capture := test.NewRecordingIncidentCapture()
+				capture.Status.Targets[0].State = operatorv1beta1.IncidentCaptureTargetArchived
+				meta.SetStatusCondition(&capture.Status.Conditions, metav1.Condition{
+					Type:   operatorv1beta1.IncidentCaptureConditionComplete,
+					Status: metav1.ConditionTrue,
+					Reason: "CaptureComplete",
+				})
+				t.objs = append(t.objs, capture)
+			})
+			It("should do nothing", func() {
+				result := t.reconcileCapture()
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+	})
+})
+
+func (t *incidentCaptureTestInput) reconcileCapture() reconcile.Result {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-incident", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	return result
+}
+
+func (t *incidentCaptureTestInput) reconcileCaptureAndGet() *operatorv1beta1.IncidentCapture {
+	t.reconcileCapture()
+	capture := &operatorv1beta1.IncidentCapture{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-incident", Namespace: "default"}, capture)
+	Expect(err).ToNot(HaveOccurred())
+	return capture
+}
+
+func expectCaptureCondition(capture *operatorv1beta1.IncidentCapture, condType string, status metav1.ConditionStatus,
+	reason string) {
+	condition := meta.FindStatusCondition(capture.Status.Conditions, condType)
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
@@ -38,6 +38,8 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"hash/fnv"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -49,15 +51,19 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"sort"
+	"strings"
 	"time"
 
 	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
 	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -70,14 +76,76 @@ import (
 // RecordingReconciler reconciles a Recording object
 type RecordingReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
 	common.Reconciler
 }
 
 // Name used for Finalizer that handles Cryostat recording deletion
 const recordingFinalizer = "operator.cryostat.io/recording.finalizer"
 
+// Reasons used for Events emitted for Recordings
+const (
+	eventCleanupAbandoned = "CleanupAbandoned"
+)
+
+// The number of copies of a running recording listed in its status, older copies are removed
+const maxArchivedSnapshots = 10
+
+// Reasons used for Recording conditions
+const (
+	reasonRecordingReconciled   = "RecordingReconciled"
+	reasonRecordingFound        = "RecordingFound"
+	reasonRecordingNotFound     = "RecordingNotFound"
+	reasonRecordingArchived     = "RecordingArchived"
+	reasonArchiveNotRequested   = "ArchiveNotRequested"
+	reasonArchivePending        = "ArchivePending"
+	reasonArchivePruned         = "ArchivePruned"
+	reasonTargetFound           = "TargetFound"
+	reasonFlightRecorderMissing = "FlightRecorderMissing"
+	reasonTargetPending         = "TargetPending"
+	reasonTargetPodNotFound     = "TargetPodNotFound"
+	reasonTargetPodNotReady     = "TargetPodNotReady"
+	reasonCryostatNotReady      = "CryostatNotReady"
+	reasonCryostatUnavailable   = "CryostatUnavailable"
+	reasonCreateFailed          = "CreateFailed"
+	reasonStopFailed            = "StopFailed"
+	reasonListFailed            = "ListFailed"
+	reasonUnknownState          = "UnknownState"
+	reasonArchiveFailed         = "ArchiveFailed"
+	reasonDeleteFailed          = "DeleteFailed"
+	reasonInternalError         = "InternalError"
+	reasonInvalidSpec           = "InvalidSpec"
+	reasonTemplateNotFound      = "TemplateNotFound"
+	reasonInvalidEventOptions   = "InvalidEventOptions"
+	reasonRecordingScheduled    = "RecordingScheduled"
+	reasonRecordingConflict     = "RecordingConflict"
+	reasonSpecChangeRejected    = "SpecChangeRejected"
+	reasonRestartFailed         = "RestartFailed"
+)
+
+// invalidRecordingError describes a problem with a Recording's spec that
+// will not resolve itself until either the spec or the target JVM changes
+type invalidRecordingError struct {
+	reason  string
+	message string
+}
+
+func (e *invalidRecordingError) Error() string {
+	return e.message
+}
+
+// isInvalidRecordingReason returns whether the reason is one used for an
+// invalidRecordingError, rather than for an error that is retried
+func isInvalidRecordingReason(reason string) bool {
+	switch reason {
+	case reasonInvalidSpec, reasonTemplateNotFound, reasonInvalidEventOptions, reasonRecordingConflict:
+		return true
+	}
+	return false
+}
+
 // +kubebuilder:rbac:namespace=system,groups="",resources=pods;services;secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:namespace=system,groups=cert-manager.io,resources=issuers;certificates,verbs=create;get;list;update;watch
 // +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=recordings;flightrecorders;cryostats,verbs=*
@@ -105,10 +173,31 @@ func (r *RecordingReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 		return reconcile.Result{}, err
 	}
 
+	// Stop trying to clean up in Cryostat once forced to, or the finalizer has timed out
+	if instance.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(instance, recordingFinalizer) {
+		cause := r.getCleanupAbandonCause(ctx, instance)
+		if cause != nil {
+			return r.abandonCleanup(ctx, instance, *cause)
+		}
+	}
+
+	// Wait for the scheduled start time before creating the recording
+	if instance.GetDeletionTimestamp() == nil && !hasRecordingStarted(instance) {
+		result, scheduled, err := r.checkRecordingSchedule(ctx, instance)
+		if scheduled {
+			return result, err
+		}
+	}
+
+	// Recordings targeting a workload may span many FlightRecorders
+	if isWorkloadRecording(instance) {
+		return r.reconcileWorkloadRecording(ctx, instance)
+	}
+
 	// Look up FlightRecorder referenced by this Recording
 	jfr, err := r.getFlightRecorder(ctx, instance)
 	if err != nil {
-		return reconcile.Result{}, err
+		return r.recordingFailed(ctx, instance, reasonInternalError, err)
 	}
 	if jfr == nil {
 		// Check if this Recording is being deleted
@@ -116,32 +205,50 @@ func (r *RecordingReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 			return r.deleteWithoutLiveTarget(ctx, instance)
 		}
 		// No matching FlightRecorder, its corresponding Pod might have been deleted
-		return reconcile.Result{}, nil
+		err = r.updateRecordingStatus(ctx, instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		// Archived recordings may still expire without their target
+		return r.deleteIfExpired(ctx, instance)
 	}
 
 	// Obtain a client configured to communicate with Cryostat
 	cryostat, err := r.GetCryostatClient(ctx, request.Namespace, jfr.Spec.JMXCredentials)
 	if err != nil {
-		return r.requeueIfNotReady(err)
+		return r.requeueIfNotReady(ctx, instance, err)
 	}
 
 	// Look up pod corresponding to this FlightRecorder object
 	targetRef := jfr.Status.Target
 	if targetRef == nil {
 		// FlightRecorder status must not have been updated yet
-		return reconcile.Result{RequeueAfter: time.Second}, nil
+		setTargetUnavailable(instance, reasonTargetPending,
+			fmt.Sprintf("FlightRecorder \"%s\" has not been assigned a target yet", jfr.Name))
+		err = r.updateRecordingStatus(ctx, instance)
+		return reconcile.Result{RequeueAfter: time.Second}, err
 	}
 	targetPod := &corev1.Pod{}
 	err = r.Client.Get(ctx, types.NamespacedName{Namespace: targetRef.Namespace, Name: targetRef.Name}, targetPod)
 	if err != nil {
-		return reconcile.Result{}, err
+		if kerrors.IsNotFound(err) {
+			setTargetUnavailable(instance, reasonTargetPodNotFound,
+				fmt.Sprintf("Target pod \"%s\" not found", targetRef.Name))
+			r.logStatusError(r.updateRecordingStatus(ctx, instance), instance)
+			return reconcile.Result{}, err
+		}
+		return r.recordingFailed(ctx, instance, reasonInternalError, err)
 	}
 
 	// Get TargetAddress for the referenced pod and port number listed in FlightRecorder
 	targetAddr, err := r.GetPodTarget(targetPod, jfr.Status.Port)
 	if err != nil {
+		setTargetUnavailable(instance, reasonTargetPodNotReady, err.Error())
+		r.logStatusError(r.updateRecordingStatus(ctx, instance), instance)
 		return reconcile.Result{}, err
 	}
+	setRecordingCondition(instance, operatorv1beta1.RecordingConditionTargetAvailable, metav1.ConditionTrue,
+		reasonTargetFound, fmt.Sprintf("Found target pod \"%s\" for FlightRecorder \"%s\"", targetPod.Name, jfr.Name))
 
 	// Check if this Recording is being deleted
 	if instance.GetDeletionTimestamp() != nil {
@@ -156,29 +263,57 @@ func (r *RecordingReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 	if !controllerutil.ContainsFinalizer(instance, recordingFinalizer) {
 		err := common.AddFinalizer(ctx, r.Client, instance, recordingFinalizer)
 		if err != nil {
-			return reconcile.Result{}, err
+			return r.recordingFailed(ctx, instance, reasonInternalError, err)
+		}
+	}
+
+	// Apply any changes to the spec since the recording was created
+	specRejected := ""
+	if hasRecordingStarted(instance) {
+		restart, rejected, err := r.checkSpecChange(instance)
+		if err != nil {
+			return r.recordingFailed(ctx, instance, reasonInternalError, err)
+		}
+		specRejected = rejected
+		if restart {
+			err = r.restartRecording(ctx, cryostat, targetAddr, instance, r.Log)
+			if err != nil {
+				return r.recordingFailed(ctx, instance, reasonRestartFailed, err)
+			}
+			resetRecordingStatus(instance)
 		}
 	}
 
 	// Tell Cryostat to create the recording if not already done
-	if instance.Status.State == nil { // Recording hasn't been created yet
-		if instance.Spec.Duration.Duration == time.Duration(0) {
-			r.Log.Info("creating new continuous recording", "name", instance.Spec.Name, "eventOptions", instance.Spec.EventOptions)
-			err = cryostat.StartRecording(targetAddr, instance.Spec.Name, instance.Spec.EventOptions)
-		} else {
-			r.Log.Info("creating new recording", "name", instance.Spec.Name, "duration", instance.Spec.Duration, "eventOptions", instance.Spec.EventOptions)
-			err = cryostat.DumpRecording(targetAddr, instance.Spec.Name, int(instance.Spec.Duration.Seconds()), instance.Spec.EventOptions)
+	if isRecordingAdopted(instance) && !hasRecordingStarted(instance) {
+		r.Log.Info("using existing recording from target JVM", "name", instance.Spec.Name)
+		err = setRecordingGeneration(instance)
+		if err != nil {
+			return r.recordingFailed(ctx, instance, reasonInternalError, err)
 		}
+	} else if !hasRecordingStarted(instance) { // Recording hasn't been created yet
+		events, err := getRecordingEvents(ctx, r.Client, instance, jfr)
 		if err != nil {
+			return r.recordingInvalid(ctx, instance, err)
+		}
+		err = r.createRecording(cryostat, targetAddr, instance, events, r.Log)
+		if err != nil {
+			if _, ok := err.(*invalidRecordingError); ok {
+				return r.recordingInvalid(ctx, instance, err)
+			}
 			r.Log.Error(err, "failed to create new recording")
-			return reconcile.Result{}, err
+			return r.recordingFailed(ctx, instance, reasonCreateFailed, err)
+		}
+		err = setRecordingGeneration(instance)
+		if err != nil {
+			return r.recordingFailed(ctx, instance, reasonInternalError, err)
 		}
-	} else if shouldStopRecording(instance) {
+	} else if shouldStopRecording(instance, r.Now()) {
 		r.Log.Info("stopping recording", "name", instance.Spec.Name)
 		err = cryostat.StopRecording(targetAddr, instance.Spec.Name)
 		if err != nil {
 			r.Log.Error(err, "failed to stop recording")
-			return reconcile.Result{}, err
+			return r.recordingFailed(ctx, instance, reasonStopFailed, err)
 		}
 	}
 
@@ -189,45 +324,115 @@ func (r *RecordingReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 	reportURL := instance.Status.ReportURL
 	descriptor, err := r.findRecordingByName(cryostat, targetAddr, instance.Spec.Name)
 	if err != nil {
-		return reconcile.Result{}, err
+		return r.recordingFailed(ctx, instance, reasonListFailed, err)
 	}
 	if descriptor != nil {
 		state, err := validateRecordingState(descriptor.State)
 		if err != nil {
-			// TODO Likely an internal error, requeuing may not help. Status.Condition may be useful.
+			// TODO Likely an internal error, requeuing may not help
 			r.Log.Error(err, "unknown recording state observed from Cryostat")
-			return reconcile.Result{}, err
+			return r.recordingFailed(ctx, instance, reasonUnknownState, err)
 		}
 		instance.Status.State = state
+		if *state == operatorv1beta1.RecordingStateStopped && instance.Status.CompletionTime == nil {
+			instance.Status.CompletionTime = &metav1.Time{Time: r.Now()}
+		}
 		instance.Status.StartTime = metav1.Unix(0, descriptor.StartTime*int64(time.Millisecond))
 		instance.Status.Duration = metav1.Duration{
 			Duration: time.Duration(descriptor.Duration) * time.Millisecond,
 		}
+		instance.Status.MaxSize = resource.NewQuantity(descriptor.MaxSize, resource.BinarySI)
+		instance.Status.MaxAge = &metav1.Duration{
+			Duration: time.Duration(descriptor.MaxAge) * time.Millisecond,
+		}
+		instance.Status.ToDisk = &descriptor.ToDisk
 		downloadURL = &descriptor.DownloadURL
 		reportURL = &descriptor.ReportURL
+		setRecordingCondition(instance, operatorv1beta1.RecordingConditionReady, metav1.ConditionTrue,
+			reasonRecordingFound, fmt.Sprintf("Recording \"%s\" is %s in the target JVM", descriptor.Name, *state))
+	} else {
+		setRecordingCondition(instance, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse,
+			reasonRecordingNotFound, fmt.Sprintf("Recording \"%s\" was not found in the target JVM", instance.Spec.Name))
+	}
+
+	// Save a copy of the running recording before its target pod shuts down,
+	// otherwise periodically if requested
+	isRunning := descriptor != nil && *instance.Status.State == operatorv1beta1.RecordingStateRunning
+	if isRunning && isPodTerminating(targetPod) {
+		err = r.archiveSnapshotBeforeTermination(ctx, cryostat, instance, targetAddr, targetPod)
+		if err != nil {
+			setRecordingCondition(instance, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse,
+				reasonArchiveFailed, err.Error())
+			return r.recordingFailed(ctx, instance, reasonArchiveFailed, err)
+		}
+	} else if isRunning && instance.Spec.Archive && instance.Spec.ArchiveInterval != nil {
+		err = r.archiveSnapshotIfDue(ctx, cryostat, instance, targetAddr)
+		if err != nil {
+			setRecordingCondition(instance, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse,
+				reasonArchiveFailed, err.Error())
+			return r.recordingFailed(ctx, instance, reasonArchiveFailed, err)
+		}
+	}
+
+	// A recording that had already stopped when it was first found, such as one adopted by
+	// createRecording after the operator restarted, has no earlier URL to archive from
+	if instance.Status.DownloadURL == nil {
+		instance.Status.DownloadURL = downloadURL
+		instance.Status.ReportURL = reportURL
 	}
 
 	// Archive completed recording if requested and not already done
 	isStopped := instance.Status.State != nil && *instance.Status.State == operatorv1beta1.RecordingStateStopped
-	if instance.Spec.Archive && isStopped {
+	if !instance.Spec.Archive {
+		setRecordingCondition(instance, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse,
+			reasonArchiveNotRequested, "Recording is not configured to be archived")
+	} else if !isStopped {
+		message := "Recording will be archived once it has stopped"
+		if snapshots := instance.Status.ArchivedSnapshots; len(snapshots) > 0 {
+			message += fmt.Sprintf(", latest copy archived as \"%s\"", snapshots[len(snapshots)-1].Name)
+		}
+		setRecordingCondition(instance, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse,
+			reasonArchivePending, message)
+	} else if meta.IsStatusConditionTrue(instance.Status.Conditions, operatorv1beta1.RecordingConditionArchived) {
+		// Only archive once, a missing file has been pruned rather than never saved
+		downloadURL = instance.Status.DownloadURL
+		reportURL = instance.Status.ReportURL
+		err = r.checkArchivedRecording(cryostat, instance)
+		if err != nil {
+			return r.recordingFailed(ctx, instance, reasonListFailed, err)
+		}
+	} else {
 		recording, err := r.archiveStoppedRecording(cryostat, instance, targetAddr)
 		if err != nil {
-			return reconcile.Result{}, err
+			setRecordingCondition(instance, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse,
+				reasonArchiveFailed, err.Error())
+			return r.recordingFailed(ctx, instance, reasonArchiveFailed, err)
 		} else if recording == nil {
 			// Unlikely, but log just in case
 			r.Log.Info("Cannot find JFR URL just saved", "name", instance.Spec.Name)
+			setRecordingCondition(instance, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse,
+				reasonArchivePending, "Archived recording file could not be found in Cryostat")
 		} else {
 			r.Log.Info("updating download URL", "name", instance.Spec.Name, "url", &recording.DownloadURL)
 			downloadURL = &recording.DownloadURL
 			r.Log.Info("updating report URL", "name", instance.Spec.Name, "url", &recording.ReportURL)
 			reportURL = &recording.ReportURL
+			setRecordingCondition(instance, operatorv1beta1.RecordingConditionArchived, metav1.ConditionTrue,
+				reasonRecordingArchived, fmt.Sprintf("Recording archived as \"%s\"", recording.Name))
 		}
 	}
 	instance.Status.DownloadURL = downloadURL
 	instance.Status.ReportURL = reportURL
+	if len(specRejected) > 0 {
+		setRecordingCondition(instance, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue,
+			reasonSpecChangeRejected, specRejected)
+	} else {
+		setRecordingCondition(instance, operatorv1beta1.RecordingConditionFailed, metav1.ConditionFalse,
+			reasonRecordingReconciled, "Recording was successfully reconciled")
+	}
 
 	// Update Recording status
-	err = r.Client.Status().Update(ctx, instance)
+	err = r.updateRecordingStatus(ctx, instance)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -237,6 +442,12 @@ func (r *RecordingReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 	if !isStopped {
 		// Check progress of recording after 10 seconds
 		result.RequeueAfter = 10 * time.Second
+	} else {
+		// Delete the recording if its TTL has elapsed, or requeue once it will have
+		result, err = r.deleteIfExpired(ctx, instance)
+		if err != nil {
+			return result, err
+		}
 	}
 
 	reqLogger.Info("Recording successfully updated", "Namespace", instance.Namespace, "Name", instance.Name)
@@ -248,6 +459,7 @@ func (r *RecordingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	c := ctrl.NewControllerManagedBy(mgr)
 	c = c.For(&operatorv1beta1.Recording{})
 	c = r.watchFlightRecorders(c, mgr.GetClient())
+	c = r.watchTerminatingPods(c, mgr.GetClient())
 
 	return c.Complete(r)
 }
@@ -255,9 +467,9 @@ func (r *RecordingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 func (r *RecordingReconciler) getFlightRecorder(ctx context.Context, recording *operatorv1beta1.Recording) (*operatorv1beta1.FlightRecorder, error) {
 	jfrRef := recording.Spec.FlightRecorder
 	if jfrRef == nil || len(jfrRef.Name) == 0 {
-		// TODO set Condition for user/log error
 		r.Log.Info("FlightRecorder reference missing from Recording", "name", recording.Name,
 			"namespace", recording.Namespace)
+		setTargetUnavailable(recording, reasonFlightRecorderMissing, "No FlightRecorder specified in spec.flightRecorder")
 		return nil, nil
 	}
 
@@ -271,9 +483,11 @@ func (r *RecordingReconciler) getFlightRecorder(ctx context.Context, recording *
 	err = r.Client.Get(ctx, types.NamespacedName{Namespace: recording.Namespace, Name: jfrRef.Name}, jfr)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			// TODO set Condition for user, could be legitimate if pod is deleted
+			// Could be legitimate if pod is deleted
 			r.Log.Info("FlightRecorder referenced from Recording not found", "name", jfrRef.Name,
 				"namespace", recording.Namespace)
+			setTargetUnavailable(recording, reasonFlightRecorderMissing,
+				fmt.Sprintf("FlightRecorder \"%s\" not found", jfrRef.Name))
 			return nil, nil
 		}
 		return nil, err
@@ -299,6 +513,9 @@ func (r *RecordingReconciler) findSavedRecording(cryostat cryostatClient.Cryosta
 func (r *RecordingReconciler) archiveStoppedRecording(cryostat cryostatClient.CryostatClient, recording *operatorv1beta1.Recording,
 	target *cryostatClient.TargetAddress) (*cryostatClient.SavedRecording, error) {
 	// Check if existing download URL points to an archived recording
+	if recording.Status.DownloadURL == nil {
+		return nil, fmt.Errorf("recording \"%s\" has no download URL to archive from", recording.Spec.Name)
+	}
 	jfrFile, err := recordingFilename(*recording.Status.DownloadURL)
 	if err != nil {
 		return nil, err
@@ -325,6 +542,134 @@ func (r *RecordingReconciler) archiveStoppedRecording(cryostat cryostatClient.Cr
 	return r.findSavedRecording(cryostat, *filename)
 }
 
+// checkArchivedRecording notes in the Archived condition if the archived file
+// of the recording has since been deleted, such as by the retention policy
+func (r *RecordingReconciler) checkArchivedRecording(cryostat cryostatClient.CryostatClient,
+	recording *operatorv1beta1.Recording) error {
+	if recording.Status.DownloadURL == nil {
+		return nil
+	}
+	jfrFile, err := recordingFilename(*recording.Status.DownloadURL)
+	if err != nil {
+		return err
+	}
+	saved, err := r.findSavedRecording(cryostat, *jfrFile)
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		r.Log.Info("archived recording no longer exists", "name", recording.Spec.Name, "file", *jfrFile)
+		setRecordingCondition(recording, operatorv1beta1.RecordingConditionArchived, metav1.ConditionTrue,
+			reasonArchivePruned, fmt.Sprintf("Recording was archived as \"%s\", which has since been deleted", *jfrFile))
+	}
+	return nil
+}
+
+func (r *RecordingReconciler) archiveSnapshotIfDue(ctx context.Context, cryostat cryostatClient.CryostatClient,
+	recording *operatorv1beta1.Recording, target *cryostatClient.TargetAddress) error {
+	// Measure the interval from the last copy, or the start of the recording
+	last := recording.Status.StartTime.Time
+	snapshots := recording.Status.ArchivedSnapshots
+	if len(snapshots) > 0 {
+		last = snapshots[len(snapshots)-1].Time.Time
+	}
+	if r.Now().Before(last.Add(recording.Spec.ArchiveInterval.Duration)) {
+		return nil
+	}
+	return r.saveSnapshot(ctx, cryostat, recording, target, operatorv1beta1.ArchivedSnapshotReasonInterval)
+}
+
+func (r *RecordingReconciler) archiveSnapshotBeforeTermination(ctx context.Context, cryostat cryostatClient.CryostatClient,
+	recording *operatorv1beta1.Recording, target *cryostatClient.TargetAddress, pod *corev1.Pod) error {
+	// Only save one copy per shutdown of the pod
+	requested := podTerminationRequestTime(pod)
+	for _, snapshot := range recording.Status.ArchivedSnapshots {
+		if snapshot.Reason == operatorv1beta1.ArchivedSnapshotReasonTargetTerminating &&
+			!snapshot.Time.Time.Before(requested) {
+			return nil
+		}
+	}
+	r.Log.Info("target pod is terminating", "name", recording.Spec.Name, "pod", pod.Name)
+	return r.saveSnapshot(ctx, cryostat, recording, target, operatorv1beta1.ArchivedSnapshotReasonTargetTerminating)
+}
+
+func (r *RecordingReconciler) saveSnapshot(ctx context.Context, cryostat cryostatClient.CryostatClient,
+	recording *operatorv1beta1.Recording, target *cryostatClient.TargetAddress,
+	reason operatorv1beta1.ArchivedSnapshotReason) error {
+	r.Log.Info("saving copy of running recording", "name", recording.Spec.Name, "reason", reason)
+	filename, err := cryostat.SaveRecording(target, recording.Spec.Name)
+	if err != nil {
+		r.Log.Error(err, "failed to save recording", "name", recording.Spec.Name)
+		return err
+	}
+	snapshot := operatorv1beta1.ArchivedSnapshot{
+		Name:   *filename,
+		Time:   metav1.NewTime(r.Now()),
+		Reason: reason,
+	}
+
+	// Look up full URLs for filename returned by SaveRecording
+	saved, err := r.findSavedRecording(cryostat, *filename)
+	if err != nil {
+		return err
+	}
+	if saved != nil {
+		snapshot.DownloadURL = saved.DownloadURL
+		snapshot.ReportURL = saved.ReportURL
+	}
+	recording.Status.ArchivedSnapshots = append(recording.Status.ArchivedSnapshots, snapshot)
+	r.trimArchivedSnapshots(ctx, cryostat, recording)
+	return nil
+}
+
+// trimArchivedSnapshots keeps only the newest copies of a running recording,
+// so that its status does not grow without bound. The older copies are deleted
+// from Cryostat, or kept and labelled as they would be once the Recording is deleted.
+func (r *RecordingReconciler) trimArchivedSnapshots(ctx context.Context, cryostat cryostatClient.CryostatClient,
+	recording *operatorv1beta1.Recording) {
+	snapshots := recording.Status.ArchivedSnapshots
+	if len(snapshots) <= maxArchivedSnapshots {
+		return
+	}
+	dropped := snapshots[:len(snapshots)-maxArchivedSnapshots]
+	jfrFiles := map[string]bool{}
+	for _, snapshot := range dropped {
+		jfrFiles[snapshot.Name] = true
+	}
+
+	policy := operatorv1beta1.RecordingDeletionPolicyRetain
+	if recording.Annotations[operatorv1beta1.RecordingRetainAnnotation] != "true" {
+		var err error
+		policy, err = r.getDeletionPolicy(ctx, recording)
+		if err != nil {
+			r.Log.Error(err, "failed to get deletion policy", "name", recording.Spec.Name)
+			return
+		}
+	}
+	// Keep the older copies listed if they can't be removed, to try again with the next copy
+	err := r.removeSavedFiles(cryostat, recording, jfrFiles, policy)
+	if err != nil {
+		r.Log.Error(err, "failed to remove older copies of recording", "name", recording.Spec.Name)
+		return
+	}
+	recording.Status.ArchivedSnapshots = append([]operatorv1beta1.ArchivedSnapshot{},
+		snapshots[len(snapshots)-maxArchivedSnapshots:]...)
+}
+
+func isPodTerminating(pod *corev1.Pod) bool {
+	return pod.GetDeletionTimestamp() != nil
+}
+
+// podTerminationRequestTime returns when deletion of the pod was requested,
+// which precedes its deletion timestamp by the grace period
+func podTerminationRequestTime(pod *corev1.Pod) time.Time {
+	requested := pod.GetDeletionTimestamp().Time
+	if grace := pod.GetDeletionGracePeriodSeconds(); grace != nil {
+		requested = requested.Add(-time.Duration(*grace) * time.Second)
+	}
+	return requested
+}
+
 func (r *RecordingReconciler) removeRecording(cryostat cryostatClient.CryostatClient, target *cryostatClient.TargetAddress,
 	recording *operatorv1beta1.Recording) error {
 	// Check if recording exists in Cryostat's in-memory list
@@ -344,29 +689,147 @@ func (r *RecordingReconciler) removeRecording(cryostat cryostatClient.CryostatCl
 	return nil
 }
 
-func (r *RecordingReconciler) removeSavedRecording(cryostat cryostatClient.CryostatClient,
+func (r *RecordingReconciler) removeSavedRecording(ctx context.Context, cryostat cryostatClient.CryostatClient,
 	recording *operatorv1beta1.Recording) error {
-	if recording.Status.DownloadURL != nil {
-		jfrFile, err := recordingFilename(*recording.Status.DownloadURL)
-		if err != nil {
-			return err
+	jfrFiles, err := archivedFilenames(recording)
+	if err != nil {
+		return err
+	}
+	if len(jfrFiles) == 0 {
+		return nil
+	}
+	policy, err := r.getDeletionPolicy(ctx, recording)
+	if err != nil {
+		return err
+	}
+	return r.removeSavedFiles(cryostat, recording, jfrFiles, policy)
+}
+
+// removeSavedFiles deletes the named JFR files of the recording from Cryostat,
+// or labels them to be kept if the deletion policy is Retain
+func (r *RecordingReconciler) removeSavedFiles(cryostat cryostatClient.CryostatClient,
+	recording *operatorv1beta1.Recording, jfrFiles map[string]bool, policy operatorv1beta1.RecordingDeletionPolicy) error {
+	// Look for these JFR files within Cryostat's list of saved recordings
+	savedRecordings, err := cryostat.ListSavedRecordings()
+	if err != nil {
+		r.Log.Error(err, "failed to list saved flight recordings")
+		return err
+	}
+	for _, saved := range savedRecordings {
+		if !jfrFiles[saved.Name] {
+			continue
+		}
+		if policy == operatorv1beta1.RecordingDeletionPolicyRetain {
+			// Keep the JFR file, labelled so it can be found once the Recording is gone
+			err = cryostat.UpdateSavedRecordingLabels(saved.Name, retainedRecordingLabels(recording, &saved))
+			if err != nil {
+				return err
+			}
+			r.Log.Info("saved recording retained", "file", saved.Name)
+			continue
 		}
-		// Look for this JFR file within Cryostat's list of saved recordings
-		found, err := r.findSavedRecording(cryostat, *jfrFile)
+		// JFR file exists, so delete it
+		err = cryostat.DeleteSavedRecording(saved.Name)
 		if err != nil {
 			return err
 		}
+		r.Log.Info("saved recording successfully deleted", "file", saved.Name)
+	}
+	return nil
+}
+
+// getDeletionPolicy returns the deletion policy of the recording, falling back
+// to the default from the Cryostat in its namespace
+func (r *RecordingReconciler) getDeletionPolicy(ctx context.Context,
+	recording *operatorv1beta1.Recording) (operatorv1beta1.RecordingDeletionPolicy, error) {
+	if len(recording.Spec.DeletionPolicy) > 0 {
+		return recording.Spec.DeletionPolicy, nil
+	}
+	cryostat, err := r.FindCryostat(ctx, recording.Namespace)
+	if err != nil {
+		return "", err
+	}
+	storage := cryostat.Spec.StorageOptions
+	if storage != nil && len(storage.RecordingDeletionPolicy) > 0 {
+		return storage.RecordingDeletionPolicy, nil
+	}
+	return operatorv1beta1.RecordingDeletionPolicyDelete, nil
+}
+
+// retainedRecordingLabels returns the existing labels of the saved recording,
+// along with labels identifying the Recording it was archived for
+func retainedRecordingLabels(recording *operatorv1beta1.Recording,
+	saved *cryostatClient.SavedRecording) map[string]string {
+	labels := map[string]string{}
+	for key, value := range saved.Metadata.Labels {
+		labels[key] = value
+	}
+	labels[operatorv1beta1.RecordingOwnerLabel] = recording.Name
+	labels[operatorv1beta1.RecordingNamespaceLabel] = recording.Namespace
+	labels[operatorv1beta1.RecordingRetainLabel] = "true"
+	return labels
+}
 
-		if found != nil {
-			// JFR file exists, so delete it
-			err = cryostat.DeleteSavedRecording(*jfrFile)
+// archivedFilenames returns the names of all JFR files that may have been
+// archived for this recording
+func archivedFilenames(recording *operatorv1beta1.Recording) (map[string]bool, error) {
+	jfrFiles := map[string]bool{}
+	if recording.Status.DownloadURL != nil {
+		jfrFile, err := recordingFilename(*recording.Status.DownloadURL)
+		if err != nil {
+			return nil, err
+		}
+		jfrFiles[*jfrFile] = true
+	}
+	for _, snapshot := range recording.Status.ArchivedSnapshots {
+		jfrFiles[snapshot.Name] = true
+	}
+	for _, target := range recording.Status.Targets {
+		if target.Archived && target.DownloadURL != nil {
+			jfrFile, err := recordingFilename(*target.DownloadURL)
 			if err != nil {
-				return err
+				return nil, err
 			}
-			r.Log.Info("saved recording successfully deleted", "file", jfrFile)
+			jfrFiles[*jfrFile] = true
 		}
 	}
-	return nil
+	return jfrFiles, nil
+}
+
+func (r *RecordingReconciler) deleteIfExpired(ctx context.Context, recording *operatorv1beta1.Recording) (reconcile.Result, error) {
+	ttl := recording.Spec.TTLSecondsAfterFinished
+	if ttl == nil || recording.GetDeletionTimestamp() != nil || recording.Status.CompletionTime == nil ||
+		!isRecordingComplete(recording) {
+		return reconcile.Result{}, nil
+	}
+
+	// Check if the TTL has elapsed since the recording stopped
+	expiry := recording.Status.CompletionTime.Add(time.Duration(*ttl) * time.Second)
+	remaining := expiry.Sub(r.Now())
+	if remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
+	// Our finalizer cleans up the JFR files in Cryostat
+	err := r.Client.Delete(ctx, recording)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.Log.Error(err, "failed to delete expired recording", "namespace", recording.Namespace,
+			"name", recording.Name)
+		return reconcile.Result{}, err
+	}
+	r.Log.Info("deleted recording after TTL elapsed", "namespace", recording.Namespace, "name", recording.Name,
+		"ttlSecondsAfterFinished", *ttl)
+	return reconcile.Result{}, nil
+}
+
+func isRecordingComplete(recording *operatorv1beta1.Recording) bool {
+	state := recording.Status.State
+	if state == nil || *state != operatorv1beta1.RecordingStateStopped {
+		return false
+	}
+	// Wait for any requested archiving to finish as well
+	return !recording.Spec.Archive ||
+		meta.IsStatusConditionTrue(recording.Status.Conditions, operatorv1beta1.RecordingConditionArchived)
 }
 
 func (r *RecordingReconciler) applyFlightRecorderLabel(ctx context.Context, recording *operatorv1beta1.Recording,
@@ -397,14 +860,14 @@ func (r *RecordingReconciler) deleteWithoutLiveTarget(ctx context.Context, recor
 	// Obtain a client configured to communicate with Cryostat without JMX credentials
 	cryostat, err := r.GetCryostatClient(ctx, recording.Namespace, nil)
 	if err != nil {
-		return r.requeueIfNotReady(err)
+		return r.requeueIfNotReady(ctx, recording, err)
 	}
 
-	// Delete any persisted JFR file for this recording
-	err = r.removeSavedRecording(cryostat, recording)
+	// Delete or retain any persisted JFR file for this recording
+	err = r.removeSavedRecording(ctx, cryostat, recording)
 	if err != nil {
 		reqLogger.Error(err, "failed to delete saved recording in Cryostat")
-		return reconcile.Result{}, err
+		return r.recordingFailed(ctx, recording, reasonDeleteFailed, err)
 	}
 
 	// Allow deletion to proceed, since no FlightRecorder/Pod to clean up
@@ -415,18 +878,18 @@ func (r *RecordingReconciler) deleteWithoutLiveTarget(ctx context.Context, recor
 func (r *RecordingReconciler) deleteWithLiveTarget(ctx context.Context, cryostat cryostatClient.CryostatClient,
 	recording *operatorv1beta1.Recording, target *cryostatClient.TargetAddress) (reconcile.Result, error) {
 	reqLogger := r.Log.WithValues("Request.Namespace", recording.Namespace, "Request.Name", recording.Name)
-	// Delete any persisted JFR file for this recording
-	err := r.removeSavedRecording(cryostat, recording)
+	// Delete or retain any persisted JFR file for this recording
+	err := r.removeSavedRecording(ctx, cryostat, recording)
 	if err != nil {
 		reqLogger.Error(err, "failed to delete saved recording in Cryostat")
-		return reconcile.Result{}, err
+		return r.recordingFailed(ctx, recording, reasonDeleteFailed, err)
 	}
 
 	// Delete in-memory recording in Cryostat
 	err = r.removeRecording(cryostat, target, recording)
 	if err != nil {
 		reqLogger.Error(err, "failed to delete recording in Cryostat")
-		return reconcile.Result{}, err
+		return r.recordingFailed(ctx, recording, reasonDeleteFailed, err)
 	}
 
 	// Remove our finalizer only once our cleanup logic has succeeded
@@ -434,6 +897,93 @@ func (r *RecordingReconciler) deleteWithLiveTarget(ctx context.Context, cryostat
 	return reconcile.Result{}, err
 }
 
+// getCleanupAbandonCause returns why the operator should give up on cleaning up the
+// recording in Cryostat, or nil if it should keep trying
+func (r *RecordingReconciler) getCleanupAbandonCause(ctx context.Context, recording *operatorv1beta1.Recording) *string {
+	cryostat, err := r.FindCryostat(ctx, recording.Namespace)
+	if err != nil {
+		// Without a Cryostat, there is no timeout to enforce
+		return getFinalizerAbandonCause(recording, nil, r.Now())
+	}
+	return getFinalizerAbandonCause(recording, cryostat, r.Now())
+}
+
+// getFinalizerAbandonCause returns why the operator should give up on cleaning up in
+// Cryostat for an object being deleted, either because deletion was forced or the
+// finalizer timeout of the Cryostat has elapsed, or nil if it should keep trying
+func getFinalizerAbandonCause(obj client.Object, cryostat *operatorv1beta1.Cryostat, now time.Time) *string {
+	if obj.GetAnnotations()[operatorv1beta1.RecordingForceDeleteAnnotation] == "true" {
+		cause := fmt.Sprintf("deletion was forced with the %s annotation", operatorv1beta1.RecordingForceDeleteAnnotation)
+		return &cause
+	}
+	if cryostat == nil || cryostat.Spec.RecordingFinalizerTimeout == nil {
+		return nil
+	}
+	timeout := cryostat.Spec.RecordingFinalizerTimeout.Duration
+	if now.Before(obj.GetDeletionTimestamp().Add(timeout)) {
+		return nil
+	}
+	cause := fmt.Sprintf("cleanup did not succeed within %s", timeout)
+	return &cause
+}
+
+// emitCleanupAbandoned emits a Warning Event for an object deleted without cleaning
+// up in Cryostat, describing what may remain
+func emitCleanupAbandoned(recorder record.EventRecorder, obj client.Object, cause string, leaked string) {
+	recorder.Event(obj, corev1.EventTypeWarning, eventCleanupAbandoned,
+		fmt.Sprintf("Deleting without cleaning up in Cryostat, because %s. These may have been left behind: %s",
+			cause, leaked))
+}
+
+// abandonCleanup removes the finalizer from a recording being deleted without
+// cleaning up in Cryostat, and emits a Warning Event describing what may remain
+func (r *RecordingReconciler) abandonCleanup(ctx context.Context, recording *operatorv1beta1.Recording,
+	cause string) (reconcile.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", recording.Namespace, "Request.Name", recording.Name)
+	leaked, err := describeLeakedResources(recording)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	reqLogger.Info("giving up on cleaning up recording in Cryostat", "cause", cause, "leaked", leaked)
+	emitCleanupAbandoned(r.EventRecorder, recording, cause, leaked)
+
+	err = common.RemoveFinalizer(ctx, r.Client, recording, recordingFinalizer)
+	return reconcile.Result{}, err
+}
+
+// describeLeakedResources lists the recordings and JFR files in Cryostat that
+// may remain if the recording is deleted without cleaning up
+func describeLeakedResources(recording *operatorv1beta1.Recording) (string, error) {
+	leaked := []string{}
+	if recording.Status.State != nil && recording.Spec.FlightRecorder != nil {
+		leaked = append(leaked, fmt.Sprintf("recording \"%s\" on FlightRecorder \"%s\"", recording.Spec.Name,
+			recording.Spec.FlightRecorder.Name))
+	}
+	for _, target := range recording.Status.Targets {
+		if target.State != nil && len(target.FlightRecorder) > 0 {
+			leaked = append(leaked, fmt.Sprintf("recording \"%s\" on FlightRecorder \"%s\"", recording.Spec.Name,
+				target.FlightRecorder))
+		}
+	}
+	jfrFiles, err := archivedFilenames(recording)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(jfrFiles))
+	for jfrFile := range jfrFiles {
+		names = append(names, jfrFile)
+	}
+	// Sort for a stable message
+	sort.Strings(names)
+	for _, name := range names {
+		leaked = append(leaked, fmt.Sprintf("archived file \"%s\"", name))
+	}
+	if len(leaked) == 0 {
+		return "nothing", nil
+	}
+	return strings.Join(leaked, ", "), nil
+}
+
 func (r *RecordingReconciler) watchFlightRecorders(builder *builder.Builder, cl client.Client) *builder.Builder {
 	ctx := context.Background()
 	jfrPredicate := predicate.Funcs{
@@ -446,32 +996,7 @@ func (r *RecordingReconciler) watchFlightRecorders(builder *builder.Builder, cl
 	}
 
 	mapFunc := func(obj client.Object) []reconcile.Request {
-		// Look up all recordings that reference the changed FlightRecorder
-		recordings := &operatorv1beta1.RecordingList{}
-		selector := labels.SelectorFromSet(labels.Set{
-			operatorv1beta1.RecordingLabel: obj.GetName(),
-		})
-		err := cl.List(ctx, recordings, &client.ListOptions{
-			Namespace:     obj.GetNamespace(),
-			LabelSelector: selector,
-		})
-		if err != nil {
-			r.Log.Error(err, "Failed to list Recordings", "namespace", obj.GetNamespace(),
-				"selector", selector.String())
-		}
-
-		// Reconcile each recording that was found
-		requests := make([]reconcile.Request, len(recordings.Items))
-		for idx, recording := range recordings.Items {
-			request := reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Namespace: recording.Namespace,
-					Name:      recording.Name,
-				},
-			}
-			requests[idx] = request
-		}
-		return requests
+		return r.findRecordingsForTarget(ctx, cl, obj)
 	}
 
 	return builder.Watches(
@@ -482,6 +1007,65 @@ func (r *RecordingReconciler) watchFlightRecorders(builder *builder.Builder, cl
 	)
 }
 
+func (r *RecordingReconciler) watchTerminatingPods(b *builder.Builder, cl client.Client) *builder.Builder {
+	ctx := context.Background()
+	// Only pods that have just begun shutting down are of interest, so that
+	// running recordings can be archived before they're lost
+	podPredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetDeletionTimestamp() == nil && e.ObjectNew.GetDeletionTimestamp() != nil
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+
+	// FlightRecorders share the name of their target pod
+	mapFunc := func(obj client.Object) []reconcile.Request {
+		return r.findRecordingsForTarget(ctx, cl, obj)
+	}
+
+	return b.Watches(
+		&source.Kind{Type: &corev1.Pod{}},
+		handler.EnqueueRequestsFromMapFunc(mapFunc),
+		builder.WithPredicates(podPredicate),
+	)
+}
+
+// findRecordingsForTarget returns requests for all recordings that reference
+// the FlightRecorder with the object's name, or target a workload that may
+// include its pod
+func (r *RecordingReconciler) findRecordingsForTarget(ctx context.Context, cl client.Client,
+	obj client.Object) []reconcile.Request {
+	recordings := &operatorv1beta1.RecordingList{}
+	err := cl.List(ctx, recordings, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "Failed to list Recordings", "namespace", obj.GetNamespace())
+	}
+
+	// Reconcile each recording that was found
+	requests := []reconcile.Request{}
+	for _, recording := range recordings.Items {
+		if recording.Labels[operatorv1beta1.RecordingLabel] != obj.GetName() && !isWorkloadRecording(&recording) {
+			continue
+		}
+		request := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: recording.Namespace,
+				Name:      recording.Name,
+			},
+		}
+		requests = append(requests, request)
+	}
+	return requests
+}
+
 func (r *RecordingReconciler) findRecordingByName(cryostat cryostatClient.CryostatClient, target *cryostatClient.TargetAddress,
 	name string) (*cryostatClient.RecordingDescriptor, error) {
 	// Get an updated list of in-memory flight recordings
@@ -499,6 +1083,168 @@ func (r *RecordingReconciler) findRecordingByName(cryostat cryostatClient.Cryost
 	return nil, nil
 }
 
+// createRecording tells Cryostat to create the recording in the target JVM. If that fails
+// because the recording already exists, such as when it was created but the Recording's
+// status could not be updated afterwards, the existing recording is used instead as long
+// as it matches the Recording.
+func (r *RecordingReconciler) createRecording(cryostat cryostatClient.CryostatClient, target *cryostatClient.TargetAddress,
+	recording *operatorv1beta1.Recording, events []string, log logr.Logger) error {
+	options := getRecordingOptions(recording)
+	var err error
+	if recording.Spec.Duration.Duration == time.Duration(0) {
+		log.Info("creating new continuous recording", "name", recording.Spec.Name, "events", events)
+		err = cryostat.StartRecording(target, recording.Spec.Name, events, options)
+	} else {
+		log.Info("creating new recording", "name", recording.Spec.Name, "duration", recording.Spec.Duration, "events", events)
+		err = cryostat.DumpRecording(target, recording.Spec.Name, int(recording.Spec.Duration.Seconds()), events, options)
+	}
+	if err == nil {
+		return nil
+	}
+
+	// Check whether the recording already exists, otherwise report the original error
+	existing, listErr := r.findRecordingByName(cryostat, target, recording.Spec.Name)
+	if listErr != nil || existing == nil {
+		return err
+	}
+	if !recordingMatches(recording, existing) {
+		return &invalidRecordingError{
+			reason: reasonRecordingConflict,
+			message: fmt.Sprintf("A recording named \"%s\" already exists in the target JVM with different options",
+				recording.Spec.Name),
+		}
+	}
+	log.Info("using existing recording with the same name", "name", recording.Spec.Name)
+	return nil
+}
+
+// recordingMatches returns whether an existing recording in the target JVM
+// was created for this Recording. Recordings without the owner label were
+// started by someone else, and are never taken over.
+func recordingMatches(recording *operatorv1beta1.Recording, descriptor *cryostatClient.RecordingDescriptor) bool {
+	owner, ok := descriptor.Metadata.Labels[operatorv1beta1.RecordingOwnerLabel]
+	if !ok || owner != recording.Name {
+		return false
+	}
+	// The recording is started with its duration in whole seconds
+	duration := time.Duration(descriptor.Duration) * time.Millisecond
+	if duration/time.Second != recording.Spec.Duration.Duration/time.Second {
+		return false
+	}
+	options := getRecordingOptions(recording)
+	if options.ToDisk != nil && *options.ToDisk != descriptor.ToDisk {
+		return false
+	}
+	if options.MaxSize != nil && *options.MaxSize != descriptor.MaxSize {
+		return false
+	}
+	if options.MaxAge != nil && *options.MaxAge*int64(time.Second/time.Millisecond) != descriptor.MaxAge {
+		return false
+	}
+	return true
+}
+
+func getRecordingOptions(recording *operatorv1beta1.Recording) *cryostatClient.RecordingOptions {
+	options := &cryostatClient.RecordingOptions{
+		ToDisk: recording.Spec.ToDisk,
+		Labels: map[string]string{
+			operatorv1beta1.RecordingOwnerLabel: recording.Name,
+		},
+	}
+	if recording.Spec.MaxSize != nil {
+		maxSize := recording.Spec.MaxSize.Value()
+		options.MaxSize = &maxSize
+	}
+	if recording.Spec.MaxAge != nil {
+		maxAge := int64(recording.Spec.MaxAge.Seconds())
+		options.MaxAge = &maxAge
+	}
+	return options
+}
+
+func getRecordingEvents(ctx context.Context, c client.Client, recording *operatorv1beta1.Recording,
+	jfr *operatorv1beta1.FlightRecorder) ([]string, error) {
+	template := recording.Spec.Template
+	if template == nil {
+		typed, err := getTypedEventOptions(ctx, c, recording.Spec.Events, jfr)
+		if err != nil {
+			return nil, err
+		}
+		events := make([]string, 0, len(recording.Spec.EventOptions)+len(typed))
+		events = append(events, recording.Spec.EventOptions...)
+		return append(events, typed...), nil
+	}
+	if len(recording.Spec.EventOptions) > 0 || len(recording.Spec.Events) > 0 {
+		return nil, &invalidRecordingError{
+			reason:  reasonInvalidSpec,
+			message: "spec.template cannot be used together with spec.eventOptions or spec.events",
+		}
+	}
+
+	return getTemplateEvents(template, jfr)
+}
+
+func getTemplateEvents(template *operatorv1beta1.TemplateReference, jfr *operatorv1beta1.FlightRecorder) ([]string, error) {
+	// Look for the requested template in those available to the target JVM
+	templateType := template.Type
+	if len(templateType) == 0 {
+		templateType = operatorv1beta1.TemplateTypeTarget
+	}
+	for _, available := range jfr.Status.Templates {
+		if available.Name == template.Name && available.Type == templateType {
+			return cryostatClient.TemplateEvents(template.Name, templateType), nil
+		}
+	}
+	return nil, &invalidRecordingError{
+		reason: reasonTemplateNotFound,
+		message: fmt.Sprintf("Template \"%s\" of type %s is not available for FlightRecorder \"%s\"",
+			template.Name, templateType, jfr.Name),
+	}
+}
+
+func getTypedEventOptions(ctx context.Context, c client.Client, options []operatorv1beta1.EventOption,
+	jfr *operatorv1beta1.FlightRecorder) ([]string, error) {
+	if len(options) == 0 {
+		return []string{}, nil
+	}
+
+	// Index the event types available in the target JVM
+	available, err := getEventTypes(ctx, c, jfr)
+	if err != nil {
+		return nil, err
+	}
+	eventTypes := make(map[string]*operatorv1beta1.EventInfo, len(available))
+	for idx, event := range available {
+		eventTypes[event.TypeID] = &available[idx]
+	}
+
+	// Check every option, so that all problems can be reported at once
+	result := make([]string, 0, len(options))
+	problems := []string{}
+	for _, option := range options {
+		eventType, pres := eventTypes[option.TypeID]
+		if !pres {
+			problems = append(problems, fmt.Sprintf("event type \"%s\" is not available", option.TypeID))
+			continue
+		}
+		if _, pres := eventType.Options[option.Option]; !pres {
+			problems = append(problems, fmt.Sprintf("event type \"%s\" has no option \"%s\"", option.TypeID,
+				option.Option))
+			continue
+		}
+		result = append(result, fmt.Sprintf("%s:%s=%s", option.TypeID, option.Option, option.Value))
+	}
+
+	if len(problems) > 0 {
+		return nil, &invalidRecordingError{
+			reason: reasonInvalidEventOptions,
+			message: fmt.Sprintf("Invalid event options for FlightRecorder \"%s\": %s", jfr.Name,
+				strings.Join(problems, ", ")),
+		}
+	}
+	return result, nil
+}
+
 func validateRecordingState(state string) (*operatorv1beta1.RecordingState, error) {
 	convState := operatorv1beta1.RecordingState(state)
 	switch convState {
@@ -521,23 +1267,238 @@ func recordingFilename(recordingURL string) (*string, error) {
 	return &jfrFile, nil
 }
 
-func shouldStopRecording(recording *operatorv1beta1.Recording) bool {
+func shouldStopRecording(recording *operatorv1beta1.Recording, now time.Time) bool {
 	// Need to know user's request, and current state of recording
-	requested := recording.Spec.State
 	current := recording.Status.State
-	if requested == nil || current == nil {
+	if !isStopRequested(recording, now) || !hasRecordingStarted(recording) {
 		return false
 	}
 
 	// Should stop if user wants recording stopped and we're not already doing/done so
-	return *requested == operatorv1beta1.RecordingStateStopped && *current != operatorv1beta1.RecordingStateStopped &&
-		*current != operatorv1beta1.RecordingStateStopping
+	return *current != operatorv1beta1.RecordingStateStopped && *current != operatorv1beta1.RecordingStateStopping
+}
+
+// isStopRequested returns whether the user has asked for the recording to be
+// stopped, or its StopAt time has passed
+func isStopRequested(recording *operatorv1beta1.Recording, now time.Time) bool {
+	requested := recording.Spec.State
+	if requested != nil && *requested == operatorv1beta1.RecordingStateStopped {
+		return true
+	}
+	stopAt := recording.Spec.StopAt
+	return stopAt != nil && !now.Before(stopAt.Time)
+}
+
+// recordingSpecHash returns a hash of the spec fields that the recording in
+// the target JVM is created from, and cannot be changed afterwards
+func recordingSpecHash(recording *operatorv1beta1.Recording) (string, error) {
+	spec := recording.Spec
+	fields, err := json.Marshal(&operatorv1beta1.RecordingSpec{
+		EventOptions: spec.EventOptions,
+		Events:       spec.Events,
+		Template:     spec.Template,
+		Duration:     spec.Duration,
+		MaxSize:      spec.MaxSize,
+		MaxAge:       spec.MaxAge,
+		ToDisk:       spec.ToDisk,
+	})
+	if err != nil {
+		return "", err
+	}
+	hash := fnv.New64a()
+	hash.Write(fields)
+	return fmt.Sprintf("%016x", hash.Sum64()), nil
+}
+
+// setRecordingGeneration records that the recording in the target JVM
+// was created from the current spec
+func setRecordingGeneration(recording *operatorv1beta1.Recording) error {
+	hash, err := recordingSpecHash(recording)
+	if err != nil {
+		return err
+	}
+	recording.Status.RecordingGeneration = recording.Generation
+	recording.Status.RecordingSpecHash = hash
+	return nil
+}
+
+// checkSpecChange compares the spec with the one the recording was created from.
+// Changes that can be applied to the existing recording are marked as recorded.
+// Otherwise, the recording should either be restarted, or the changes rejected
+// with the returned message, depending on its restart policy.
+func (r *RecordingReconciler) checkSpecChange(recording *operatorv1beta1.Recording) (restart bool,
+	rejected string, err error) {
+	hash, err := recordingSpecHash(recording)
+	if err != nil {
+		return false, "", err
+	}
+	if len(recording.Status.RecordingSpecHash) == 0 || hash == recording.Status.RecordingSpecHash {
+		// Recordings created before the spec was tracked are assumed to be up to date
+		return false, "", setRecordingGeneration(recording)
+	}
+	if recording.Spec.RestartPolicy != operatorv1beta1.RecordingRestartPolicyOnSpecChange {
+		return false, "Changes to eventOptions, events, template, duration, maxSize, maxAge and toDisk " +
+			"are not applied to a recording that has already started, unless spec.restartPolicy is OnSpecChange", nil
+	}
+	if recording.Status.CompletionTime != nil || isStopRequested(recording, r.Now()) {
+		return false, "Changes to eventOptions, events, template, duration, maxSize, maxAge and toDisk " +
+			"are not applied to a recording that has stopped", nil
+	}
+	return true, "", nil
+}
+
+// restartRecording stops the recording in the target JVM and archives it if requested,
+// then deletes it so that it can be created again from the current spec
+func (r *RecordingReconciler) restartRecording(ctx context.Context, cryostat cryostatClient.CryostatClient,
+	target *cryostatClient.TargetAddress, recording *operatorv1beta1.Recording, log logr.Logger) error {
+	existing, err := r.findRecordingByName(cryostat, target, recording.Spec.Name)
+	if err != nil || existing == nil {
+		return err
+	}
+	log.Info("restarting recording to apply spec changes", "name", recording.Spec.Name)
+	if existing.State == string(operatorv1beta1.RecordingStateRunning) {
+		err = cryostat.StopRecording(target, recording.Spec.Name)
+		if err != nil {
+			return err
+		}
+	}
+	if recording.Spec.Archive {
+		err = r.saveSnapshot(ctx, cryostat, recording, target, operatorv1beta1.ArchivedSnapshotReasonSpecChanged)
+		if err != nil {
+			return err
+		}
+	}
+	return cryostat.DeleteRecording(target, recording.Spec.Name)
+}
+
+// resetRecordingStatus clears the status of a recording that was restarted,
+// so that it is created again
+func resetRecordingStatus(recording *operatorv1beta1.Recording) {
+	status := &recording.Status
+	status.State = nil
+	status.StartTime = metav1.Time{}
+	status.Duration = metav1.Duration{}
+	status.MaxSize = nil
+	status.MaxAge = nil
+	status.ToDisk = nil
+	status.DownloadURL = nil
+	status.ReportURL = nil
+}
+
+// isRecordingAdopted returns whether the recording was started outside of
+// the operator, and should not be created again
+func isRecordingAdopted(recording *operatorv1beta1.Recording) bool {
+	return recording.Annotations[operatorv1beta1.RecordingAdoptedAnnotation] == "true"
+}
+
+// hasRecordingStarted returns whether the recording has been created in any
+// target JVM
+func hasRecordingStarted(recording *operatorv1beta1.Recording) bool {
+	state := recording.Status.State
+	return (state != nil && *state != operatorv1beta1.RecordingStateScheduled) || len(recording.Status.Targets) > 0
+}
+
+// checkRecordingSchedule marks a recording as scheduled until its StartAt time,
+// and returns true along with when to requeue if it should not be created yet
+func (r *RecordingReconciler) checkRecordingSchedule(ctx context.Context,
+	recording *operatorv1beta1.Recording) (reconcile.Result, bool, error) {
+	startAt := recording.Spec.StartAt
+	stopAt := recording.Spec.StopAt
+	if startAt != nil && stopAt != nil && !stopAt.After(startAt.Time) {
+		result, err := r.recordingInvalid(ctx, recording, &invalidRecordingError{
+			reason:  reasonInvalidSpec,
+			message: "spec.stopAt must be later than spec.startAt",
+		})
+		return result, true, err
+	}
+	now := r.Now()
+	if stopAt != nil && !now.Before(stopAt.Time) {
+		recording.Status.State = nil
+		result, err := r.recordingInvalid(ctx, recording, &invalidRecordingError{
+			reason:  reasonInvalidSpec,
+			message: "spec.stopAt passed before the recording could be started",
+		})
+		return result, true, err
+	}
+	if startAt == nil || !now.Before(startAt.Time) {
+		return reconcile.Result{}, false, nil
+	}
+
+	r.Log.Info("recording is scheduled", "name", recording.Spec.Name, "startAt", startAt)
+	scheduled := operatorv1beta1.RecordingStateScheduled
+	recording.Status.State = &scheduled
+	setRecordingCondition(recording, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse,
+		reasonRecordingScheduled, fmt.Sprintf("Recording will start at %s", startAt.UTC().Format(time.RFC3339)))
+	err := r.updateRecordingStatus(ctx, recording)
+	return reconcile.Result{RequeueAfter: startAt.Sub(now)}, true, err
 }
 
-func (r *RecordingReconciler) requeueIfNotReady(err error) (reconcile.Result, error) {
+func (r *RecordingReconciler) requeueIfNotReady(ctx context.Context, recording *operatorv1beta1.Recording,
+	err error) (reconcile.Result, error) {
 	if err == common.ErrCertNotReady {
 		r.Log.Info("Waiting for CA certificate")
-		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		setRecordingCondition(recording, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse,
+			reasonCryostatNotReady, "Waiting for Cryostat CA certificate")
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, r.updateRecordingStatus(ctx, recording)
 	}
+	return r.recordingFailed(ctx, recording, reasonCryostatUnavailable, err)
+}
+
+// recordingFailed marks the recording as failed for the provided reason, and returns
+// the error that caused the failure so that the request is requeued
+func (r *RecordingReconciler) recordingFailed(ctx context.Context, recording *operatorv1beta1.Recording,
+	reason string, err error) (reconcile.Result, error) {
+	setRecordingCondition(recording, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, reason, err.Error())
+	setRecordingCondition(recording, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse, reason, err.Error())
+	r.logStatusError(r.updateRecordingStatus(ctx, recording), recording)
 	return reconcile.Result{}, err
 }
+
+// recordingInvalid marks the recording as failed due to a problem with its spec.
+// Since retrying will not help, the request is not requeued. The recording is
+// reconciled again once it or its FlightRecorder changes.
+func (r *RecordingReconciler) recordingInvalid(ctx context.Context, recording *operatorv1beta1.Recording,
+	err error) (reconcile.Result, error) {
+	invalid, ok := err.(*invalidRecordingError)
+	if !ok {
+		return r.recordingFailed(ctx, recording, reasonInternalError, err)
+	}
+	r.Log.Info("recording spec is invalid", "namespace", recording.Namespace, "name", recording.Name,
+		"reason", invalid.reason, "message", invalid.message)
+	setRecordingCondition(recording, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue,
+		invalid.reason, invalid.message)
+	setRecordingCondition(recording, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse,
+		invalid.reason, invalid.message)
+	return reconcile.Result{}, r.updateRecordingStatus(ctx, recording)
+}
+
+func (r *RecordingReconciler) updateRecordingStatus(ctx context.Context, recording *operatorv1beta1.Recording) error {
+	recording.Status.ObservedGeneration = recording.Generation
+	return r.Client.Status().Update(ctx, recording)
+}
+
+func (r *RecordingReconciler) logStatusError(err error, recording *operatorv1beta1.Recording) {
+	// Don't mask the original error if the status update also fails
+	if err != nil {
+		r.Log.Error(err, "failed to update Recording status", "namespace", recording.Namespace,
+			"name", recording.Name)
+	}
+}
+
+func setTargetUnavailable(recording *operatorv1beta1.Recording, reason string, message string) {
+	setRecordingCondition(recording, operatorv1beta1.RecordingConditionTargetAvailable, metav1.ConditionFalse,
+		reason, message)
+	setRecordingCondition(recording, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse,
+		reason, message)
+}
+
+func setRecordingCondition(recording *operatorv1beta1.Recording, condType string, status metav1.ConditionStatus,
+	reason string, message string) {
+	meta.SetStatusCondition(&recording.Status.Conditions, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		ObservedGeneration: recording.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -44,9 +44,12 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -76,10 +79,11 @@ var _ = Describe("RecordingController", func() {
 		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
 		t.Server = test.NewServer(t.Client, t.handlers, t.TLS)
 		t.controller = &controllers.RecordingReconciler{
-			Client:     t.Client,
-			Scheme:     s,
-			Log:        logger,
-			Reconciler: test.NewTestReconciler(&t.TestReconcilerConfig),
+			Client:        t.Client,
+			Scheme:        s,
+			Log:           logger,
+			EventRecorder: record.NewFakeRecorder(1024),
+			Reconciler:    test.NewTestReconciler(&t.TestReconcilerConfig),
 		}
 	})
 
@@ -124,17 +128,277 @@ var _ = Describe("RecordingController", func() {
 			It("should requeue after 10 seconds", func() {
 				t.expectRecordingResult(reconcile.Result{RequeueAfter: 10 * time.Second})
 			})
+			It("should set conditions", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionTrue, "RecordingFound")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionTargetAvailable, metav1.ConditionTrue, "TargetFound")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse, "ArchiveNotRequested")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionFalse, "RecordingReconciled")
+			})
+			It("should set observed generation", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.ObservedGeneration).To(Equal(obj.Generation))
+			})
+			It("should set recording generation", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.RecordingGeneration).To(Equal(obj.Generation))
+				Expect(obj.Status.RecordingSpecHash).ToNot(BeEmpty())
+			})
+		})
+		Context("with a new recording with size and age limits", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecordingWithOptions())
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpWithOptionsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptorsWithOptions("RUNNING", 30000)),
+				}
+			})
+			It("updates status with recording info", func() {
+				desc := test.NewRecordingDescriptorsWithOptions("RUNNING", 30000)[0]
+				t.expectRecordingUpdated(&desc)
+			})
 		})
 		Context("with a new recording that fails", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, test.NewRecording())
 				t.handlers = []http.HandlerFunc{
 					test.NewDumpFailHandler(),
+					test.NewListHandler([]cryostatClient.RecordingDescriptor{}),
 				}
 			})
 			It("should requeue with error", func() {
 				t.expectRecordingReconcileError()
 			})
+			It("should set Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "CreateFailed")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse, "CreateFailed")
+			})
+		})
+		Context("with a new recording that already exists in the JVM", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpFailHandler(),
+					test.NewListHandler(test.NewOwnedRecordingDescriptors("RUNNING", 30000, "my-recording")),
+					test.NewListHandler(test.NewOwnedRecordingDescriptors("RUNNING", 30000, "my-recording")),
+				}
+			})
+			It("should use the existing recording", func() {
+				desc := test.NewRecordingDescriptors("RUNNING", 30000)[0]
+				t.expectRecordingUpdated(&desc)
+			})
+			It("should set conditions", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionTrue, "RecordingFound")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionFalse, "RecordingReconciled")
+			})
+		})
+		Context("with a new recording to archive that already exists and has stopped in the JVM", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecordingToArchive())
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpFailHandler(),
+					test.NewListHandler(test.NewOwnedRecordingDescriptors("STOPPED", 30000, "my-recording")),
+					test.NewListHandler(test.NewOwnedRecordingDescriptors("STOPPED", 30000, "my-recording")),
+					test.NewListSavedHandler([]cryostatClient.SavedRecording{}),
+					test.NewSaveHandler(),
+					test.NewListSavedHandler(test.NewSavedRecordings()),
+				}
+			})
+			It("should archive the existing recording", func() {
+				obj := t.reconcileRecordingAndGet()
+				saved := test.NewSavedRecordings()[0]
+				Expect(obj.Status.DownloadURL).To(Equal(&saved.DownloadURL))
+				Expect(obj.Status.ReportURL).To(Equal(&saved.ReportURL))
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionArchived, metav1.ConditionTrue, "RecordingArchived")
+			})
+		})
+		Context("with a new recording with a sub-second duration that already exists in the JVM", func() {
+			BeforeEach(func() {
+				rec := test.NewRecording()
+				rec.Spec.Duration = metav1.Duration{Duration: 30*time.Second + 500*time.Millisecond}
+				t.objs = append(t.objs, rec)
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpFailHandler(),
+					test.NewListHandler(test.NewOwnedRecordingDescriptors("RUNNING", 30000, "my-recording")),
+					test.NewListHandler(test.NewOwnedRecordingDescriptors("RUNNING", 30000, "my-recording")),
+				}
+			})
+			It("should use the existing recording", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionTrue, "RecordingFound")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionFalse, "RecordingReconciled")
+			})
+		})
+		Context("with a new recording that already exists in the JVM without an owner", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpFailHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should not use the existing recording", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "RecordingConflict")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse, "RecordingConflict")
+			})
+		})
+		Context("with a new recording that already exists in the JVM with different options", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpFailHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+				}
+			})
+			It("should not requeue", func() {
+				t.expectRecordingResult(reconcile.Result{})
+			})
+			It("should set Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "RecordingConflict")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse, "RecordingConflict")
+			})
+		})
+		Context("with a new recording that already exists in the JVM for another Recording", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpFailHandler(),
+					test.NewListHandler(test.NewOwnedRecordingDescriptors("RUNNING", 30000, "other-recording")),
+				}
+			})
+			It("should set Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "RecordingConflict")
+			})
+		})
+		Context("with a new recording using a template", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithTemplates(),
+					test.NewTargetPod(), test.NewCryostatService(), test.NewJMXAuthSecret(),
+					test.NewRecordingWithTemplate("Profiling"),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpWithTemplateHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("updates status with recording info", func() {
+				desc := test.NewRecordingDescriptors("RUNNING", 30000)[0]
+				t.expectRecordingUpdated(&desc)
+			})
+		})
+		Context("with a new recording using a missing template", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithTemplates(),
+					test.NewTargetPod(), test.NewCryostatService(), test.NewJMXAuthSecret(),
+					test.NewRecordingWithTemplate("Continuous"),
+				}
+			})
+			It("should not requeue", func() {
+				t.expectRecordingResult(reconcile.Result{})
+			})
+			It("should set Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "TemplateNotFound")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse, "TemplateNotFound")
+				Expect(obj.Status.State).To(BeNil())
+			})
+		})
+		Context("with a new recording using both a template and event options", func() {
+			BeforeEach(func() {
+				rec := test.NewRecordingWithTemplate("Profiling")
+				rec.Spec.EventOptions = test.NewRecording().Spec.EventOptions
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithTemplates(),
+					test.NewTargetPod(), test.NewCryostatService(), test.NewJMXAuthSecret(), rec,
+				}
+			})
+			It("should set Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "InvalidSpec")
+				Expect(obj.Status.State).To(BeNil())
+			})
+		})
+		Context("with a new recording using typed event options", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithEvents(),
+					test.NewTargetPod(), test.NewCryostatService(), test.NewJMXAuthSecret(),
+					test.NewRecordingWithTypedEvents("jdk.socketRead", "stackTrace"),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpWithTypedEventsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("updates status with recording info", func() {
+				desc := test.NewRecordingDescriptors("RUNNING", 30000)[0]
+				t.expectRecordingUpdated(&desc)
+			})
+		})
+		Context("with a new recording using typed event options and an event catalog", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithEventCatalog(),
+					test.NewTargetPod(), test.NewCryostatService(), test.NewJMXAuthSecret(),
+					test.NewRecordingWithTypedEvents("jdk.socketRead", "stackTrace"),
+					test.NewEventCatalogConfigMap(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpWithTypedEventsHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("updates status with recording info", func() {
+				desc := test.NewRecordingDescriptors("RUNNING", 30000)[0]
+				t.expectRecordingUpdated(&desc)
+			})
+			Context("that does not exist", func() {
+				BeforeEach(func() {
+					t.objs = t.objs[:len(t.objs)-1]
+					t.handlers = nil
+				})
+				It("should requeue with error", func() {
+					t.expectRecordingReconcileError()
+				})
+			})
+		})
+		Context("with a new recording using an unknown event type", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithEvents(),
+					test.NewTargetPod(), test.NewCryostatService(), test.NewJMXAuthSecret(),
+					test.NewRecordingWithTypedEvents("jdk.socketReed", "enabled"),
+				}
+			})
+			It("should set Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "InvalidEventOptions")
+				condition := meta.FindStatusCondition(obj.Status.Conditions, operatorv1beta1.RecordingConditionFailed)
+				Expect(condition.Message).To(ContainSubstring("jdk.socketReed"))
+				Expect(obj.Status.State).To(BeNil())
+			})
+		})
+		Context("with a new recording using an unknown event option", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithEvents(),
+					test.NewTargetPod(), test.NewCryostatService(), test.NewJMXAuthSecret(),
+					test.NewRecordingWithTypedEvents("jdk.socketRead", "period"),
+				}
+			})
+			It("should set Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "InvalidEventOptions")
+				condition := meta.FindStatusCondition(obj.Status.Conditions, operatorv1beta1.RecordingConditionFailed)
+				Expect(condition.Message).To(ContainSubstring("period"))
+			})
 		})
 		Context("with a new continuous recording", func() {
 			BeforeEach(func() {
@@ -157,12 +421,32 @@ var _ = Describe("RecordingController", func() {
 				t.objs = append(t.objs, test.NewContinuousRecording())
 				t.handlers = []http.HandlerFunc{
 					test.NewStartFailHandler(),
+					test.NewListHandler([]cryostatClient.RecordingDescriptor{}),
 				}
 			})
 			It("should requeue with error", func() {
 				t.expectRecordingReconcileError()
 			})
 		})
+		Context("with a new adopted recording", func() {
+			BeforeEach(func() {
+				rec := test.NewRecording()
+				rec.Annotations = map[string]string{
+					operatorv1beta1.RecordingAdoptedAnnotation: "true",
+				}
+				t.objs = append(t.objs, rec)
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should use the existing recording in the JVM", func() {
+				desc := test.NewRecordingDescriptors("RUNNING", 30000)[0]
+				t.expectRecordingUpdated(&desc)
+			})
+			It("adds finalizer to recording", func() {
+				t.expectRecordingFinalizerPresent()
+			})
+		})
 		Context("with a running recording", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, test.NewRunningRecording())
@@ -177,6 +461,251 @@ var _ = Describe("RecordingController", func() {
 				t.expectRecordingResult(reconcile.Result{RequeueAfter: 10 * time.Second})
 			})
 		})
+		Context("with a running recording whose spec has changed", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecordingWithChangedSpec("", false))
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should keep the existing recording", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.RecordingGeneration).To(Equal(int64(1)))
+				Expect(obj.Status.Duration).To(Equal(metav1.Duration{Duration: 30 * time.Second}))
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionTrue, "RecordingFound")
+			})
+			It("should reject the change", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "SpecChangeRejected")
+			})
+			It("should requeue after 10 seconds", func() {
+				t.expectRecordingResult(reconcile.Result{RequeueAfter: 10 * time.Second})
+			})
+		})
+		Context("with a running recording whose spec has changed with restartPolicy OnSpecChange", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecordingWithChangedSpec(operatorv1beta1.RecordingRestartPolicyOnSpecChange, false))
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+					test.NewStopHandler(),
+					test.NewDeleteHandler(),
+					test.NewStartHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+				}
+			})
+			It("should recreate the recording", func() {
+				desc := test.NewRecordingDescriptors("RUNNING", 0)[0]
+				t.expectRecordingUpdated(&desc)
+			})
+			It("should set recording generation", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.RecordingGeneration).To(Equal(int64(2)))
+				Expect(obj.Status.RecordingSpecHash).ToNot(Equal("0123456789abcdef"))
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionFalse, "RecordingReconciled")
+			})
+			Context("and archiving requested", func() {
+				BeforeEach(func() {
+					t.objs[len(t.objs)-1] = test.NewRecordingWithChangedSpec(operatorv1beta1.RecordingRestartPolicyOnSpecChange, true)
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+						test.NewStopHandler(),
+						test.NewSaveHandler(),
+						test.NewListSavedHandler(test.NewSavedRecordings()),
+						test.NewDeleteHandler(),
+						test.NewStartHandler(),
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+					}
+				})
+				It("should archive the previous recording", func() {
+					obj := t.reconcileRecordingAndGet()
+					Expect(obj.Status.ArchivedSnapshots).To(HaveLen(1))
+					snapshot := obj.Status.ArchivedSnapshots[0]
+					Expect(snapshot.Name).To(Equal(test.NewSavedRecordings()[0].Name))
+					Expect(snapshot.Reason).To(Equal(operatorv1beta1.ArchivedSnapshotReasonSpecChanged))
+				})
+			})
+			Context("after the recording has stopped", func() {
+				BeforeEach(func() {
+					rec := test.NewRecordingWithChangedSpec(operatorv1beta1.RecordingRestartPolicyOnSpecChange, false)
+					stopped := operatorv1beta1.RecordingStateStopped
+					rec.Status.State = &stopped
+					rec.Status.CompletionTime = &metav1.Time{Time: test.NewRecordingCompletionTime()}
+					t.objs[len(t.objs)-1] = rec
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 30000)),
+					}
+				})
+				It("should reject the change", func() {
+					obj := t.reconcileRecordingAndGet()
+					Expect(obj.Status.RecordingGeneration).To(Equal(int64(1)))
+					expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "SpecChangeRejected")
+				})
+			})
+		})
+		Context("with a running recording when the target pod is terminating", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorder(),
+					test.NewTerminatingTargetPod(), test.NewCryostatService(), test.NewJMXAuthSecret(),
+				}
+				now := test.NewPodTerminationTime().Add(5 * time.Second)
+				t.Now = &now
+			})
+			Context("that has not been saved yet", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewRunningContinuousRecording())
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+						test.NewSaveHandler(),
+						test.NewListSavedHandler(test.NewSavedRecordings()),
+					}
+				})
+				It("should add the saved copy to status", func() {
+					obj := t.reconcileRecordingAndGet()
+					saved := test.NewSavedRecordings()[0]
+					Expect(obj.Status.ArchivedSnapshots).To(HaveLen(1))
+					snapshot := obj.Status.ArchivedSnapshots[0]
+					Expect(snapshot.Name).To(Equal(saved.Name))
+					Expect(snapshot.DownloadURL).To(Equal(saved.DownloadURL))
+					Expect(snapshot.ReportURL).To(Equal(saved.ReportURL))
+					Expect(snapshot.Reason).To(Equal(operatorv1beta1.ArchivedSnapshotReasonTargetTerminating))
+					Expect(snapshot.Time.Time).To(BeTemporally("~", *t.Now, time.Second))
+				})
+				It("should requeue after 10 seconds", func() {
+					result := t.reconcileRecording()
+					Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+				})
+			})
+			Context("that has already been saved", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewRunningRecordingWithTerminationSnapshot())
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+					}
+				})
+				It("should not save the recording again", func() {
+					obj := t.reconcileRecordingAndGet()
+					Expect(obj.Status.ArchivedSnapshots).To(HaveLen(1))
+				})
+			})
+			Context("when saving fails", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewRunningContinuousRecording())
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+						test.NewSaveFailHandler(),
+					}
+				})
+				It("should set Failed condition", func() {
+					obj := t.reconcileRecordingAndGet()
+					expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "ArchiveFailed")
+				})
+				It("should requeue with error", func() {
+					t.expectRecordingReconcileError()
+				})
+			})
+		})
+		Context("with a running recording to be archived periodically", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRunningRecordingWithArchiveInterval())
+			})
+			Context("when a copy is due", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+						test.NewSaveHandler(),
+						test.NewListSavedHandler(test.NewSavedRecordings()),
+					}
+					now := time.Unix(0, 1597090030341*int64(time.Millisecond)).Add(90 * time.Minute)
+					t.Now = &now
+				})
+				It("should add the copy to status", func() {
+					obj := t.reconcileRecordingAndGet()
+					saved := test.NewSavedRecordings()[0]
+					Expect(obj.Status.ArchivedSnapshots).To(HaveLen(1))
+					snapshot := obj.Status.ArchivedSnapshots[0]
+					Expect(snapshot.Name).To(Equal(saved.Name))
+					Expect(snapshot.DownloadURL).To(Equal(saved.DownloadURL))
+					Expect(snapshot.ReportURL).To(Equal(saved.ReportURL))
+					// Converted to RFC3339 during serialization (sub-second precision lost)
+					Expect(snapshot.Time.Time).To(BeTemporally("~", *t.Now, time.Second))
+				})
+				It("should not change the download URL", func() {
+					obj := t.reconcileRecordingAndGet()
+					Expect(obj.Status.DownloadURL).ToNot(BeNil())
+					Expect(*obj.Status.DownloadURL).To(Equal("http://path/to/test-recording.jfr"))
+				})
+				It("should requeue after 10 seconds", func() {
+					result := t.reconcileRecording()
+					Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+				})
+			})
+			Context("when a copy is due with the maximum number of copies listed", func() {
+				BeforeEach(func() {
+					t.objs[len(t.objs)-1] = test.NewRunningRecordingWithSnapshots(10)
+					saved := append(test.NewSavedRecordings(), cryostatClient.SavedRecording{
+						Name:        "snapshot-0-test-recording.jfr",
+						DownloadURL: "http://path/to/snapshot-0-test-recording.jfr",
+						ReportURL:   "http://path/to/snapshot-0-test-recording.html",
+					})
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+						test.NewSaveHandler(),
+						test.NewListSavedHandler(test.NewSavedRecordings()),
+						test.NewListSavedHandler(saved),
+						test.NewDeleteSavedNamedHandler("snapshot-0-test-recording.jfr"),
+					}
+					now := time.Unix(0, 1597090030341*int64(time.Millisecond)).Add(90 * time.Minute)
+					t.Now = &now
+				})
+				It("should remove the oldest copy from status", func() {
+					obj := t.reconcileRecordingAndGet()
+					snapshots := obj.Status.ArchivedSnapshots
+					Expect(snapshots).To(HaveLen(10))
+					Expect(snapshots[0].Name).To(Equal("snapshot-1-test-recording.jfr"))
+					Expect(snapshots[9].Name).To(Equal(test.NewSavedRecordings()[0].Name))
+				})
+				Context("when deleting the oldest copy fails", func() {
+					BeforeEach(func() {
+						t.handlers[4] = test.NewDeleteSavedNamedFailHandler("snapshot-0-test-recording.jfr")
+					})
+					It("should keep the oldest copy in status", func() {
+						obj := t.reconcileRecordingAndGet()
+						snapshots := obj.Status.ArchivedSnapshots
+						Expect(snapshots).To(HaveLen(11))
+						Expect(snapshots[0].Name).To(Equal("snapshot-0-test-recording.jfr"))
+					})
+				})
+			})
+			Context("when a copy is not yet due", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+					}
+					now := time.Unix(0, 1597090030341*int64(time.Millisecond)).Add(30 * time.Minute)
+					t.Now = &now
+				})
+				It("should not save the recording", func() {
+					obj := t.reconcileRecordingAndGet()
+					Expect(obj.Status.ArchivedSnapshots).To(BeEmpty())
+				})
+			})
+			Context("when saving a copy fails", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+						test.NewSaveFailHandler(),
+					}
+					now := time.Unix(0, 1597090030341*int64(time.Millisecond)).Add(90 * time.Minute)
+					t.Now = &now
+				})
+				It("should set Archived and Failed conditions", func() {
+					obj := t.reconcileRecordingAndGet()
+					expectRecordingCondition(obj, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse, "ArchiveFailed")
+					expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "ArchiveFailed")
+				})
+			})
+		})
 		Context("with a running recording not found in Cryostat", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, test.NewRunningRecording())
@@ -190,6 +719,10 @@ var _ = Describe("RecordingController", func() {
 			It("should requeue after 10 seconds", func() {
 				t.expectRecordingResult(reconcile.Result{RequeueAfter: 10 * time.Second})
 			})
+			It("should set Ready condition to false", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse, "RecordingNotFound")
+			})
 		})
 		Context("when listing recordings fail", func() {
 			BeforeEach(func() {
@@ -212,6 +745,10 @@ var _ = Describe("RecordingController", func() {
 			It("should requeue with error", func() {
 				t.expectRecordingReconcileError()
 			})
+			It("should set Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "UnknownState")
+			})
 		})
 		Context("with a running recording to be stopped", func() {
 			BeforeEach(func() {
@@ -240,6 +777,104 @@ var _ = Describe("RecordingController", func() {
 				t.expectRecordingReconcileError()
 			})
 		})
+		Context("with a recording scheduled to start later", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewScheduledRecording())
+				now := test.NewRecordingWindowStart().Add(-time.Hour)
+				t.Now = &now
+			})
+			It("should not create the recording yet", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.State).ToNot(BeNil())
+				Expect(*obj.Status.State).To(Equal(operatorv1beta1.RecordingStateScheduled))
+				Expect(obj.Finalizers).To(BeEmpty())
+			})
+			It("should set conditions", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse, "RecordingScheduled")
+			})
+			It("should requeue at the start time", func() {
+				result := t.reconcileRecording()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Hour}))
+			})
+		})
+		Context("with a scheduled recording at its start time", func() {
+			BeforeEach(func() {
+				rec := test.NewScheduledRecording()
+				scheduled := operatorv1beta1.RecordingStateScheduled
+				rec.Status.State = &scheduled
+				t.objs = append(t.objs, rec)
+				t.handlers = []http.HandlerFunc{
+					test.NewStartHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+				}
+				now := test.NewRecordingWindowStart()
+				t.Now = &now
+			})
+			It("should create the recording", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.State).ToNot(BeNil())
+				Expect(*obj.Status.State).To(Equal(operatorv1beta1.RecordingStateRunning))
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionTrue, "RecordingFound")
+			})
+		})
+		Context("with a scheduled recording before its stop time", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRunningScheduledRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+				}
+				now := test.NewRecordingWindowStart().Add(30 * time.Minute)
+				t.Now = &now
+			})
+			It("should not stop the recording", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(*obj.Status.State).To(Equal(operatorv1beta1.RecordingStateRunning))
+			})
+		})
+		Context("with a scheduled recording at its stop time", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRunningScheduledRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewStopHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 0)),
+				}
+				now := test.NewRecordingWindowStart().Add(time.Hour)
+				t.Now = &now
+			})
+			It("should stop the recording", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(*obj.Status.State).To(Equal(operatorv1beta1.RecordingStateStopped))
+			})
+		})
+		Context("with a scheduled recording that stops before it starts", func() {
+			BeforeEach(func() {
+				rec := test.NewScheduledRecording()
+				stopAt := metav1.NewTime(test.NewRecordingWindowStart().Add(-time.Minute))
+				rec.Spec.StopAt = &stopAt
+				t.objs = append(t.objs, rec)
+			})
+			It("should set Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "InvalidSpec")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse, "InvalidSpec")
+			})
+		})
+		Context("with a scheduled recording whose stop time has passed", func() {
+			BeforeEach(func() {
+				rec := test.NewScheduledRecording()
+				scheduled := operatorv1beta1.RecordingStateScheduled
+				rec.Status.State = &scheduled
+				t.objs = append(t.objs, rec)
+				now := test.NewRecordingWindowStart().Add(2 * time.Hour)
+				t.Now = &now
+			})
+			It("should not create the recording", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.State).To(BeNil())
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "InvalidSpec")
+			})
+		})
 		Context("with a stopped recording to be archived", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, test.NewStoppedRecordingToArchive())
@@ -302,6 +937,11 @@ var _ = Describe("RecordingController", func() {
 			It("should requeue with error", func() {
 				t.expectRecordingReconcileError()
 			})
+			It("should set Archived and Failed conditions", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse, "ArchiveFailed")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "ArchiveFailed")
+			})
 		})
 		Context("with a running recording to be stopped and archived", func() {
 			BeforeEach(func() {
@@ -356,17 +996,106 @@ var _ = Describe("RecordingController", func() {
 		})
 		Context("with an archived recording", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, test.NewArchivedRecording())
+				t.objs = append(t.objs, test.NewArchivedRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 30000)),
+					test.NewListSavedHandler(test.NewSavedRecordings()),
+				}
+			})
+			It("should not change status", func() {
+				t.expectRecordingStatusUnchaged()
+			})
+			It("should not requeue", func() {
+				t.expectRecordingResult(reconcile.Result{})
+			})
+			It("should set Archived condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionArchived, metav1.ConditionTrue, "RecordingArchived")
+			})
+		})
+		Context("with an archived recording whose file was deleted", func() {
+			BeforeEach(func() {
+				rec := test.NewArchivedRecordingWithTTL()
+				rec.Spec.TTLSecondsAfterFinished = nil
+				t.objs = append(t.objs, rec)
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 30000)),
+					test.NewListSavedHandler([]cryostatClient.SavedRecording{}),
+				}
+			})
+			It("should not archive the recording again", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(*obj.Status.DownloadURL).To(Equal("http://path/to/saved-test-recording.jfr"))
+				Expect(*obj.Status.ReportURL).To(Equal("http://path/to/saved-test-recording.html"))
+			})
+			It("should report the archived file was pruned", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionArchived, metav1.ConditionTrue, "ArchivePruned")
+			})
+		})
+		Context("with a newly stopped recording", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewArchivedRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 30000)),
+					test.NewListSavedHandler(test.NewSavedRecordings()),
+				}
+				now := test.NewRecordingCompletionTime()
+				t.Now = &now
+			})
+			It("should set completion time", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.CompletionTime).ToNot(BeNil())
+				Expect(obj.Status.CompletionTime.Time).To(BeTemporally("==", test.NewRecordingCompletionTime()))
+			})
+		})
+		Context("with an archived recording with a TTL", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewArchivedRecordingWithTTL())
 				t.handlers = []http.HandlerFunc{
 					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 30000)),
 					test.NewListSavedHandler(test.NewSavedRecordings()),
 				}
 			})
-			It("should not change status", func() {
-				t.expectRecordingStatusUnchaged()
+			Context("that has not expired", func() {
+				BeforeEach(func() {
+					now := test.NewRecordingCompletionTime().Add(30 * time.Minute)
+					t.Now = &now
+				})
+				It("should requeue when the TTL expires", func() {
+					result := t.reconcileRecording()
+					Expect(result).To(Equal(reconcile.Result{RequeueAfter: 30 * time.Minute}))
+				})
+				It("should not delete the recording", func() {
+					t.reconcileRecording()
+					t.expectRecordingExists(true)
+				})
+			})
+			Context("that has expired", func() {
+				BeforeEach(func() {
+					now := test.NewRecordingCompletionTime().Add(2 * time.Hour)
+					t.Now = &now
+				})
+				It("should delete the recording", func() {
+					result := t.reconcileRecording()
+					Expect(result).To(Equal(reconcile.Result{}))
+					t.expectRecordingExists(false)
+				})
 			})
-			It("should not requeue", func() {
-				t.expectRecordingResult(reconcile.Result{})
+		})
+		Context("with an expired recording with missing FlightRecorder", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewArchivedRecordingWithTTL(),
+					test.NewJMXAuthSecret(),
+				}
+				now := test.NewRecordingCompletionTime().Add(2 * time.Hour)
+				t.Now = &now
+			})
+			It("should delete the recording", func() {
+				t.reconcileRecording()
+				t.expectRecordingExists(false)
 			})
 		})
 		Context("with a deleted archived recording", func() {
@@ -405,6 +1134,82 @@ var _ = Describe("RecordingController", func() {
 				t.expectRecordingResult(reconcile.Result{})
 			})
 		})
+		Context("with a deleted recording with archived copies and missing FlightRecorder", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewDeletedRecordingWithSnapshots(),
+					test.NewJMXAuthSecret(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewSavedRecordingsWithSnapshot()),
+					test.NewDeleteSavedNoJMXAuthHandler(),
+					test.NewDeleteArchivedFileHandler("snapshot-test-recording.jfr"),
+				}
+			})
+			It("should delete all archived files and remove the finalizer", func() {
+				t.expectRecordingFinalizerAbsent()
+			})
+		})
+		Context("with a deleted archived recording to be retained", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs,
+					test.NewDeletedArchivedRecordingWithPolicy(operatorv1beta1.RecordingDeletionPolicyRetain))
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedHandler(test.NewSavedRecordings()),
+					test.NewRetainSavedHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 30000)),
+					test.NewDeleteHandler(),
+				}
+			})
+			It("should label the saved recording and remove the finalizer", func() {
+				t.expectRecordingFinalizerAbsent()
+			})
+		})
+		Context("with a deleted archived recording and a Cryostat that retains recordings", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostatWithRecordingDeletionPolicy(operatorv1beta1.RecordingDeletionPolicyRetain),
+					test.NewCACert(), test.NewFlightRecorder(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewDeletedArchivedRecording(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedHandler(test.NewSavedRecordings()),
+					test.NewRetainSavedHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 30000)),
+					test.NewDeleteHandler(),
+				}
+			})
+			It("should label the saved recording and remove the finalizer", func() {
+				t.expectRecordingFinalizerAbsent()
+			})
+			Context("that is overridden by the Recording", func() {
+				BeforeEach(func() {
+					t.objs[len(t.objs)-1] = test.NewDeletedArchivedRecordingWithPolicy(
+						operatorv1beta1.RecordingDeletionPolicyDelete)
+					t.handlers[1] = test.NewDeleteSavedHandler()
+				})
+				It("should delete the saved recording and remove the finalizer", func() {
+					t.expectRecordingFinalizerAbsent()
+				})
+			})
+		})
+		Context("with a deleted recording to be retained and missing FlightRecorder", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewTargetPod(), test.NewCryostatService(),
+					test.NewDeletedArchivedRecordingWithPolicy(operatorv1beta1.RecordingDeletionPolicyRetain),
+					test.NewJMXAuthSecret(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewSavedRecordings()),
+					test.NewRetainSavedNoJMXAuthHandler(),
+				}
+			})
+			It("should label the saved recording and remove the finalizer", func() {
+				t.expectRecordingFinalizerAbsent()
+			})
+		})
 		Context("when deleting the saved recording fails", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, test.NewDeletedArchivedRecording())
@@ -420,6 +1225,64 @@ var _ = Describe("RecordingController", func() {
 				t.expectRecordingReconcileError()
 			})
 		})
+		Context("with a force-deleted recording", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewForceDeletedArchivedRecording())
+			})
+			It("should remove the finalizer without cleaning up", func() {
+				t.expectRecordingFinalizerAbsent()
+			})
+			It("should emit a Warning Event describing what may remain", func() {
+				t.reconcileRecording()
+				Expect(t.getEvents()).To(ConsistOf(
+					"Warning CleanupAbandoned Deleting without cleaning up in Cryostat, because deletion was " +
+						"forced with the operator.cryostat.io/force-delete annotation. These may have been left " +
+						"behind: recording \"test-recording\" on FlightRecorder \"test-pod\", " +
+						"archived file \"saved-test-recording.jfr\""))
+			})
+		})
+		Context("when deleting the saved recording keeps failing", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostatWithFinalizerTimeout(10 * time.Minute), test.NewCACert(),
+					test.NewFlightRecorder(), test.NewTargetPod(), test.NewCryostatService(),
+					test.NewJMXAuthSecret(), test.NewDeletedArchivedRecording(),
+				}
+			})
+			Context("within the finalizer timeout", func() {
+				BeforeEach(func() {
+					now := test.NewRecordingDeletionTime().Add(5 * time.Minute)
+					t.Now = &now
+					t.handlers = []http.HandlerFunc{
+						test.NewListSavedHandler(test.NewSavedRecordings()),
+						test.NewDeleteSavedFailHandler(),
+					}
+				})
+				It("should keep the finalizer", func() {
+					t.expectRecordingFinalizerPresent()
+				})
+				It("should requeue with error and not emit an Event", func() {
+					t.expectRecordingReconcileError()
+					Expect(t.getEvents()).To(BeEmpty())
+				})
+			})
+			Context("after the finalizer timeout", func() {
+				BeforeEach(func() {
+					now := test.NewRecordingDeletionTime().Add(10 * time.Minute)
+					t.Now = &now
+				})
+				It("should remove the finalizer without cleaning up", func() {
+					t.expectRecordingFinalizerAbsent()
+				})
+				It("should emit a Warning Event", func() {
+					t.reconcileRecording()
+					events := t.getEvents()
+					Expect(events).To(HaveLen(1))
+					Expect(events[0]).To(HavePrefix("Warning CleanupAbandoned Deleting without cleaning up " +
+						"in Cryostat, because cleanup did not succeed within 10m0s."))
+				})
+			})
+		})
 		Context("when deleting the in-memory recording fails", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, test.NewDeletedArchivedRecording())
@@ -504,6 +1367,11 @@ var _ = Describe("RecordingController", func() {
 				obj := t.reconcileRecordingAndGet()
 				Expect(obj.Labels).To(HaveKeyWithValue(operatorv1beta1.RecordingLabel, "test-pod"))
 			})
+			It("should set TargetAvailable condition to false", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionTargetAvailable, metav1.ConditionFalse, "FlightRecorderMissing")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse, "FlightRecorderMissing")
+			})
 		})
 		Context("FlightRecorder is not defined in Recording", func() {
 			BeforeEach(func() {
@@ -537,6 +1405,10 @@ var _ = Describe("RecordingController", func() {
 			It("should requeue with error", func() {
 				t.expectRecordingReconcileError()
 			})
+			It("should set TargetAvailable condition to false", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionTargetAvailable, metav1.ConditionFalse, "TargetPodNotFound")
+			})
 		})
 		Context("Target pod has no IP", func() {
 			BeforeEach(func() {
@@ -553,6 +1425,316 @@ var _ = Describe("RecordingController", func() {
 			})
 		})
 	})
+	Describe("reconciling a request for a workload", func() {
+		BeforeEach(func() {
+			t.objs = []runtime.Object{
+				test.NewCryostat(), test.NewCACert(), test.NewFlightRecorder(),
+				test.NewWorkloadTargetPod(), test.NewPendingWorkloadTargetPod(),
+				test.NewTargetDeployment(), test.NewCryostatService(), test.NewJMXAuthSecret(),
+			}
+		})
+		Context("with a new recording", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewWorkloadRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should start the recording on each pod with a FlightRecorder", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.Targets).To(HaveLen(2))
+				target := obj.Status.Targets[0]
+				Expect(target.Pod).To(Equal("test-pod"))
+				Expect(target.FlightRecorder).To(Equal("test-pod"))
+				Expect(target.State).ToNot(BeNil())
+				Expect(*target.State).To(Equal(operatorv1beta1.RecordingStateRunning))
+				Expect(target.DownloadURL).ToNot(BeNil())
+				Expect(*target.DownloadURL).To(Equal("http://path/to/test-recording.jfr"))
+				Expect(target.Message).To(BeEmpty())
+			})
+			It("should wait for pods without a FlightRecorder", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.Targets).To(HaveLen(2))
+				target := obj.Status.Targets[1]
+				Expect(target.Pod).To(Equal("test-pod-2"))
+				Expect(target.State).To(BeNil())
+				Expect(target.Message).ToNot(BeEmpty())
+			})
+			It("should set overall state and conditions", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.State).ToNot(BeNil())
+				Expect(*obj.Status.State).To(Equal(operatorv1beta1.RecordingStateRunning))
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionTargetAvailable, metav1.ConditionTrue, "TargetFound")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionReady, metav1.ConditionTrue, "RecordingFound")
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionFalse, "RecordingReconciled")
+			})
+			It("adds finalizer to recording", func() {
+				t.expectRecordingFinalizerPresent()
+			})
+			It("should requeue after 10 seconds", func() {
+				result := t.reconcileRecording()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+			})
+		})
+		Context("with a new recording using a selector", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewSelectorRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should start the recording on each matching pod", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.Targets).To(HaveLen(2))
+				Expect(obj.Status.Targets[0].State).ToNot(BeNil())
+				Expect(*obj.Status.Targets[0].State).To(Equal(operatorv1beta1.RecordingStateRunning))
+			})
+		})
+		Context("with a running recording", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRunningWorkloadRecording(false))
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should forget pods that no longer exist", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.Targets).To(HaveLen(2))
+				Expect(obj.Status.Targets[0].Pod).To(Equal("test-pod"))
+				Expect(obj.Status.Targets[1].Pod).To(Equal("test-pod-2"))
+			})
+		})
+		Context("with a running recording whose spec has changed with restartPolicy OnSpecChange", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewWorkloadRecordingWithChangedSpec(operatorv1beta1.RecordingRestartPolicyOnSpecChange))
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+					test.NewStopHandler(),
+					test.NewDeleteHandler(),
+					test.NewStartHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+				}
+			})
+			It("should recreate the recording on each pod", func() {
+				obj := t.reconcileRecordingAndGet()
+				target := obj.Status.Targets[0]
+				Expect(target.Pod).To(Equal("test-pod"))
+				Expect(target.State).ToNot(BeNil())
+				Expect(*target.State).To(Equal(operatorv1beta1.RecordingStateRunning))
+			})
+			It("should set recording generation", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.RecordingGeneration).To(Equal(int64(2)))
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionFalse, "RecordingReconciled")
+			})
+		})
+		Context("with a running recording whose spec has changed", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewWorkloadRecordingWithChangedSpec(""))
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should reject the change", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.RecordingGeneration).To(Equal(int64(1)))
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "SpecChangeRejected")
+			})
+		})
+		Context("with a running recording when a target pod is terminating", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorder(),
+					test.NewTerminatingWorkloadTargetPod(), test.NewPendingWorkloadTargetPod(),
+					test.NewTargetDeployment(), test.NewCryostatService(), test.NewJMXAuthSecret(),
+					test.NewRunningWorkloadRecording(false),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 0)),
+					test.NewSaveHandler(),
+					test.NewListSavedHandler(test.NewSavedRecordings()),
+				}
+			})
+			It("should archive the recording from the terminating pod", func() {
+				obj := t.reconcileRecordingAndGet()
+				saved := test.NewSavedRecordings()[0]
+				target := obj.Status.Targets[0]
+				Expect(target.Pod).To(Equal("test-pod"))
+				Expect(target.Archived).To(BeTrue())
+				Expect(target.DownloadURL).ToNot(BeNil())
+				Expect(*target.DownloadURL).To(Equal(saved.DownloadURL))
+				Expect(target.Message).ToNot(BeEmpty())
+			})
+		})
+		Context("with a new recording when a target pod is terminating", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorder(),
+					test.NewTerminatingWorkloadTargetPod(), test.NewTargetDeployment(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewWorkloadRecording(),
+				}
+			})
+			It("should not start the recording on that pod", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.Targets).To(HaveLen(1))
+				Expect(obj.Status.Targets[0].State).To(BeNil())
+				Expect(obj.Status.Targets[0].Message).ToNot(BeEmpty())
+			})
+			It("should set TargetAvailable condition to false", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionTargetAvailable, metav1.ConditionFalse, "NoTargetPods")
+			})
+		})
+		Context("with a recording archived from a pod that no longer exists", func() {
+			BeforeEach(func() {
+				recording := test.NewRunningWorkloadRecording(false)
+				recording.Status.Targets[1].Archived = true
+				t.objs = append(t.objs, recording)
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("RUNNING", 30000)),
+				}
+			})
+			It("should keep the archived recording as stopped", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.Targets).To(HaveLen(3))
+				target := obj.Status.Targets[0]
+				Expect(target.Pod).To(Equal("old-pod"))
+				Expect(target.Archived).To(BeTrue())
+				Expect(target.State).ToNot(BeNil())
+				Expect(*target.State).To(Equal(operatorv1beta1.RecordingStateStopped))
+			})
+			It("should use the state of the running pods", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.State).ToNot(BeNil())
+				Expect(*obj.Status.State).To(Equal(operatorv1beta1.RecordingStateRunning))
+			})
+		})
+		Context("with a stopped recording to be archived", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRunningWorkloadRecording(true))
+				t.handlers = []http.HandlerFunc{
+					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 30000)),
+					test.NewSaveHandler(),
+					test.NewListSavedHandler(test.NewSavedRecordings()),
+				}
+			})
+			It("should archive the recording from each pod", func() {
+				obj := t.reconcileRecordingAndGet()
+				saved := test.NewSavedRecordings()[0]
+				target := obj.Status.Targets[0]
+				Expect(target.Archived).To(BeTrue())
+				Expect(target.DownloadURL).ToNot(BeNil())
+				Expect(*target.DownloadURL).To(Equal(saved.DownloadURL))
+				Expect(target.ReportURL).ToNot(BeNil())
+				Expect(*target.ReportURL).To(Equal(saved.ReportURL))
+			})
+			It("should mark the recording as stopped and archived", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.State).ToNot(BeNil())
+				Expect(*obj.Status.State).To(Equal(operatorv1beta1.RecordingStateStopped))
+				Expect(obj.Status.CompletionTime).ToNot(BeNil())
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionArchived, metav1.ConditionTrue, "RecordingArchived")
+			})
+			It("should not requeue", func() {
+				result := t.reconcileRecording()
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+		Context("when creating the recording fails", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewWorkloadRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewDumpFailHandler(),
+					test.NewListHandler([]cryostatClient.RecordingDescriptor{}),
+				}
+			})
+			It("should set a message for the pod and Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.Targets[0].Message).ToNot(BeEmpty())
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "TargetsFailed")
+			})
+			It("should requeue with error", func() {
+				t.expectRecordingReconcileError()
+			})
+		})
+		Context("with a recording scheduled to start later", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewScheduledWorkloadRecording())
+				now := test.NewRecordingWindowStart().Add(-time.Hour)
+				t.Now = &now
+			})
+			It("should not start the recording on any pod", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.Targets).To(BeEmpty())
+				Expect(obj.Status.State).ToNot(BeNil())
+				Expect(*obj.Status.State).To(Equal(operatorv1beta1.RecordingStateScheduled))
+			})
+			It("should requeue at the start time", func() {
+				result := t.reconcileRecording()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Hour}))
+			})
+		})
+		Context("with a scheduled recording at its stop time", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRunningScheduledWorkloadRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewStopHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 0)),
+				}
+				now := test.NewRecordingWindowStart().Add(time.Hour)
+				t.Now = &now
+			})
+			It("should stop the recording on each pod", func() {
+				obj := t.reconcileRecordingAndGet()
+				Expect(obj.Status.Targets[0].Pod).To(Equal("test-pod"))
+				Expect(*obj.Status.Targets[0].State).To(Equal(operatorv1beta1.RecordingStateStopped))
+			})
+		})
+		Context("with a missing workload", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorder(),
+					test.NewWorkloadTargetPod(), test.NewCryostatService(), test.NewJMXAuthSecret(),
+					test.NewWorkloadRecording(),
+				}
+			})
+			It("should set TargetAvailable condition to false", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionTargetAvailable, metav1.ConditionFalse, "WorkloadNotFound")
+			})
+			It("should requeue after 10 seconds", func() {
+				result := t.reconcileRecording()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+			})
+		})
+		Context("with both a workload and a FlightRecorder", func() {
+			BeforeEach(func() {
+				recording := test.NewWorkloadRecording()
+				recording.Spec.FlightRecorder = test.NewRecording().Spec.FlightRecorder
+				t.objs = append(t.objs, recording)
+			})
+			It("should set Failed condition", func() {
+				obj := t.reconcileRecordingAndGet()
+				expectRecordingCondition(obj, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue, "InvalidSpec")
+			})
+		})
+		Context("with a deleted recording", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewDeletedWorkloadRecording())
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewSavedRecordings()),
+					test.NewDeleteSavedNoJMXAuthHandler(),
+					test.NewListHandler(test.NewRecordingDescriptors("STOPPED", 30000)),
+					test.NewDeleteHandler(),
+				}
+			})
+			It("should remove the finalizer", func() {
+				t.expectRecordingFinalizerAbsent()
+			})
+		})
+	})
 })
 
 func (t *recordingTestInput) expectRecordingUpdated(desc *cryostatClient.RecordingDescriptor) {
@@ -573,6 +1755,12 @@ func (t *recordingTestInput) expectRecordingUpdated(desc *cryostatClient.Recordi
 	Expect(*obj.Status.DownloadURL).To(Equal(desc.DownloadURL))
 	Expect(obj.Status.ReportURL).ToNot(BeNil())
 	Expect(*obj.Status.ReportURL).To(Equal(desc.ReportURL))
+	Expect(obj.Status.MaxSize).ToNot(BeNil())
+	Expect(obj.Status.MaxSize.Value()).To(Equal(desc.MaxSize))
+	Expect(obj.Status.MaxAge).To(Equal(&metav1.Duration{
+		Duration: time.Duration(desc.MaxAge) * time.Millisecond,
+	}))
+	Expect(obj.Status.ToDisk).To(Equal(&desc.ToDisk))
 }
 
 func (t *recordingTestInput) expectRecordingStatusUnchaged() {
@@ -580,8 +1768,22 @@ func (t *recordingTestInput) expectRecordingStatusUnchaged() {
 	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "my-recording", Namespace: "default"}, before)
 	Expect(err).ToNot(HaveOccurred())
 
+	// Conditions are expected to be updated, but nothing else
 	after := t.reconcileRecordingAndGet()
-	Expect(after.Status).To(Equal(before.Status))
+	Expect(after.Status.State).To(Equal(before.Status.State))
+	Expect(after.Status.StartTime).To(Equal(before.Status.StartTime))
+	Expect(after.Status.Duration).To(Equal(before.Status.Duration))
+	Expect(after.Status.DownloadURL).To(Equal(before.Status.DownloadURL))
+	Expect(after.Status.ReportURL).To(Equal(before.Status.ReportURL))
+}
+
+func expectRecordingCondition(obj *operatorv1beta1.Recording, condType string, status metav1.ConditionStatus,
+	reason string) {
+	condition := meta.FindStatusCondition(obj.Status.Conditions, condType)
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+	Expect(condition.Message).ToNot(BeEmpty())
 }
 
 func (t *recordingTestInput) expectRecordingFinalizerPresent() {
@@ -610,6 +1812,32 @@ func (t *recordingTestInput) expectRecordingResult(result reconcile.Result) {
 	Expect(result).To(Equal(result))
 }
 
+func (t *recordingTestInput) reconcileRecording() reconcile.Result {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "my-recording", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	return result
+}
+
+func (t *recordingTestInput) expectRecordingExists(exists bool) {
+	obj := &operatorv1beta1.Recording{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "my-recording", Namespace: "default"}, obj)
+	if exists {
+		Expect(err).ToNot(HaveOccurred())
+	} else {
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+	}
+}
+
+func (t *recordingTestInput) getEvents() []string {
+	recorder := t.controller.EventRecorder.(*record.FakeRecorder)
+	events := []string{}
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	return events
+}
+
 func (t *recordingTestInput) reconcileRecordingAndGet() *operatorv1beta1.Recording {
 	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "my-recording", Namespace: "default"}}
 	t.controller.Reconcile(context.Background(), req)
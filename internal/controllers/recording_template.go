@@ -0,0 +1,121 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// newRecordingFromTemplate returns a Recording created by the owner from its
+// template at the given time. The Recording is named after namePrefix, and
+// labelled with ownerLabel so the owner can find it again.
+func newRecordingFromTemplate(owner metav1.Object, template *operatorv1beta1.RecordingTemplateSpec,
+	namePrefix string, ownerLabel string, createTime time.Time, scheme *runtime.Scheme) (*operatorv1beta1.Recording, error) {
+	// Use a deterministic name, so that the same Recording is never created twice
+	suffix := fmt.Sprintf("%d", createTime.Unix()/60)
+
+	labels := map[string]string{}
+	for key, value := range template.Labels {
+		labels[key] = value
+	}
+	labels[ownerLabel] = recordingOwnerLabelValue(owner.GetName())
+
+	annotations := map[string]string{}
+	for key, value := range template.Annotations {
+		annotations[key] = value
+	}
+
+	spec := template.Spec.DeepCopy()
+	jfrName := spec.Name
+	if len(jfrName) == 0 {
+		jfrName = owner.GetName()
+	}
+	// The JFR recording name must also be unique within the target JVM
+	spec.Name = jfrName + "-" + suffix
+
+	recording := &operatorv1beta1.Recording{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        templateRecordingName(namePrefix, suffix),
+			Namespace:   owner.GetNamespace(),
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: *spec,
+	}
+	if err := controllerutil.SetControllerReference(owner, recording, scheme); err != nil {
+		return nil, err
+	}
+	return recording, nil
+}
+
+// templateRecordingName returns the name of a Recording created from a template.
+// If the name would be too long, the prefix is truncated and a hash of it is
+// added to keep the name unique.
+func templateRecordingName(prefix string, suffix string) string {
+	if len(prefix)+len(suffix)+1 <= 253 {
+		return prefix + "-" + suffix
+	}
+	hash := nameHash(prefix)
+	prefix = strings.TrimRight(prefix[:253-len(hash)-len(suffix)-2], "-.")
+	return prefix + "-" + hash + "-" + suffix
+}
+
+// recordingOwnerLabelValue returns the value of the label referring to the
+// owner of a Recording. Object names may be longer than label values, so long
+// names are truncated and a hash of them is added to keep the value unique.
+func recordingOwnerLabelValue(ownerName string) string {
+	if len(ownerName) <= 63 {
+		return ownerName
+	}
+	hash := nameHash(ownerName)
+	prefix := strings.TrimRight(ownerName[:63-len(hash)-1], "-.")
+	return prefix + "-" + hash
+}
+
+func nameHash(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
@@ -0,0 +1,544 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+)
+
+// Reasons used for conditions of Recordings targeting a workload
+const (
+	reasonWorkloadNotFound = "WorkloadNotFound"
+	reasonNoTargetPods     = "NoTargetPods"
+	reasonTargetsFailed    = "TargetsFailed"
+)
+
+// isWorkloadRecording returns whether the recording targets the pods of a
+// workload or selector, rather than a single FlightRecorder
+func isWorkloadRecording(recording *operatorv1beta1.Recording) bool {
+	return recording.Spec.Workload != nil || recording.Spec.Selector != nil
+}
+
+// reconcileWorkloadRecording creates and manages the recording on each pod
+// targeted by the Recording's workload or selector
+func (r *RecordingReconciler) reconcileWorkloadRecording(ctx context.Context,
+	recording *operatorv1beta1.Recording) (reconcile.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", recording.Namespace, "Request.Name", recording.Name)
+
+	// Check if this Recording is being deleted
+	if recording.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(recording, recordingFinalizer) {
+			return r.deleteWorkloadRecording(ctx, recording)
+		}
+		// Ready for deletion
+		return reconcile.Result{}, nil
+	}
+
+	err := validateWorkloadRecording(recording)
+	if err != nil {
+		return r.recordingInvalid(ctx, recording, err)
+	}
+
+	// Add our finalizer, so we can clean up Cryostat resources upon deletion
+	if !controllerutil.ContainsFinalizer(recording, recordingFinalizer) {
+		err := common.AddFinalizer(ctx, r.Client, recording, recordingFinalizer)
+		if err != nil {
+			return r.recordingFailed(ctx, recording, reasonInternalError, err)
+		}
+	}
+
+	// Look up the pods currently targeted, and their FlightRecorders
+	pods, err := r.getWorkloadPods(ctx, recording)
+	if err != nil {
+		return r.recordingFailed(ctx, recording, reasonInternalError, err)
+	}
+	if pods == nil {
+		// Workload doesn't exist yet, or has been deleted
+		err = r.updateRecordingStatus(ctx, recording)
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, err
+	}
+	jfrs, err := getFlightRecordersByPod(ctx, r.Client, recording.Namespace)
+	if err != nil {
+		return r.recordingFailed(ctx, recording, reasonInternalError, err)
+	}
+
+	// Don't start the recording on new pods once it has finished, or is requested to stop
+	canStart := recording.Status.CompletionTime == nil && !isStopRequested(recording, r.Now())
+
+	// Apply any changes to the spec since the recording was created
+	restart := false
+	specRejected := ""
+	if hasRecordingStarted(recording) {
+		restart, specRejected, err = r.checkSpecChange(recording)
+		if err != nil {
+			return r.recordingFailed(ctx, recording, reasonInternalError, err)
+		}
+	}
+
+	targets := []operatorv1beta1.RecordingTargetStatus{}
+	current := map[string]bool{}
+	failures := []string{}
+	var targetErr error
+	available := 0
+	for idx := range pods {
+		pod := &pods[idx]
+		current[pod.Name] = true
+		target := findTargetStatus(recording, pod.Name)
+		jfr, pres := jfrs[pod.Name]
+		if !pres {
+			target.Message = "Waiting for a FlightRecorder for this pod"
+		} else {
+			// Pods shutting down without the recording no longer count towards readiness
+			if !isPodTerminating(pod) || target.State != nil {
+				available++
+			}
+			target.FlightRecorder = jfr.Name
+			err = r.reconcileTarget(ctx, recording, target, jfr, pod, canStart, restart)
+			if err != nil {
+				reqLogger.Error(err, "failed to reconcile recording on target pod", "pod", pod.Name)
+				target.Message = err.Error()
+				failures = append(failures, fmt.Sprintf("%s: %s", pod.Name, err.Error()))
+				// Spec problems won't be fixed by requeuing
+				if _, ok := err.(*invalidRecordingError); !ok {
+					targetErr = err
+				}
+			}
+		}
+		targets = append(targets, *target)
+	}
+	// Keep track of archived recordings from pods that no longer exist
+	for _, target := range recording.Status.Targets {
+		if !current[target.Pod] && target.Archived {
+			// The recording ended along with its JVM
+			stopped := operatorv1beta1.RecordingStateStopped
+			target.State = &stopped
+			target.Message = "Target pod no longer exists"
+			targets = append(targets, target)
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Pod < targets[j].Pod
+	})
+	recording.Status.Targets = targets
+	r.setWorkloadStatus(recording, available, failures)
+	if len(failures) == 0 && len(specRejected) == 0 {
+		// Every target now records the current spec
+		err = setRecordingGeneration(recording)
+		if err != nil {
+			return r.recordingFailed(ctx, recording, reasonInternalError, err)
+		}
+	} else if len(failures) == 0 {
+		setRecordingCondition(recording, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue,
+			reasonSpecChangeRejected, specRejected)
+	}
+
+	err = r.updateRecordingStatus(ctx, recording)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if targetErr != nil {
+		return reconcile.Result{}, targetErr
+	}
+
+	// Keep watching for new pods until the recording has finished
+	if isRecordingComplete(recording) {
+		return r.deleteIfExpired(ctx, recording)
+	}
+	reqLogger.Info("Recording successfully updated", "Namespace", recording.Namespace, "Name", recording.Name)
+	return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+}
+
+func validateWorkloadRecording(recording *operatorv1beta1.Recording) error {
+	if recording.Spec.FlightRecorder != nil || (recording.Spec.Workload != nil && recording.Spec.Selector != nil) {
+		return &invalidRecordingError{
+			reason:  reasonInvalidSpec,
+			message: "Only one of flightRecorder, workload and selector may be specified",
+		}
+	}
+	if recording.Spec.ArchiveInterval != nil {
+		return &invalidRecordingError{
+			reason:  reasonInvalidSpec,
+			message: "archiveInterval cannot be used together with workload or selector",
+		}
+	}
+	return nil
+}
+
+// getWorkloadPods returns the pods targeted by the recording, or nil if its
+// workload could not be found
+func (r *RecordingReconciler) getWorkloadPods(ctx context.Context, recording *operatorv1beta1.Recording) ([]corev1.Pod, error) {
+	labelSelector := recording.Spec.Selector
+	if recording.Spec.Workload != nil {
+		var err error
+		labelSelector, err = r.getWorkloadSelector(ctx, recording)
+		if err != nil || labelSelector == nil {
+			return nil, err
+		}
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := &corev1.PodList{}
+	err = r.Client.List(ctx, pods, &client.ListOptions{
+		Namespace:     recording.Namespace,
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Pods that are shutting down are included, so their recordings can be archived
+	return pods.Items, nil
+}
+
+func (r *RecordingReconciler) getWorkloadSelector(ctx context.Context,
+	recording *operatorv1beta1.Recording) (*metav1.LabelSelector, error) {
+	workload := recording.Spec.Workload
+	key := types.NamespacedName{Namespace: recording.Namespace, Name: workload.Name}
+	var obj client.Object
+	switch workload.Kind {
+	case operatorv1beta1.WorkloadKindDeployment:
+		obj = &appsv1.Deployment{}
+	case operatorv1beta1.WorkloadKindStatefulSet:
+		obj = &appsv1.StatefulSet{}
+	default:
+		return nil, fmt.Errorf("unsupported workload kind \"%s\"", workload.Kind)
+	}
+
+	err := r.Client.Get(ctx, key, obj)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			setTargetUnavailable(recording, reasonWorkloadNotFound,
+				fmt.Sprintf("%s \"%s\" not found", workload.Kind, workload.Name))
+			return nil, nil
+		}
+		return nil, err
+	}
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return workload.Spec.Selector, nil
+	case *appsv1.StatefulSet:
+		return workload.Spec.Selector, nil
+	}
+	return nil, nil
+}
+
+// getFlightRecordersByPod returns the FlightRecorders in the namespace, keyed
+// by the name of their target pod
+func getFlightRecordersByPod(ctx context.Context, c client.Client,
+	namespace string) (map[string]*operatorv1beta1.FlightRecorder, error) {
+	jfrs := &operatorv1beta1.FlightRecorderList{}
+	err := c.List(ctx, jfrs, client.InNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]*operatorv1beta1.FlightRecorder{}
+	for idx, jfr := range jfrs.Items {
+		if jfr.Status.Target != nil {
+			result[jfr.Status.Target.Name] = &jfrs.Items[idx]
+		}
+	}
+	return result, nil
+}
+
+func findTargetStatus(recording *operatorv1beta1.Recording, podName string) *operatorv1beta1.RecordingTargetStatus {
+	for _, target := range recording.Status.Targets {
+		if target.Pod == podName {
+			return target.DeepCopy()
+		}
+	}
+	return &operatorv1beta1.RecordingTargetStatus{Pod: podName}
+}
+
+// reconcileTarget creates and updates the recording on a single target pod
+func (r *RecordingReconciler) reconcileTarget(ctx context.Context, recording *operatorv1beta1.Recording,
+	target *operatorv1beta1.RecordingTargetStatus, jfr *operatorv1beta1.FlightRecorder, pod *corev1.Pod,
+	canStart bool, restart bool) error {
+	// Nothing left to do once archived
+	if target.Archived {
+		return nil
+	}
+	terminating := isPodTerminating(pod)
+	if terminating && target.State == nil {
+		target.Message = "Recording is not started on pods that are shutting down"
+		return nil
+	}
+	targetAddr, err := r.GetPodTarget(pod, jfr.Status.Port)
+	if err != nil {
+		// Pod likely hasn't started yet
+		target.Message = err.Error()
+		return nil
+	}
+	cryostat, err := r.GetCryostatClient(ctx, recording.Namespace, jfr.Spec.JMXCredentials)
+	if err != nil {
+		return err
+	}
+
+	// Recreate the recording from the current spec if requested
+	if restart && target.State != nil {
+		err = r.restartRecording(ctx, cryostat, targetAddr, recording, r.Log.WithValues("pod", pod.Name))
+		if err != nil {
+			return err
+		}
+		*target = operatorv1beta1.RecordingTargetStatus{
+			Pod:            target.Pod,
+			FlightRecorder: target.FlightRecorder,
+		}
+	}
+
+	// Tell Cryostat to create the recording if not already done
+	if target.State == nil {
+		if !canStart {
+			target.Message = "Recording is not started on new pods once it has finished or been stopped"
+			return nil
+		}
+		events, err := getRecordingEvents(ctx, r.Client, recording, jfr)
+		if err != nil {
+			return err
+		}
+		err = r.createRecording(cryostat, targetAddr, recording, events, r.Log.WithValues("pod", pod.Name))
+		if err != nil {
+			return err
+		}
+	} else if isStopRequested(recording, r.Now()) && *target.State == operatorv1beta1.RecordingStateRunning {
+		r.Log.Info("stopping recording", "name", recording.Spec.Name, "pod", pod.Name)
+		err = cryostat.StopRecording(targetAddr, recording.Spec.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update the target's status with the newest info from Cryostat
+	descriptor, err := r.findRecordingByName(cryostat, targetAddr, recording.Spec.Name)
+	if err != nil {
+		return err
+	}
+	if descriptor == nil {
+		target.Message = fmt.Sprintf("Recording \"%s\" was not found in the target JVM", recording.Spec.Name)
+		return nil
+	}
+	state, err := validateRecordingState(descriptor.State)
+	if err != nil {
+		return err
+	}
+	startTime := metav1.Unix(0, descriptor.StartTime*int64(time.Millisecond))
+	target.State = state
+	target.StartTime = &startTime
+	target.DownloadURL = &descriptor.DownloadURL
+	target.ReportURL = &descriptor.ReportURL
+	target.Message = ""
+
+	// Archive the recording once it has stopped if requested, or before
+	// the pod shuts down if it is still running
+	isRunning := *state == operatorv1beta1.RecordingStateRunning
+	if (recording.Spec.Archive && *state == operatorv1beta1.RecordingStateStopped) || (terminating && isRunning) {
+		r.Log.Info("saving recording", "name", recording.Spec.Name, "pod", pod.Name, "terminating", terminating)
+		filename, err := cryostat.SaveRecording(targetAddr, recording.Spec.Name)
+		if err != nil {
+			return err
+		}
+		saved, err := r.findSavedRecording(cryostat, *filename)
+		if err != nil {
+			return err
+		}
+		if saved != nil {
+			target.DownloadURL = &saved.DownloadURL
+			target.ReportURL = &saved.ReportURL
+			target.Archived = true
+			if terminating {
+				target.Message = "Recording was archived while the target pod was shutting down"
+			}
+		}
+	}
+	return nil
+}
+
+func (r *RecordingReconciler) setWorkloadStatus(recording *operatorv1beta1.Recording, available int, failures []string) {
+	targets := recording.Status.Targets
+	recording.Status.State = aggregateTargetState(targets)
+	isStopped := recording.Status.State != nil && *recording.Status.State == operatorv1beta1.RecordingStateStopped
+	if isStopped && recording.Status.CompletionTime == nil {
+		recording.Status.CompletionTime = &metav1.Time{Time: r.Now()}
+	}
+
+	if available > 0 {
+		setRecordingCondition(recording, operatorv1beta1.RecordingConditionTargetAvailable, metav1.ConditionTrue,
+			reasonTargetFound, fmt.Sprintf("Found %d target pod(s) with a FlightRecorder", available))
+	} else {
+		setTargetUnavailable(recording, reasonNoTargetPods, "No target pods with a FlightRecorder were found")
+	}
+
+	created := 0
+	archived := 0
+	for _, target := range targets {
+		if target.State != nil {
+			created++
+		}
+		if target.Archived {
+			archived++
+		}
+	}
+	if available > 0 {
+		if created >= available {
+			setRecordingCondition(recording, operatorv1beta1.RecordingConditionReady, metav1.ConditionTrue,
+				reasonRecordingFound, fmt.Sprintf("Recording \"%s\" is %s on %d target pod(s)", recording.Spec.Name,
+					*recording.Status.State, created))
+		} else {
+			setRecordingCondition(recording, operatorv1beta1.RecordingConditionReady, metav1.ConditionFalse,
+				reasonRecordingNotFound, fmt.Sprintf("Recording \"%s\" was found on %d of %d target pod(s)",
+					recording.Spec.Name, created, available))
+		}
+	}
+
+	if !recording.Spec.Archive {
+		setRecordingCondition(recording, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse,
+			reasonArchiveNotRequested, "Recording is not configured to be archived")
+	} else if isStopped && archived == created {
+		setRecordingCondition(recording, operatorv1beta1.RecordingConditionArchived, metav1.ConditionTrue,
+			reasonRecordingArchived, fmt.Sprintf("Recording archived from %d target pod(s)", archived))
+	} else {
+		setRecordingCondition(recording, operatorv1beta1.RecordingConditionArchived, metav1.ConditionFalse,
+			reasonArchivePending, "Recording will be archived from each target pod once it has stopped")
+	}
+
+	if len(failures) > 0 {
+		message := strings.Join(failures, "; ")
+		setRecordingCondition(recording, operatorv1beta1.RecordingConditionFailed, metav1.ConditionTrue,
+			reasonTargetsFailed, message)
+	} else {
+		setRecordingCondition(recording, operatorv1beta1.RecordingConditionFailed, metav1.ConditionFalse,
+			reasonRecordingReconciled, "Recording was successfully reconciled")
+	}
+}
+
+// aggregateTargetState returns STOPPED once the recording has stopped on every
+// target, otherwise the state of the least progressed recording
+func aggregateTargetState(targets []operatorv1beta1.RecordingTargetStatus) *operatorv1beta1.RecordingState {
+	order := []operatorv1beta1.RecordingState{
+		operatorv1beta1.RecordingStateCreated,
+		operatorv1beta1.RecordingStateRunning,
+		operatorv1beta1.RecordingStateStopping,
+		operatorv1beta1.RecordingStateStopped,
+	}
+	for idx := range order {
+		for _, target := range targets {
+			if target.State != nil && *target.State == order[idx] {
+				return &order[idx]
+			}
+		}
+	}
+	return nil
+}
+
+func (r *RecordingReconciler) deleteWorkloadRecording(ctx context.Context,
+	recording *operatorv1beta1.Recording) (reconcile.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", recording.Namespace, "Request.Name", recording.Name)
+
+	// Delete or retain any persisted JFR files for this recording
+	cryostat, err := r.GetCryostatClient(ctx, recording.Namespace, nil)
+	if err != nil {
+		return r.requeueIfNotReady(ctx, recording, err)
+	}
+	err = r.removeSavedRecording(ctx, cryostat, recording)
+	if err != nil {
+		reqLogger.Error(err, "failed to delete saved recording in Cryostat")
+		return r.recordingFailed(ctx, recording, reasonDeleteFailed, err)
+	}
+
+	// Delete in-memory recordings from target pods that still exist
+	for _, target := range recording.Status.Targets {
+		err = r.removeTargetRecording(ctx, recording, &target)
+		if err != nil {
+			reqLogger.Error(err, "failed to delete recording in Cryostat", "pod", target.Pod)
+			return r.recordingFailed(ctx, recording, reasonDeleteFailed, err)
+		}
+	}
+
+	// Remove our finalizer only once our cleanup logic has succeeded
+	err = common.RemoveFinalizer(ctx, r.Client, recording, recordingFinalizer)
+	return reconcile.Result{}, err
+}
+
+func (r *RecordingReconciler) removeTargetRecording(ctx context.Context, recording *operatorv1beta1.Recording,
+	target *operatorv1beta1.RecordingTargetStatus) error {
+	if len(target.FlightRecorder) == 0 || target.State == nil {
+		return nil
+	}
+	jfr := &operatorv1beta1.FlightRecorder{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: recording.Namespace, Name: target.FlightRecorder}, jfr)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Pod is gone, along with its in-memory recordings
+			return nil
+		}
+		return err
+	}
+	pod := &corev1.Pod{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: recording.Namespace, Name: target.Pod}, pod)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	targetAddr, err := r.GetPodTarget(pod, jfr.Status.Port)
+	if err != nil {
+		return nil
+	}
+	cryostat, err := r.GetCryostatClient(ctx, recording.Namespace, jfr.Spec.JMXCredentials)
+	if err != nil {
+		return err
+	}
+	return r.removeRecording(cryostat, targetAddr, recording)
+}
@@ -0,0 +1,433 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+)
+
+// RecordingTriggerReconciler reconciles a RecordingTrigger object
+type RecordingTriggerReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+	common.Reconciler
+}
+
+// How long to wait between Recordings for the same pod, if not specified
+const defaultTriggerCooldown = 10 * time.Minute
+
+// How long to keep finished Recordings created by a trigger, if the template
+// does not specify ttlSecondsAfterFinished
+const defaultTriggerRecordingTTL = 24 * time.Hour
+
+// Trigger reason used when a container restarts and spec.onRestart is set
+const triggerReasonRestarted = "Restarted"
+
+// Reasons used for Events emitted by RecordingTriggers
+const (
+	eventRecordingTriggered  = "RecordingTriggered"
+	eventTriggerSuppressed   = "TriggerSuppressed"
+	eventTriggerInvalidSpec  = "InvalidSpec"
+	eventTriggerCreateFailed = "RecordingCreateFailed"
+)
+
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=recordingtriggers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=recordingtriggers/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=recordingtriggers/finalizers,verbs=update
+
+// Reconcile processes a RecordingTrigger, creating a Recording for each selected pod
+// whose containers have restarted or are waiting to start for one of the specified reasons
+func (r *RecordingTriggerReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling RecordingTrigger")
+
+	// Fetch the RecordingTrigger instance
+	instance := &operatorv1beta1.RecordingTrigger{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			reqLogger.Info("RecordingTrigger does not exist")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&instance.Spec.Selector)
+	if err != nil {
+		// Requeuing won't help until the selector is fixed
+		reqLogger.Error(err, "invalid selector")
+		r.EventRecorder.Event(instance, corev1.EventTypeWarning, eventTriggerInvalidSpec, err.Error())
+		return reconcile.Result{}, nil
+	}
+	pods := &corev1.PodList{}
+	err = r.Client.List(ctx, pods, &client.ListOptions{
+		Namespace:     instance.Namespace,
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	jfrs, err := getFlightRecordersByPod(ctx, r.Client, instance.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	previous := map[string]*operatorv1beta1.RecordingTriggerPodStatus{}
+	for idx := range instance.Status.Pods {
+		previous[instance.Status.Pods[idx].Pod] = &instance.Status.Pods[idx]
+	}
+
+	// Entries for pods that no longer exist or match the selector are dropped
+	podStatuses := []operatorv1beta1.RecordingTriggerPodStatus{}
+	pending := false
+	var createErr error
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		// Pods being deleted won't restart, and any replacement will be a new pod
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		status := r.observePod(instance, pod, previous[pod.Name])
+		// Wait for the replacement container to be ready, so that its JVM can be reached
+		if len(status.PendingReason) > 0 && isTriggerPodReady(pod, instance.Spec.ContainerName) {
+			err = r.fireTrigger(ctx, instance, status, jfrs[pod.Name])
+			if err != nil {
+				// Keep going, so that other pods are not held up by this one
+				createErr = err
+			}
+		}
+		if len(status.PendingReason) > 0 {
+			pending = true
+		}
+		podStatuses = append(podStatuses, *status)
+	}
+
+	instance.Status.Pods = podStatuses
+	err = r.Client.Status().Update(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if createErr != nil {
+		return reconcile.Result{}, createErr
+	}
+
+	reqLogger.Info("RecordingTrigger successfully updated", "Namespace", instance.Namespace, "Name", instance.Name)
+	if pending {
+		// The pod's FlightRecorder may appear without the pod itself changing
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RecordingTriggerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c := ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1beta1.RecordingTrigger{}, builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	return r.watchPods(c, mgr.GetClient()).Complete(r)
+}
+
+func (r *RecordingTriggerReconciler) watchPods(b *builder.Builder, cl client.Client) *builder.Builder {
+	ctx := context.Background()
+	mapFunc := func(obj client.Object) []reconcile.Request {
+		return r.findTriggersForPod(ctx, cl, obj)
+	}
+
+	// Most pod updates, such as changes to conditions or IPs, can't trigger a Recording
+	podPredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, okOld := e.ObjectOld.(*corev1.Pod)
+			newPod, okNew := e.ObjectNew.(*corev1.Pod)
+			if !okOld || !okNew {
+				return false
+			}
+			return !reflect.DeepEqual(oldPod.Labels, newPod.Labels) ||
+				!reflect.DeepEqual(getTriggerStates(oldPod), getTriggerStates(newPod))
+		},
+	}
+
+	return b.Watches(
+		&source.Kind{Type: &corev1.Pod{}},
+		handler.EnqueueRequestsFromMapFunc(mapFunc),
+		builder.WithPredicates(podPredicate),
+	)
+}
+
+// triggerState is the part of a container's status that RecordingTriggers act on
+type triggerState struct {
+	name              string
+	restartCount      int32
+	ready             bool
+	waitingReason     string
+	terminationReason string
+}
+
+// getTriggerStates returns the state of each of the pod's containers that
+// may cause a RecordingTrigger to create a Recording
+func getTriggerStates(pod *corev1.Pod) []triggerState {
+	states := make([]triggerState, 0, len(pod.Status.ContainerStatuses))
+	for _, container := range pod.Status.ContainerStatuses {
+		state := triggerState{
+			name:         container.Name,
+			restartCount: container.RestartCount,
+			ready:        container.Ready,
+		}
+		if container.State.Waiting != nil {
+			state.waitingReason = container.State.Waiting.Reason
+		}
+		if container.LastTerminationState.Terminated != nil {
+			state.terminationReason = container.LastTerminationState.Terminated.Reason
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// findTriggersForPod returns requests for all RecordingTriggers whose
+// selector matches the pod
+func (r *RecordingTriggerReconciler) findTriggersForPod(ctx context.Context, cl client.Client,
+	obj client.Object) []reconcile.Request {
+	triggers := &operatorv1beta1.RecordingTriggerList{}
+	err := cl.List(ctx, triggers, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "Failed to list RecordingTriggers", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, trigger := range triggers.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&trigger.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Name},
+		})
+	}
+	return requests
+}
+
+// observePod compares the pod's container statuses with what was previously
+// observed, and returns the updated status with any reason to trigger a Recording
+func (r *RecordingTriggerReconciler) observePod(trigger *operatorv1beta1.RecordingTrigger, pod *corev1.Pod,
+	previous *operatorv1beta1.RecordingTriggerPodStatus) *operatorv1beta1.RecordingTriggerPodStatus {
+	containers := getTriggerContainerStatuses(pod, trigger.Spec.ContainerName)
+	restartCount := int32(0)
+	for _, container := range containers {
+		restartCount += container.RestartCount
+	}
+
+	var status *operatorv1beta1.RecordingTriggerPodStatus
+	if previous == nil {
+		// Restarts before the pod was first seen have already been missed
+		status = &operatorv1beta1.RecordingTriggerPodStatus{Pod: pod.Name}
+	} else {
+		status = previous.DeepCopy()
+		if restartCount > status.RestartCount {
+			if reason := getTerminationTrigger(trigger, containers); len(reason) > 0 {
+				status.PendingReason = reason
+			}
+		}
+	}
+	status.RestartCount = restartCount
+
+	// Waiting containers are of interest whenever they are seen
+	if len(status.PendingReason) == 0 {
+		status.PendingReason = getWaitingTrigger(trigger, containers)
+	}
+	return status
+}
+
+// fireTrigger creates a Recording for a pod with a pending trigger, unless its
+// last Recording is too recent
+func (r *RecordingTriggerReconciler) fireTrigger(ctx context.Context, trigger *operatorv1beta1.RecordingTrigger,
+	status *operatorv1beta1.RecordingTriggerPodStatus, jfr *operatorv1beta1.FlightRecorder) error {
+	reqLogger := r.Log.WithValues("Request.Namespace", trigger.Namespace, "Request.Name", trigger.Name,
+		"pod", status.Pod)
+	now := r.Now()
+	cooldown := defaultTriggerCooldown
+	if trigger.Spec.Cooldown != nil {
+		cooldown = trigger.Spec.Cooldown.Duration
+	}
+	if status.LastTriggerTime != nil && now.Before(status.LastTriggerTime.Add(cooldown)) {
+		reqLogger.Info("trigger is cooling down, skipping", "reason", status.PendingReason,
+			"lastTriggerTime", status.LastTriggerTime)
+		r.EventRecorder.Eventf(trigger, corev1.EventTypeNormal, eventTriggerSuppressed,
+			"Not recording pod %s after %s, the last recording was created less than %s ago",
+			status.Pod, status.PendingReason, cooldown)
+		status.PendingReason = ""
+		return nil
+	}
+	if jfr == nil {
+		// Try again once the pod has a FlightRecorder
+		reqLogger.Info("waiting for FlightRecorder for pod")
+		return nil
+	}
+
+	recording, err := r.newRecordingForTrigger(trigger, status, jfr, now)
+	if err != nil {
+		return err
+	}
+	err = r.Client.Create(ctx, recording)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		reqLogger.Error(err, "failed to create recording", "recording", recording.Name)
+		r.EventRecorder.Eventf(trigger, corev1.EventTypeWarning, eventTriggerCreateFailed,
+			"Failed to create recording for pod %s after %s: %s", status.Pod, status.PendingReason, err.Error())
+		return err
+	}
+	reqLogger.Info("created recording for trigger", "recording", recording.Name, "reason", status.PendingReason)
+	r.EventRecorder.Eventf(trigger, corev1.EventTypeNormal, eventRecordingTriggered,
+		"Created recording %s for pod %s after %s", recording.Name, status.Pod, status.PendingReason)
+
+	triggerTime := &metav1.Time{Time: now}
+	status.PendingReason = ""
+	status.LastTriggerTime = triggerTime
+	status.LastRecording = recording.Name
+	trigger.Status.LastTriggerTime = triggerTime
+	return nil
+}
+
+func (r *RecordingTriggerReconciler) newRecordingForTrigger(trigger *operatorv1beta1.RecordingTrigger,
+	status *operatorv1beta1.RecordingTriggerPodStatus, jfr *operatorv1beta1.FlightRecorder,
+	triggerTime time.Time) (*operatorv1beta1.Recording, error) {
+	recording, err := newRecordingFromTemplate(trigger, &trigger.Spec.RecordingTemplate,
+		trigger.Name+"-"+status.Pod, operatorv1beta1.RecordingTriggerLabel, triggerTime, r.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	recording.Annotations[operatorv1beta1.RecordingTriggerReasonAnnotation] = status.PendingReason
+
+	spec := &recording.Spec
+	// Record only the pod that was triggered
+	spec.FlightRecorder = &corev1.LocalObjectReference{Name: jfr.Name}
+	spec.Workload = nil
+	spec.Selector = nil
+	// Don't let Recordings from repeated triggers pile up
+	if spec.TTLSecondsAfterFinished == nil {
+		ttl := int32(defaultTriggerRecordingTTL.Seconds())
+		spec.TTLSecondsAfterFinished = &ttl
+	}
+	return recording, nil
+}
+
+// getTriggerContainerStatuses returns the statuses of the pod's containers
+// watched by the trigger
+func getTriggerContainerStatuses(pod *corev1.Pod, containerName string) []corev1.ContainerStatus {
+	if len(containerName) == 0 {
+		return pod.Status.ContainerStatuses
+	}
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.Name == containerName {
+			return []corev1.ContainerStatus{container}
+		}
+	}
+	return nil
+}
+
+// getTerminationTrigger returns the reason for a restart to trigger a Recording,
+// or an empty string if the restart is not of interest
+func getTerminationTrigger(trigger *operatorv1beta1.RecordingTrigger, containers []corev1.ContainerStatus) string {
+	for _, container := range containers {
+		terminated := container.LastTerminationState.Terminated
+		if terminated != nil && containsString(trigger.Spec.TerminationReasons, terminated.Reason) {
+			return terminated.Reason
+		}
+	}
+	if trigger.Spec.OnRestart {
+		return triggerReasonRestarted
+	}
+	return ""
+}
+
+// getWaitingTrigger returns the reason for a waiting container to trigger a
+// Recording, or an empty string if none are of interest
+func getWaitingTrigger(trigger *operatorv1beta1.RecordingTrigger, containers []corev1.ContainerStatus) string {
+	for _, container := range containers {
+		waiting := container.State.Waiting
+		if waiting != nil && containsString(trigger.Spec.WaitingReasons, waiting.Reason) {
+			return waiting.Reason
+		}
+	}
+	return ""
+}
+
+// isTriggerPodReady returns whether all of the pod's watched containers are ready
+func isTriggerPodReady(pod *corev1.Pod, containerName string) bool {
+	containers := getTriggerContainerStatuses(pod, containerName)
+	for _, container := range containers {
+		if !container.Ready {
+			return false
+		}
+	}
+	return len(containers) > 0
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
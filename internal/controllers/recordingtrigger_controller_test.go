@@ -0,0 +1,475 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+)
+
+type recordingTriggerTestInput struct {
+	controller *controllers.RecordingTriggerReconciler
+	objs       []runtime.Object
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("RecordingTriggerController", func() {
+	var t *recordingTriggerTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.controller = &controllers.RecordingTriggerReconciler{
+			Client:        t.Client,
+			Scheme:        s,
+			Log:           logger,
+			EventRecorder: record.NewFakeRecorder(1024),
+			Reconciler:    test.NewTestReconciler(&t.TestReconcilerConfig),
+		}
+	})
+
+	BeforeEach(func() {
+		t = &recordingTriggerTestInput{
+			objs: []runtime.Object{
+				test.NewFlightRecorder(),
+			},
+		}
+		t.setNow(test.NewTriggerTime())
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a pod seen for the first time", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecordingTrigger(), test.NewRunningWorkloadTargetPod(2))
+			})
+			It("should record the restart count", func() {
+				t.reconcileRecordingTrigger()
+				trigger := t.getRecordingTrigger()
+				Expect(trigger.Status.Pods).To(Equal([]operatorv1beta1.RecordingTriggerPodStatus{
+					{
+						Pod:          "test-pod",
+						RestartCount: 2,
+					},
+				}))
+			})
+			It("should not create a recording", func() {
+				t.reconcileRecordingTrigger()
+				Expect(t.getChildRecordings()).To(BeEmpty())
+			})
+			It("should not requeue", func() {
+				result := t.reconcileRecordingTrigger()
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+		Context("when a container restarts after a matching termination", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewObservedRecordingTrigger(0),
+					test.NewRestartedWorkloadTargetPod("OOMKilled", true))
+			})
+			It("should create a recording for the pod", func() {
+				t.reconcileRecordingTrigger()
+				expected := test.NewTriggeredRecording("OOMKilled")
+				recording := t.getRecording(expected.Name)
+				Expect(recording.Labels).To(Equal(expected.Labels))
+				Expect(recording.Annotations).To(Equal(expected.Annotations))
+				Expect(recording.OwnerReferences).To(Equal(expected.OwnerReferences))
+				Expect(recording.Spec).To(Equal(expected.Spec))
+			})
+			It("should update the status", func() {
+				t.reconcileRecordingTrigger()
+				trigger := t.getRecordingTrigger()
+				Expect(trigger.Status.Pods).To(HaveLen(1))
+				status := trigger.Status.Pods[0]
+				Expect(status.RestartCount).To(Equal(int32(1)))
+				Expect(status.PendingReason).To(BeEmpty())
+				Expect(status.LastRecording).To(Equal(test.NewTriggeredRecording("OOMKilled").Name))
+				Expect(status.LastTriggerTime).ToNot(BeNil())
+				Expect(status.LastTriggerTime.Time).To(BeTemporally("~", test.NewTriggerTime(), time.Second))
+				Expect(trigger.Status.LastTriggerTime).ToNot(BeNil())
+				Expect(trigger.Status.LastTriggerTime.Time).To(BeTemporally("~", test.NewTriggerTime(), time.Second))
+			})
+			It("should emit an event", func() {
+				t.reconcileRecordingTrigger()
+				events := t.getEvents()
+				Expect(events).To(HaveLen(1))
+				Expect(events[0]).To(HavePrefix("Normal RecordingTriggered"))
+			})
+			It("should not create a second recording", func() {
+				t.reconcileRecordingTrigger()
+				t.reconcileRecordingTrigger()
+				Expect(t.getChildRecordings()).To(HaveLen(1))
+			})
+		})
+		Context("when the recording template sets a TTL", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewObservedRecordingTrigger(0),
+					test.NewRestartedWorkloadTargetPod("OOMKilled", true))
+				t.updateTriggerSpec(func(spec *operatorv1beta1.RecordingTriggerSpec) {
+					ttl := int32(600)
+					spec.RecordingTemplate.Spec.TTLSecondsAfterFinished = &ttl
+				})
+			})
+			It("should use the template's TTL", func() {
+				t.reconcileRecordingTrigger()
+				recording := t.getRecording(test.NewTriggeredRecording("OOMKilled").Name)
+				Expect(recording.Spec.TTLSecondsAfterFinished).ToNot(BeNil())
+				Expect(*recording.Spec.TTLSecondsAfterFinished).To(Equal(int32(600)))
+			})
+		})
+		Context("with a long name when a container restarts", func() {
+			var triggerName string
+			BeforeEach(func() {
+				trigger := test.NewObservedRecordingTrigger(0)
+				trigger.Name = strings.Repeat("a", 100)
+				triggerName = trigger.Name
+				t.objs = append(t.objs, trigger, test.NewRestartedWorkloadTargetPod("OOMKilled", true))
+			})
+			It("should create a recording with a valid label", func() {
+				t.reconcileRecordingTriggerNamed(triggerName)
+				recordings := &operatorv1beta1.RecordingList{}
+				err := t.Client.List(context.Background(), recordings, client.InNamespace("default"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(recordings.Items).To(HaveLen(1))
+				value := recordings.Items[0].Labels[operatorv1beta1.RecordingTriggerLabel]
+				Expect(validation.IsValidLabelValue(value)).To(BeEmpty())
+				Expect(value).To(MatchRegexp("^a+-[0-9a-f]{8}$"))
+			})
+		})
+		Context("when a pod with a long name restarts", func() {
+			var podName string
+			BeforeEach(func() {
+				podName = strings.Repeat("a", 250)
+				trigger := test.NewObservedRecordingTrigger(0)
+				trigger.Status.Pods[0].Pod = podName
+				pod := test.NewRestartedWorkloadTargetPod("OOMKilled", true)
+				pod.Name = podName
+				jfr := test.NewFlightRecorder()
+				jfr.Name = podName
+				jfr.Status.Target.Name = podName
+				t.objs = []runtime.Object{trigger, pod, jfr}
+			})
+			It("should create a recording with a valid name", func() {
+				t.reconcileRecordingTrigger()
+				recordings := t.getChildRecordings()
+				Expect(recordings).To(HaveLen(1))
+				name := recordings[0].Name
+				Expect(len(name)).To(BeNumerically("<=", 253))
+				Expect(name).To(HavePrefix("crash-trigger-aaaa"))
+				Expect(name).To(MatchRegexp("-[0-9a-f]{8}-26997840$"))
+			})
+			It("should reference the recording in the status", func() {
+				t.reconcileRecordingTrigger()
+				recordings := t.getChildRecordings()
+				Expect(recordings).To(HaveLen(1))
+				trigger := t.getRecordingTrigger()
+				Expect(trigger.Status.Pods[0].LastRecording).To(Equal(recordings[0].Name))
+			})
+		})
+		Context("when a restarted container is not ready yet", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewObservedRecordingTrigger(0),
+					test.NewRestartedWorkloadTargetPod("OOMKilled", false))
+			})
+			It("should not create a recording", func() {
+				t.reconcileRecordingTrigger()
+				Expect(t.getChildRecordings()).To(BeEmpty())
+			})
+			It("should remember the reason", func() {
+				t.reconcileRecordingTrigger()
+				trigger := t.getRecordingTrigger()
+				Expect(trigger.Status.Pods[0].PendingReason).To(Equal("OOMKilled"))
+			})
+			It("should requeue after 10 seconds", func() {
+				result := t.reconcileRecordingTrigger()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+			})
+		})
+		Context("when a container restarts after a termination that does not match", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewObservedRecordingTrigger(0),
+					test.NewRestartedWorkloadTargetPod("Error", true))
+			})
+			It("should not create a recording", func() {
+				t.reconcileRecordingTrigger()
+				Expect(t.getChildRecordings()).To(BeEmpty())
+			})
+			It("should record the new restart count", func() {
+				t.reconcileRecordingTrigger()
+				trigger := t.getRecordingTrigger()
+				Expect(trigger.Status.Pods[0].RestartCount).To(Equal(int32(1)))
+				Expect(trigger.Status.Pods[0].PendingReason).To(BeEmpty())
+			})
+			Context("with onRestart set", func() {
+				BeforeEach(func() {
+					t.updateTriggerSpec(func(spec *operatorv1beta1.RecordingTriggerSpec) {
+						spec.OnRestart = true
+					})
+				})
+				It("should create a recording for the pod", func() {
+					t.reconcileRecordingTrigger()
+					expected := test.NewTriggeredRecording("Restarted")
+					recording := t.getRecording(expected.Name)
+					Expect(recording.Annotations).To(Equal(expected.Annotations))
+				})
+			})
+			Context("with a different container name", func() {
+				BeforeEach(func() {
+					t.updateTriggerSpec(func(spec *operatorv1beta1.RecordingTriggerSpec) {
+						spec.OnRestart = true
+						spec.ContainerName = "other-container"
+					})
+				})
+				It("should not create a recording", func() {
+					t.reconcileRecordingTrigger()
+					Expect(t.getChildRecordings()).To(BeEmpty())
+				})
+			})
+		})
+		Context("with a container in a crash loop", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewRecordingTrigger(), test.NewCrashLoopWorkloadTargetPod())
+			})
+			It("should remember the reason", func() {
+				t.reconcileRecordingTrigger()
+				trigger := t.getRecordingTrigger()
+				Expect(trigger.Status.Pods[0].PendingReason).To(Equal("CrashLoopBackOff"))
+				Expect(t.getChildRecordings()).To(BeEmpty())
+			})
+		})
+		Context("when a container recovers from a crash loop", func() {
+			BeforeEach(func() {
+				trigger := test.NewObservedRecordingTrigger(1)
+				trigger.Status.Pods[0].PendingReason = "CrashLoopBackOff"
+				t.objs = append(t.objs, trigger, test.NewRunningWorkloadTargetPod(1))
+			})
+			It("should create a recording for the pod", func() {
+				t.reconcileRecordingTrigger()
+				expected := test.NewTriggeredRecording("CrashLoopBackOff")
+				recording := t.getRecording(expected.Name)
+				Expect(recording.Annotations).To(Equal(expected.Annotations))
+			})
+		})
+		Context("when triggered during the cooldown", func() {
+			BeforeEach(func() {
+				trigger := test.NewObservedRecordingTrigger(0)
+				lastTrigger := metav1.NewTime(test.NewTriggerTime().Add(-5 * time.Minute))
+				trigger.Status.Pods[0].LastTriggerTime = &lastTrigger
+				t.objs = append(t.objs, trigger, test.NewRestartedWorkloadTargetPod("OOMKilled", true))
+			})
+			It("should not create a recording", func() {
+				t.reconcileRecordingTrigger()
+				Expect(t.getChildRecordings()).To(BeEmpty())
+			})
+			It("should discard the trigger", func() {
+				t.reconcileRecordingTrigger()
+				trigger := t.getRecordingTrigger()
+				Expect(trigger.Status.Pods[0].PendingReason).To(BeEmpty())
+				Expect(trigger.Status.Pods[0].RestartCount).To(Equal(int32(1)))
+			})
+			It("should emit an event", func() {
+				t.reconcileRecordingTrigger()
+				events := t.getEvents()
+				Expect(events).To(HaveLen(1))
+				Expect(events[0]).To(HavePrefix("Normal TriggerSuppressed"))
+			})
+			Context("with a shorter cooldown", func() {
+				BeforeEach(func() {
+					t.updateTriggerSpec(func(spec *operatorv1beta1.RecordingTriggerSpec) {
+						spec.Cooldown = &metav1.Duration{Duration: time.Minute}
+					})
+				})
+				It("should create a recording for the pod", func() {
+					t.reconcileRecordingTrigger()
+					Expect(t.getChildRecordings()).To(HaveLen(1))
+				})
+			})
+		})
+		Context("when the pod has no FlightRecorder", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewObservedRecordingTrigger(0), test.NewRestartedWorkloadTargetPod("OOMKilled", true),
+				}
+			})
+			It("should not create a recording", func() {
+				t.reconcileRecordingTrigger()
+				Expect(t.getChildRecordings()).To(BeEmpty())
+			})
+			It("should keep the trigger and requeue", func() {
+				result := t.reconcileRecordingTrigger()
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+				trigger := t.getRecordingTrigger()
+				Expect(trigger.Status.Pods[0].PendingReason).To(Equal("OOMKilled"))
+			})
+		})
+		Context("when the pod is terminating", func() {
+			BeforeEach(func() {
+				pod := test.NewRestartedWorkloadTargetPod("OOMKilled", true)
+				delTime := metav1.NewTime(test.NewTriggerTime())
+				pod.DeletionTimestamp = &delTime
+				t.objs = append(t.objs, test.NewObservedRecordingTrigger(0), pod)
+			})
+			It("should not create a recording", func() {
+				t.reconcileRecordingTrigger()
+				Expect(t.getChildRecordings()).To(BeEmpty())
+			})
+			It("should forget the pod", func() {
+				t.reconcileRecordingTrigger()
+				trigger := t.getRecordingTrigger()
+				Expect(trigger.Status.Pods).To(BeEmpty())
+			})
+		})
+		Context("when the pod no longer exists", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewObservedRecordingTrigger(0))
+			})
+			It("should forget the pod", func() {
+				t.reconcileRecordingTrigger()
+				trigger := t.getRecordingTrigger()
+				Expect(trigger.Status.Pods).To(BeEmpty())
+			})
+		})
+		Context("with an invalid selector", func() {
+			BeforeEach(func() {
+				trigger := test.NewRecordingTrigger()
+				trigger.Spec.Selector = metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "app",
+							Operator: "Bad",
+						},
+					},
+				}
+				t.objs = append(t.objs, trigger, test.NewRestartedWorkloadTargetPod("OOMKilled", true))
+			})
+			It("should emit a warning event", func() {
+				result := t.reconcileRecordingTrigger()
+				Expect(result).To(Equal(reconcile.Result{}))
+				events := t.getEvents()
+				Expect(events).To(HaveLen(1))
+				Expect(events[0]).To(HavePrefix(corev1.EventTypeWarning + " InvalidSpec"))
+			})
+		})
+		Context("after the trigger is deleted", func() {
+			It("should do nothing", func() {
+				result := t.reconcileRecordingTrigger()
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+	})
+})
+
+func (t *recordingTriggerTestInput) setNow(now time.Time) {
+	t.Now = &now
+}
+
+func (t *recordingTriggerTestInput) updateTriggerSpec(update func(spec *operatorv1beta1.RecordingTriggerSpec)) {
+	for _, obj := range t.objs {
+		if trigger, ok := obj.(*operatorv1beta1.RecordingTrigger); ok {
+			update(&trigger.Spec)
+		}
+	}
+}
+
+func (t *recordingTriggerTestInput) reconcileRecordingTrigger() reconcile.Result {
+	return t.reconcileRecordingTriggerNamed("crash-trigger")
+}
+
+func (t *recordingTriggerTestInput) reconcileRecordingTriggerNamed(name string) reconcile.Result {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	return result
+}
+
+func (t *recordingTriggerTestInput) getRecordingTrigger() *operatorv1beta1.RecordingTrigger {
+	trigger := &operatorv1beta1.RecordingTrigger{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "crash-trigger", Namespace: "default"}, trigger)
+	Expect(err).ToNot(HaveOccurred())
+	return trigger
+}
+
+func (t *recordingTriggerTestInput) getRecording(name string) *operatorv1beta1.Recording {
+	recording := &operatorv1beta1.Recording{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, recording)
+	Expect(err).ToNot(HaveOccurred())
+	return recording
+}
+
+func (t *recordingTriggerTestInput) getChildRecordings() []operatorv1beta1.Recording {
+	recordings := &operatorv1beta1.RecordingList{}
+	err := t.Client.List(context.Background(), recordings, client.InNamespace("default"),
+		client.MatchingLabels{operatorv1beta1.RecordingTriggerLabel: "crash-trigger"})
+	Expect(err).ToNot(HaveOccurred())
+	return recordings.Items
+}
+
+func (t *recordingTriggerTestInput) getEvents() []string {
+	recorder := t.controller.EventRecorder.(*record.FakeRecorder)
+	events := []string{}
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	return events
+}
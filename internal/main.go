@@ -19,7 +19,10 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -66,11 +69,46 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var alertAddr string
+	var alertTemplate string
+	var alertTemplateType string
+	var alertDuration time.Duration
+	var alertTTL time.Duration
+	var alertTokenFile string
+	var alertNamespaces string
+	var orphanSweepInterval time.Duration
+	var resyncInterval time.Duration
+	var compactEventCatalog bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&alertAddr, "alert-receiver-bind-address", "0",
+		"The address the Alertmanager webhook receiver binds to. Set to 0 to disable the receiver.")
+	flag.StringVar(&alertTemplate, "alert-receiver-template", "Profiling",
+		"The event template used for recordings started by alerts.")
+	flag.StringVar(&alertTemplateType, "alert-receiver-template-type", string(operatorv1beta1.TemplateTypeTarget),
+		"The type of the event template used for recordings started by alerts, either TARGET or CUSTOM.")
+	flag.DurationVar(&alertDuration, "alert-receiver-duration", 5*time.Minute,
+		"The duration of recordings started by alerts. Set to 0 for continuous recordings.")
+	flag.DurationVar(&alertTTL, "alert-receiver-ttl", 24*time.Hour,
+		"How long to keep recordings started by alerts once they have finished. Set to 0 to keep them.")
+	flag.StringVar(&alertTokenFile, "alert-receiver-token-file", "",
+		"A file containing the bearer token Alertmanager must send to the alert receiver. "+
+			"Required when the alert receiver is enabled.")
+	flag.StringVar(&alertNamespaces, "alert-receiver-namespaces", "",
+		"A comma-separated list of namespaces in which alerts may start recordings. "+
+			"Defaults to the namespace watched by the operator.")
+	flag.DurationVar(&orphanSweepInterval, "orphaned-recording-sweep-interval", 0,
+		"How often to delete recordings the operator created in target JVMs once their Recording no longer exists. "+
+			"Set to 0 to disable.")
+	flag.DurationVar(&resyncInterval, "flightrecorder-resync-interval", 10*time.Minute,
+		"How often to refresh the events, templates and recordings of each FlightRecorder from its target JVM. "+
+			"Set to 0 to only refresh when the FlightRecorder or its target pod changes.")
+	flag.BoolVar(&compactEventCatalog, "flightrecorder-compact-event-catalog", false,
+		"Store the events available in each FlightRecorder's target JVM in a ConfigMap shared by "+
+			"FlightRecorders with the same events, instead of in its status.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -122,9 +160,10 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&controllers.RecordingReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Recording"),
-		Scheme: mgr.GetScheme(),
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("Recording"),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("recording-controller"),
 		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
 			Client: mgr.GetClient(),
 		}),
@@ -133,9 +172,13 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&controllers.FlightRecorderReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("FlightRecorder"),
-		Scheme: mgr.GetScheme(),
+		Client:              mgr.GetClient(),
+		Log:                 ctrl.Log.WithName("controllers").WithName("FlightRecorder"),
+		Scheme:              mgr.GetScheme(),
+		EventRecorder:       mgr.GetEventRecorderFor("flightrecorder-controller"),
+		OrphanSweepInterval: orphanSweepInterval,
+		ResyncInterval:      resyncInterval,
+		CompactEventCatalog: compactEventCatalog,
 		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
 			Client: mgr.GetClient(),
 		}),
@@ -154,8 +197,101 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Endpoints")
 		os.Exit(1)
 	}
+	if err = (&controllers.CronRecordingReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("CronRecording"),
+		Scheme: mgr.GetScheme(),
+		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CronRecording")
+		os.Exit(1)
+	}
+	if err = (&controllers.ArchiveRetentionReconciler{
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("ArchiveRetention"),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("archive-retention-controller"),
+		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ArchiveRetention")
+		os.Exit(1)
+	}
+	if err = (&controllers.IncidentCaptureReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("IncidentCapture"),
+		Scheme: mgr.GetScheme(),
+		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IncidentCapture")
+		os.Exit(1)
+	}
+	if err = (&controllers.RecordingTriggerReconciler{
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("RecordingTrigger"),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("recordingtrigger-controller"),
+		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RecordingTrigger")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
+	if alertAddr != "0" {
+		templateType := operatorv1beta1.TemplateType(alertTemplateType)
+		if templateType != operatorv1beta1.TemplateTypeTarget && templateType != operatorv1beta1.TemplateTypeCustom {
+			setupLog.Error(fmt.Errorf("unknown template type %q", alertTemplateType),
+				"unable to create alert receiver")
+			os.Exit(1)
+		}
+		if len(alertTokenFile) == 0 {
+			setupLog.Error(fmt.Errorf("--alert-receiver-token-file must be set"), "unable to create alert receiver")
+			os.Exit(1)
+		}
+		token, err := ioutil.ReadFile(alertTokenFile)
+		if err != nil {
+			setupLog.Error(err, "unable to read alert receiver token")
+			os.Exit(1)
+		}
+		if len(strings.TrimSpace(string(token))) == 0 {
+			setupLog.Error(fmt.Errorf("%s is empty", alertTokenFile), "unable to read alert receiver token")
+			os.Exit(1)
+		}
+		namespaces := splitNamespaces(alertNamespaces)
+		if len(namespaces) == 0 {
+			namespaces = splitNamespaces(watchNamespace)
+		}
+		if len(namespaces) == 0 {
+			setupLog.Error(fmt.Errorf("--alert-receiver-namespaces must be set when watching all namespaces"),
+				"unable to create alert receiver")
+			os.Exit(1)
+		}
+		if err = mgr.Add(&controllers.AlertReceiver{
+			Client:      mgr.GetClient(),
+			Log:         ctrl.Log.WithName("alerts"),
+			BindAddress: alertAddr,
+			Token:       strings.TrimSpace(string(token)),
+			Namespaces:  namespaces,
+			Template: operatorv1beta1.TemplateReference{
+				Name: alertTemplate,
+				Type: templateType,
+			},
+			Duration: alertDuration,
+			TTL:      alertTTL,
+		}); err != nil {
+			setupLog.Error(err, "unable to create alert receiver")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -203,3 +339,14 @@ func isOpenShift(mgr ctrl.Manager) (bool, error) {
 	}
 	return true, nil
 }
+
+// splitNamespaces parses a comma-separated list of namespaces
+func splitNamespaces(list string) []string {
+	namespaces := []string{}
+	for _, ns := range strings.Split(list, ",") {
+		if ns = strings.TrimSpace(ns); len(ns) > 0 {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
@@ -37,48 +37,101 @@
 package test
 
 import (
+	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
 	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	"github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
 )
 
+const testEventOptions = "jdk.socketRead:enabled=true,jdk.socketWrite:enabled=true"
+
+// Metadata the operator attaches to recordings it creates for the "my-recording" Recording
+const testRecordingMetadata = `{"labels":{"operator.cryostat.io/recording":"my-recording"}}`
+
+// Metadata the operator attaches to recordings it creates for the "test-incident" IncidentCapture
+const testIncidentMetadata = `{"labels":{"operator.cryostat.io/incidentcapture":"test-incident"}}`
+
 func NewDumpHandler() http.HandlerFunc {
-	return createRecordingHandler(30, true)
+	return createRecordingHandler("test-recording", 30, testEventOptions, withRecordingMetadata(nil), true)
 }
 
 func NewDumpFailHandler() http.HandlerFunc {
-	return createRecordingHandler(30, false)
+	return createRecordingHandler("test-recording", 30, testEventOptions, withRecordingMetadata(nil), false)
+}
+
+func NewDumpWithTemplateHandler() http.HandlerFunc {
+	return createRecordingHandler("test-recording", 30, "template=Profiling,type=TARGET", withRecordingMetadata(nil), true)
+}
+
+func NewDumpWithTypedEventsHandler() http.HandlerFunc {
+	return createRecordingHandler("test-recording", 30, testEventOptions+",jdk.socketRead:stackTrace=true", withRecordingMetadata(nil), true)
+}
+
+func NewDumpWithOptionsHandler() http.HandlerFunc {
+	return createRecordingHandler("test-recording", 30, testEventOptions, withRecordingMetadata(map[string]string{
+		"maxSize": "536870912",
+		"maxAge":  "3600",
+		"toDisk":  "true",
+	}), true)
 }
 
 func NewStartHandler() http.HandlerFunc {
-	return createRecordingHandler(0, true)
+	return createRecordingHandler("test-recording", 0, testEventOptions, withRecordingMetadata(nil), true)
 }
 
 func NewStartFailHandler() http.HandlerFunc {
-	return createRecordingHandler(0, false)
+	return createRecordingHandler("test-recording", 0, testEventOptions, withRecordingMetadata(nil), false)
+}
+
+func NewIncidentDumpHandler() http.HandlerFunc {
+	return createRecordingHandler(IncidentRecordingName, 30, "template=Profiling,type=TARGET",
+		map[string]string{"metadata": testIncidentMetadata}, true)
+}
+
+func NewIncidentDumpFailHandler() http.HandlerFunc {
+	return createRecordingHandler(IncidentRecordingName, 30, "template=Profiling,type=TARGET",
+		map[string]string{"metadata": testIncidentMetadata}, false)
+}
+
+func withRecordingMetadata(options map[string]string) map[string]string {
+	result := map[string]string{
+		"metadata": testRecordingMetadata,
+	}
+	for key, value := range options {
+		result[key] = value
+	}
+	return result
 }
 
-func createRecordingHandler(duration int64, succeed bool) http.HandlerFunc {
+func createRecordingHandler(name string, duration int64, events string, options map[string]string,
+	succeed bool) http.HandlerFunc {
 	desc := NewRecordingDescriptors("CREATED", duration)[0]
+	desc.Name = name
 	handlers := []http.HandlerFunc{
 		ghttp.VerifyRequest(http.MethodPost, "/api/v1/targets/1.2.3.4:8001/recordings"),
 		ghttp.VerifyContentType("application/x-www-form-urlencoded"),
-		ghttp.VerifyFormKV("recordingName", "test-recording"),
-		ghttp.VerifyFormKV("events", "jdk.socketRead:enabled=true,jdk.socketWrite:enabled=true"),
+		ghttp.VerifyFormKV("recordingName", name),
+		ghttp.VerifyFormKV("events", events),
 		verifyToken(),
 		verifyJMXAuth(),
 	}
 	if duration > 0 {
 		handlers = append(handlers, ghttp.VerifyFormKV("duration", strconv.Itoa(int(duration))))
 	}
+	for key, value := range options {
+		handlers = append(handlers, ghttp.VerifyFormKV(key, value))
+	}
 	if succeed {
 		handlers = append(handlers, ghttp.RespondWithJSONEncoded(http.StatusOK, desc))
 	} else {
 		handlers = append(handlers, ghttp.RespondWith(http.StatusBadRequest,
-			"Recording with name \"test-recording\" already exists"))
+			"Recording with name \""+name+"\" already exists"))
 	}
 	return ghttp.CombineHandlers(handlers...)
 }
@@ -109,26 +162,61 @@ func stopHandler(succeed bool) http.HandlerFunc {
 }
 
 func NewSaveHandler() http.HandlerFunc {
-	return saveHandler(true)
+	return saveHandler("test-recording", true)
 }
 
 func NewSaveFailHandler() http.HandlerFunc {
-	return saveHandler(false)
+	return saveHandler("test-recording", false)
+}
+
+// NewSaveNamedHandler expects the recording with the given name to be saved,
+// and responds with the filename "saved-<name>.jfr"
+func NewSaveNamedHandler(name string) http.HandlerFunc {
+	return saveHandler(name, true)
 }
 
-func saveHandler(succeed bool) http.HandlerFunc {
+// NewSaveNamedFailHandler expects the recording with the given name to be saved,
+// and responds as if it does not exist
+func NewSaveNamedFailHandler(name string) http.HandlerFunc {
+	return saveHandler(name, false)
+}
+
+func saveHandler(name string, succeed bool) http.HandlerFunc {
 	handlers := []http.HandlerFunc{
-		ghttp.VerifyRequest(http.MethodPatch, "/api/v1/targets/1.2.3.4:8001/recordings/test-recording"),
+		ghttp.VerifyRequest(http.MethodPatch, "/api/v1/targets/1.2.3.4:8001/recordings/"+name),
 		ghttp.VerifyContentType("text/plain"),
 		ghttp.VerifyBody([]byte("save")),
 		verifyToken(),
 		verifyJMXAuth(),
 	}
 	if succeed {
-		handlers = append(handlers, ghttp.RespondWith(http.StatusOK, "saved-test-recording.jfr"))
+		handlers = append(handlers, ghttp.RespondWith(http.StatusOK, "saved-"+name+".jfr"))
 	} else {
 		handlers = append(handlers, ghttp.RespondWith(http.StatusNotFound,
-			"Recording with name \"test-recording\" not found"))
+			"Recording with name \""+name+"\" not found"))
+	}
+	return ghttp.CombineHandlers(handlers...)
+}
+
+func NewSnapshotHandler() http.HandlerFunc {
+	return snapshotHandler(true)
+}
+
+func NewSnapshotFailHandler() http.HandlerFunc {
+	return snapshotHandler(false)
+}
+
+func snapshotHandler(succeed bool) http.HandlerFunc {
+	handlers := []http.HandlerFunc{
+		ghttp.VerifyRequest(http.MethodPost, "/api/v1/targets/1.2.3.4:8001/snapshot"),
+		verifyToken(),
+		verifyJMXAuth(),
+	}
+	if succeed {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusOK, "snapshot-1"))
+	} else {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusInternalServerError,
+			"Failed to create snapshot"))
 	}
 	return ghttp.CombineHandlers(handlers...)
 }
@@ -142,6 +230,14 @@ func NewListHandler(descriptors []cryostatClient.RecordingDescriptor) http.Handl
 	)
 }
 
+func NewListNoJMXAuthHandler(descriptors []cryostatClient.RecordingDescriptor) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodGet, "/api/v1/targets/1.2.3.4:8001/recordings"),
+		verifyToken(),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, descriptors),
+	)
+}
+
 func NewListFailHandler(descriptors []cryostatClient.RecordingDescriptor) http.HandlerFunc {
 	return ghttp.CombineHandlers(
 		ghttp.VerifyRequest(http.MethodGet, "/api/v1/targets/1.2.3.4:8001/recordings"),
@@ -164,6 +260,42 @@ func NewRecordingDescriptors(state string, duration int64) []cryostatClient.Reco
 	}
 }
 
+func NewOwnedRecordingDescriptors(state string, duration int64, owner string) []cryostatClient.RecordingDescriptor {
+	descriptors := NewRecordingDescriptors(state, duration)
+	descriptors[0].Metadata.Labels = map[string]string{
+		operatorv1beta1.RecordingOwnerLabel: owner,
+	}
+	return descriptors
+}
+
+func NewIncidentDescriptors(state string) []cryostatClient.RecordingDescriptor {
+	descriptors := NewRecordingDescriptors(state, 30000)
+	descriptors[0].Name = IncidentRecordingName
+	descriptors[0].DownloadURL = "http://path/to/" + IncidentRecordingName + ".jfr"
+	descriptors[0].ReportURL = "http://path/to/" + IncidentRecordingName + ".html"
+	descriptors[0].Metadata.Labels = map[string]string{
+		operatorv1beta1.IncidentCaptureLabel: "test-incident",
+	}
+	return descriptors
+}
+
+// NewIncidentSnapshotDescriptors returns the snapshot taken by NewSnapshotHandler
+func NewIncidentSnapshotDescriptors() []cryostatClient.RecordingDescriptor {
+	descriptors := NewRecordingDescriptors("STOPPED", 0)
+	descriptors[0].Name = "snapshot-1"
+	descriptors[0].DownloadURL = "http://path/to/snapshot-1.jfr"
+	descriptors[0].ReportURL = "http://path/to/snapshot-1.html"
+	return descriptors
+}
+
+func NewRecordingDescriptorsWithOptions(state string, duration int64) []cryostatClient.RecordingDescriptor {
+	descriptors := NewRecordingDescriptors(state, duration)
+	descriptors[0].MaxSize = 536870912
+	descriptors[0].MaxAge = 3600000
+	descriptors[0].ToDisk = true
+	return descriptors
+}
+
 func NewListSavedHandler(saved []cryostatClient.SavedRecording) http.HandlerFunc {
 	return newListSavedHandler(saved, true, true)
 }
@@ -202,9 +334,58 @@ func NewSavedRecordings() []cryostatClient.SavedRecording {
 	}
 }
 
+// NewArchivedFiles returns three 100MiB archived recordings, archived at 02:05
+// on the 1st, 2nd and 3rd of May 2021
+func NewArchivedFiles() []cryostatClient.SavedRecording {
+	files := []cryostatClient.SavedRecording{}
+	for day := 1; day <= 3; day++ {
+		archived := time.Date(2021, time.May, day, 2, 5, 0, 0, time.UTC)
+		name := "10-0-0-1_nightly_" + archived.Format("20060102T150405Z") + ".jfr"
+		files = append(files, cryostatClient.SavedRecording{
+			Name:         name,
+			DownloadURL:  "http://path/to/" + name,
+			ReportURL:    "http://path/to/" + strings.TrimSuffix(name, ".jfr") + ".html",
+			Size:         100 * 1024 * 1024,
+			ArchivedTime: archived.UnixNano() / int64(time.Millisecond),
+		})
+	}
+	// The archive time of the first file is only known from its name
+	files[0].ArchivedTime = 0
+	return files
+}
+
+// NewIncidentSavedRecordings returns the files archived by an IncidentCapture,
+// both when recording for a duration and when taking a snapshot
+func NewIncidentSavedRecordings() []cryostatClient.SavedRecording {
+	return []cryostatClient.SavedRecording{
+		{
+			Name:        "saved-" + IncidentRecordingName + ".jfr",
+			DownloadURL: "http://path/to/saved-" + IncidentRecordingName + ".jfr",
+			ReportURL:   "http://path/to/saved-" + IncidentRecordingName + ".html",
+		},
+		{
+			Name:        "saved-snapshot-1.jfr",
+			DownloadURL: "http://path/to/saved-snapshot-1.jfr",
+			ReportURL:   "http://path/to/saved-snapshot-1.html",
+		},
+	}
+}
+
+func NewSavedRecordingsWithSnapshot() []cryostatClient.SavedRecording {
+	return append(NewSavedRecordings(), cryostatClient.SavedRecording{
+		Name:        "snapshot-test-recording.jfr",
+		DownloadURL: "http://path/to/snapshot-test-recording.jfr",
+		ReportURL:   "http://path/to/snapshot-test-recording.html",
+	})
+}
+
 func NewDeleteHandler() http.HandlerFunc {
+	return NewDeleteNamedHandler("test-recording")
+}
+
+func NewDeleteNamedHandler(name string) http.HandlerFunc {
 	return ghttp.CombineHandlers(
-		ghttp.VerifyRequest(http.MethodDelete, "/api/v1/targets/1.2.3.4:8001/recordings/test-recording"),
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v1/targets/1.2.3.4:8001/recordings/"+name),
 		verifyToken(),
 		verifyJMXAuth(),
 		ghttp.RespondWith(http.StatusOK, nil),
@@ -222,20 +403,36 @@ func NewDeleteFailHandler() http.HandlerFunc {
 }
 
 func NewDeleteSavedHandler() http.HandlerFunc {
-	return newDeleteSavedHandler(true, true)
+	return newDeleteSavedHandler("saved-test-recording.jfr", true, true)
 }
 
 func NewDeleteSavedNoJMXAuthHandler() http.HandlerFunc {
-	return newDeleteSavedHandler(false, true)
+	return newDeleteSavedHandler("saved-test-recording.jfr", false, true)
 }
 
 func NewDeleteSavedFailHandler() http.HandlerFunc {
-	return newDeleteSavedHandler(true, false)
+	return newDeleteSavedHandler("saved-test-recording.jfr", true, false)
+}
+
+func NewDeleteSavedNamedHandler(filename string) http.HandlerFunc {
+	return newDeleteSavedHandler(filename, true, true)
+}
+
+func NewDeleteSavedNamedFailHandler(filename string) http.HandlerFunc {
+	return newDeleteSavedHandler(filename, true, false)
+}
+
+func NewDeleteArchivedFileHandler(filename string) http.HandlerFunc {
+	return newDeleteSavedHandler(filename, false, true)
 }
 
-func newDeleteSavedHandler(jmxAuth bool, succeed bool) http.HandlerFunc {
+func NewDeleteArchivedFileFailHandler(filename string) http.HandlerFunc {
+	return newDeleteSavedHandler(filename, false, false)
+}
+
+func newDeleteSavedHandler(filename string, jmxAuth bool, succeed bool) http.HandlerFunc {
 	handlers := []http.HandlerFunc{
-		ghttp.VerifyRequest(http.MethodDelete, "/api/v1/recordings/saved-test-recording.jfr"),
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v1/recordings/"+filename),
 		verifyToken(),
 	}
 	if jmxAuth {
@@ -244,11 +441,36 @@ func newDeleteSavedHandler(jmxAuth bool, succeed bool) http.HandlerFunc {
 	if succeed {
 		handlers = append(handlers, ghttp.RespondWith(http.StatusOK, nil))
 	} else {
-		handlers = append(handlers, ghttp.RespondWith(http.StatusNotFound, "saved-test-recording.jfr"))
+		handlers = append(handlers, ghttp.RespondWith(http.StatusNotFound, filename))
 	}
 	return ghttp.CombineHandlers(handlers...)
 }
 
+func NewRetainSavedHandler() http.HandlerFunc {
+	return newRetainSavedHandler(true)
+}
+
+func NewRetainSavedNoJMXAuthHandler() http.HandlerFunc {
+	return newRetainSavedHandler(false)
+}
+
+func newRetainSavedHandler(jmxAuth bool) http.HandlerFunc {
+	handlers := []http.HandlerFunc{
+		ghttp.VerifyRequest(http.MethodPost, "/api/beta/recordings/saved-test-recording.jfr/metadata/labels"),
+		verifyToken(),
+		ghttp.VerifyJSONRepresenting(map[string]string{
+			"operator.cryostat.io/recording": "my-recording",
+			"operator.cryostat.io/namespace": "default",
+			"operator.cryostat.io/retain":    "true",
+		}),
+	}
+	if jmxAuth {
+		handlers = append(handlers, verifyJMXAuth())
+	}
+	handlers = append(handlers, ghttp.RespondWith(http.StatusOK, nil))
+	return ghttp.CombineHandlers(handlers...)
+}
+
 func NewListEventTypesHandler() http.HandlerFunc {
 	return ghttp.CombineHandlers(
 		ghttp.VerifyRequest(http.MethodGet, "/api/v1/targets/1.2.3.4:8001/events"),
@@ -267,11 +489,15 @@ func NewListEventTypesNoJMXAuthHandler() http.HandlerFunc {
 }
 
 func NewListEventTypesFailHandler() http.HandlerFunc {
+	return NewListEventTypesErrorHandler(http.StatusUnauthorized, "")
+}
+
+func NewListEventTypesErrorHandler(statusCode int, message string) http.HandlerFunc {
 	return ghttp.CombineHandlers(
 		ghttp.VerifyRequest(http.MethodGet, "/api/v1/targets/1.2.3.4:8001/events"),
 		verifyToken(),
 		verifyJMXAuth(),
-		ghttp.RespondWith(http.StatusUnauthorized, nil),
+		ghttp.RespondWith(statusCode, message),
 	)
 }
 
@@ -329,6 +555,73 @@ func NewListTemplatesFailHandler() http.HandlerFunc {
 	)
 }
 
+func NewListTemplatesWithCustomHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodGet, "/api/v1/targets/1.2.3.4:8001/templates"),
+		verifyToken(),
+		verifyJMXAuth(),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, NewTemplatesWithCustom()),
+	)
+}
+
+func NewUploadTemplateHandler() http.HandlerFunc {
+	return newUploadTemplateHandler(http.StatusOK, "")
+}
+
+func NewUploadTemplateFailHandler() http.HandlerFunc {
+	return newUploadTemplateHandler(http.StatusBadRequest, "Invalid XML")
+}
+
+func NewUploadTemplateExistsHandler() http.HandlerFunc {
+	return newUploadTemplateHandler(http.StatusBadRequest, "Event template \"Custom Profiling\" already exists")
+}
+
+func newUploadTemplateHandler(statusCode int, message string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodPost, "/api/v1/templates"),
+		verifyToken(),
+		func(w http.ResponseWriter, r *http.Request) {
+			file, header, err := r.FormFile("template")
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			defer file.Close()
+			content, err := ioutil.ReadAll(file)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(header.Filename).To(gomega.Equal("custom.jfc"))
+			gomega.Expect(string(content)).To(gomega.Equal(NewCustomTemplate()))
+		},
+		ghttp.RespondWith(statusCode, message),
+	)
+}
+
+func NewDeleteTemplateHandler() http.HandlerFunc {
+	return newDeleteTemplateHandler(http.StatusOK)
+}
+
+func NewDeleteTemplateFailHandler() http.HandlerFunc {
+	return newDeleteTemplateHandler(http.StatusInternalServerError)
+}
+
+func NewDeleteTemplateNotFoundHandler() http.HandlerFunc {
+	return newDeleteTemplateHandler(http.StatusNotFound)
+}
+
+func newDeleteTemplateHandler(statusCode int) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v1/templates/Custom Profiling"),
+		verifyToken(),
+		ghttp.RespondWith(statusCode, nil),
+	)
+}
+
+func NewTemplatesWithCustom() []operatorv1beta1.TemplateInfo {
+	return append(NewTemplates(), operatorv1beta1.TemplateInfo{
+		Name:        "Custom Profiling",
+		Description: "Profiling with socket events enabled",
+		Provider:    "Example",
+		Type:        "CUSTOM",
+	})
+}
+
 func NewTemplates() []operatorv1beta1.TemplateInfo {
 	return []operatorv1beta1.TemplateInfo{
 		{
@@ -340,6 +633,62 @@ func NewTemplates() []operatorv1beta1.TemplateInfo {
 	}
 }
 
+func NewMBeanMetricsHandler() http.HandlerFunc {
+	return newMBeanMetricsHandler(true, http.StatusOK)
+}
+
+func NewMBeanMetricsNoJMXAuthHandler() http.HandlerFunc {
+	return newMBeanMetricsHandler(false, http.StatusOK)
+}
+
+// NewMBeanMetricsNotFoundHandler responds as a Cryostat without the mbeanMetrics endpoint
+func NewMBeanMetricsNotFoundHandler() http.HandlerFunc {
+	return newMBeanMetricsHandler(true, http.StatusNotFound)
+}
+
+func NewMBeanMetricsFailHandler() http.HandlerFunc {
+	return newMBeanMetricsHandler(true, http.StatusInternalServerError)
+}
+
+func newMBeanMetricsHandler(jmxAuth bool, status int) http.HandlerFunc {
+	handlers := []http.HandlerFunc{
+		ghttp.VerifyRequest(http.MethodGet, "/api/beta/targets/1.2.3.4:8001/mbeanMetrics"),
+		verifyToken(),
+	}
+	if jmxAuth {
+		handlers = append(handlers, verifyJMXAuth())
+	}
+	if status == http.StatusOK {
+		handlers = append(handlers, ghttp.RespondWithJSONEncoded(http.StatusOK, NewMBeanMetrics()))
+	} else {
+		handlers = append(handlers, ghttp.RespondWith(status, nil))
+	}
+	return ghttp.CombineHandlers(handlers...)
+}
+
+func NewMBeanMetrics() *cryostatClient.MBeanMetrics {
+	return &cryostatClient.MBeanMetrics{
+		Runtime: cryostatClient.RuntimeMetrics{
+			Name:      "1234@test-pod",
+			VMVendor:  "Eclipse Adoptium",
+			VMVersion: "17.0.2+8",
+			Uptime:    3600000,
+		},
+		Memory: cryostatClient.MemoryMetrics{
+			HeapMemoryUsage: cryostatClient.MemoryUsage{
+				Used:      64 * 1024 * 1024,
+				Committed: 128 * 1024 * 1024,
+				Max:       512 * 1024 * 1024,
+			},
+			NonHeapMemoryUsage: cryostatClient.MemoryUsage{
+				Used:      32 * 1024 * 1024,
+				Committed: 48 * 1024 * 1024,
+				Max:       -1,
+			},
+		},
+	}
+}
+
 func verifyToken() http.HandlerFunc {
 	return ghttp.VerifyHeaderKV("Authorization", "Bearer myToken")
 }
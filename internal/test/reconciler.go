@@ -39,6 +39,7 @@ package test
 import (
 	"net/url"
 	"strconv"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -56,6 +57,7 @@ type TestReconcilerConfig struct {
 	EnvCoreImageTag       *string
 	EnvDatasourceImageTag *string
 	EnvGrafanaImageTag    *string
+	Now                   *time.Time
 }
 
 // NewTestReconciler returns a common.Reconciler for use by unit tests
@@ -103,7 +105,8 @@ func (c *testClientFactory) CreateClient(config *cryostatClient.Config) (cryosta
 }
 
 type testOSUtils struct {
-	envs map[string]string
+	envs  map[string]string
+	clock func() time.Time
 }
 
 func newTestOSUtils(config *TestReconcilerConfig) *testOSUtils {
@@ -120,7 +123,14 @@ func newTestOSUtils(config *TestReconcilerConfig) *testOSUtils {
 	if config.EnvGrafanaImageTag != nil {
 		envs["RELATED_IMAGE_GRAFANA"] = *config.EnvGrafanaImageTag
 	}
-	return &testOSUtils{envs}
+	clock := func() time.Time {
+		// Allow tests to change the current time between reconciles
+		if config.Now != nil {
+			return *config.Now
+		}
+		return time.Now()
+	}
+	return &testOSUtils{envs: envs, clock: clock}
 }
 
 func (o *testOSUtils) GetFileContents(path string) ([]byte, error) {
@@ -131,3 +141,10 @@ func (o *testOSUtils) GetFileContents(path string) ([]byte, error) {
 func (o *testOSUtils) GetEnv(name string) string {
 	return o.envs[name]
 }
+
+func (o *testOSUtils) Now() time.Time {
+	if o.clock == nil {
+		return time.Now()
+	}
+	return o.clock()
+}
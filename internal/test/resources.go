@@ -37,6 +37,10 @@
 package test
 
 import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
 	"time"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
@@ -46,6 +50,7 @@ import (
 	consolev1 "github.com/openshift/api/console/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	securityv1 "github.com/openshift/api/security/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -102,6 +107,28 @@ func NewCryostat() *operatorv1beta1.Cryostat {
 	}
 }
 
+func NewCryostatWithRetention(policy *operatorv1beta1.ArchiveRetentionPolicy) *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.StorageOptions = &operatorv1beta1.StorageConfiguration{
+		Retention: policy,
+	}
+	return cr
+}
+
+func NewCryostatWithRecordingDeletionPolicy(policy operatorv1beta1.RecordingDeletionPolicy) *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.StorageOptions = &operatorv1beta1.StorageConfiguration{
+		RecordingDeletionPolicy: policy,
+	}
+	return cr
+}
+
+func NewCryostatWithFinalizerTimeout(timeout time.Duration) *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.RecordingFinalizerTimeout = &metav1.Duration{Duration: timeout}
+	return cr
+}
+
 func NewCryostatWithSecrets() *operatorv1beta1.Cryostat {
 	cr := NewCryostat()
 	key := "test.crt"
@@ -251,6 +278,160 @@ func NewFlightRecorderForCryostat() *operatorv1beta1.FlightRecorder {
 	return recorder
 }
 
+func NewFlightRecorderWithTemplates() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	recorder.Status.Templates = NewTemplates()
+	return recorder
+}
+
+func NewFlightRecorderWithEvents() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	recorder.Status.Events = NewEventTypes()
+	return recorder
+}
+
+func NewAdoptingFlightRecorder() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	recorder.Annotations = map[string]string{
+		operatorv1beta1.AdoptRecordingsAnnotation: "true",
+	}
+	return recorder
+}
+
+func NewFlightRecorderWithEventTemplates() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	recorder.Spec.EventTemplates = []operatorv1beta1.TemplateConfigMap{
+		{
+			ConfigMapName: "custom-template",
+			Filename:      "custom.jfc",
+		},
+	}
+	return recorder
+}
+
+func NewFlightRecorderWithUploadedTemplate(hash string) *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	recorder.Status.UploadedTemplates = []operatorv1beta1.UploadedTemplate{NewUploadedTemplate(hash)}
+	return recorder
+}
+
+func NewOtherFlightRecorderWithUploadedTemplate(configMapName string, hash string) *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	recorder.Name = "other-pod"
+	template := NewUploadedTemplate(hash)
+	template.ConfigMapName = configMapName
+	recorder.Status.UploadedTemplates = []operatorv1beta1.UploadedTemplate{template}
+	return recorder
+}
+
+func NewUploadedTemplate(hash string) operatorv1beta1.UploadedTemplate {
+	return operatorv1beta1.UploadedTemplate{
+		Name:          "Custom Profiling",
+		ConfigMapName: "custom-template",
+		Filename:      "custom.jfc",
+		Hash:          hash,
+	}
+}
+
+func NewCustomTemplateConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "custom-template",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"custom.jfc": NewCustomTemplate(),
+		},
+	}
+}
+
+func NewCustomTemplate() string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<configuration version="2.0" label="Custom Profiling" description="Profiling with socket events enabled" provider="Example">
+  <event name="jdk.SocketRead">
+    <setting name="enabled">true</setting>
+    <setting name="threshold">20 ms</setting>
+  </event>
+</configuration>
+`
+}
+
+func NewFlightRecorderWithEventCatalog() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	recorder.Status.EventCatalog = &operatorv1beta1.EventCatalogReference{
+		ConfigMapName: NewEventCatalogName(),
+		EventCount:    int32(len(NewEventTypes())),
+	}
+	return recorder
+}
+
+func NewFlightRecorderWithOldEventCatalog() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	recorder.Status.EventCatalog = &operatorv1beta1.EventCatalogReference{
+		ConfigMapName: "flightrecorder-events-old",
+		EventCount:    0,
+	}
+	return recorder
+}
+
+// NewOtherFlightRecorderWithEventCatalog returns a FlightRecorder for another pod
+// that refers to the named event catalog
+func NewOtherFlightRecorderWithEventCatalog(configMapName string) *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	recorder.Name = "other-pod"
+	recorder.Status.EventCatalog = &operatorv1beta1.EventCatalogReference{
+		ConfigMapName: configMapName,
+	}
+	return recorder
+}
+
+func NewEventCatalogName() string {
+	data, err := json.Marshal(NewEventTypes())
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	hash := fnv.New64a()
+	hash.Write(data)
+	return fmt.Sprintf("flightrecorder-events-%016x", hash.Sum64())
+}
+
+func NewEventCatalogConfigMap() *corev1.ConfigMap {
+	data, err := json.Marshal(NewEventTypes())
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	return newEventCatalogConfigMap(NewEventCatalogName(), string(data))
+}
+
+func NewOldEventCatalogConfigMap() *corev1.ConfigMap {
+	return newEventCatalogConfigMap("flightrecorder-events-old", "[]")
+}
+
+func newEventCatalogConfigMap(name string, data string) *corev1.ConfigMap {
+	immutable := true
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				operatorv1beta1.EventCatalogLabel: "true",
+			},
+		},
+		Data: map[string]string{
+			operatorv1beta1.EventCatalogKey: data,
+		},
+		Immutable: &immutable,
+	}
+}
+
+func NewJVMRecordings(recording string) []operatorv1beta1.JVMRecordingInfo {
+	return []operatorv1beta1.JVMRecordingInfo{
+		{
+			Name:      "test-recording",
+			State:     operatorv1beta1.RecordingStateRunning,
+			StartTime: metav1.Unix(0, 1597090030341*int64(time.Millisecond)),
+			Duration:  metav1.Duration{Duration: 30 * time.Second},
+			Recording: recording,
+		},
+	}
+}
+
 func newFlightRecorder(jmxAuth *operatorv1beta1.JMXAuthSecret) *operatorv1beta1.FlightRecorder {
 	return &operatorv1beta1.FlightRecorder{
 		TypeMeta: metav1.TypeMeta{
@@ -288,6 +469,28 @@ func newFlightRecorder(jmxAuth *operatorv1beta1.JMXAuthSecret) *operatorv1beta1.
 	}
 }
 
+// NewJVMInfo returns the JVM details expected from NewMBeanMetrics
+func NewJVMInfo() *operatorv1beta1.JVMInfo {
+	pid := int64(1234)
+	heapMax := resource.MustParse("512Mi")
+	return &operatorv1beta1.JVMInfo{
+		Vendor:  "Eclipse Adoptium",
+		Version: "17.0.2+8",
+		PID:     &pid,
+		Uptime:  metav1.Duration{Duration: time.Hour},
+		HeapUsage: operatorv1beta1.JVMMemoryUsage{
+			Used:      resource.MustParse("64Mi"),
+			Committed: resource.MustParse("128Mi"),
+			Max:       &heapMax,
+		},
+		NonHeapUsage: operatorv1beta1.JVMMemoryUsage{
+			Used:      resource.MustParse("32Mi"),
+			Committed: resource.MustParse("48Mi"),
+		},
+		JFRAvailable: true,
+	}
+}
+
 func NewRecording() *operatorv1beta1.Recording {
 	return newRecording(getDuration(false), nil, nil, false)
 }
@@ -306,12 +509,89 @@ func NewRunningContinuousRecording() *operatorv1beta1.Recording {
 	return newRecording(getDuration(true), &running, nil, false)
 }
 
+func NewRunningRecordingWithArchiveInterval() *operatorv1beta1.Recording {
+	running := operatorv1beta1.RecordingStateRunning
+	rec := newRecording(getDuration(true), &running, nil, true)
+	rec.Spec.ArchiveInterval = &metav1.Duration{Duration: time.Hour}
+	return rec
+}
+
+// NewRunningRecordingWithSnapshots returns NewRunningRecordingWithArchiveInterval after
+// the given number of copies have been saved, named "snapshot-<n>-test-recording.jfr"
+// and saved a minute apart
+func NewRunningRecordingWithSnapshots(count int) *operatorv1beta1.Recording {
+	rec := NewRunningRecordingWithArchiveInterval()
+	start := time.Unix(0, 1597090030341*int64(time.Millisecond))
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("snapshot-%d-test-recording", i)
+		rec.Status.ArchivedSnapshots = append(rec.Status.ArchivedSnapshots, operatorv1beta1.ArchivedSnapshot{
+			Name:        name + ".jfr",
+			DownloadURL: "http://path/to/" + name + ".jfr",
+			ReportURL:   "http://path/to/" + name + ".html",
+			Time:        metav1.NewTime(start.Add(time.Duration(i) * time.Minute)),
+			Reason:      operatorv1beta1.ArchivedSnapshotReasonInterval,
+		})
+	}
+	return rec
+}
+
+func NewDeletedRecordingWithSnapshots() *operatorv1beta1.Recording {
+	rec := NewDeletedArchivedRecording()
+	rec.Spec.ArchiveInterval = &metav1.Duration{Duration: time.Hour}
+	rec.Status.ArchivedSnapshots = []operatorv1beta1.ArchivedSnapshot{
+		{
+			Name:        "snapshot-test-recording.jfr",
+			DownloadURL: "http://path/to/snapshot-test-recording.jfr",
+			ReportURL:   "http://path/to/snapshot-test-recording.html",
+			Time:        metav1.Unix(0, 1597093630341*int64(time.Millisecond)),
+		},
+	}
+	return rec
+}
+
+// NewRunningRecordingWithTerminationSnapshot returns a running recording that was
+// already saved after the shutdown of NewTerminatingTargetPod began
+func NewRunningRecordingWithTerminationSnapshot() *operatorv1beta1.Recording {
+	rec := NewRunningContinuousRecording()
+	rec.Status.ArchivedSnapshots = []operatorv1beta1.ArchivedSnapshot{
+		{
+			Name:        "snapshot-test-recording.jfr",
+			DownloadURL: "http://path/to/snapshot-test-recording.jfr",
+			ReportURL:   "http://path/to/snapshot-test-recording.html",
+			Time:        metav1.NewTime(NewPodTerminationTime().Add(10 * time.Second)),
+			Reason:      operatorv1beta1.ArchivedSnapshotReasonTargetTerminating,
+		},
+	}
+	return rec
+}
+
+// NewRecordingWithChangedSpec returns a running recording whose duration was changed
+// to record indefinitely, after it was created from an earlier generation of its spec
+func NewRecordingWithChangedSpec(policy operatorv1beta1.RecordingRestartPolicy, archive bool) *operatorv1beta1.Recording {
+	running := operatorv1beta1.RecordingStateRunning
+	rec := newRecording(getDuration(true), &running, nil, archive)
+	setChangedSpec(rec, policy)
+	rec.Status.Duration = metav1.Duration{Duration: getDuration(false)}
+	return rec
+}
+
+func setChangedSpec(rec *operatorv1beta1.Recording, policy operatorv1beta1.RecordingRestartPolicy) {
+	rec.Generation = 2
+	rec.Spec.RestartPolicy = policy
+	rec.Status.RecordingGeneration = 1
+	rec.Status.RecordingSpecHash = "0123456789abcdef"
+}
+
 func NewRecordingToStop() *operatorv1beta1.Recording {
 	running := operatorv1beta1.RecordingStateRunning
 	stopped := operatorv1beta1.RecordingStateStopped
 	return newRecording(getDuration(true), &running, &stopped, false)
 }
 
+func NewRecordingToArchive() *operatorv1beta1.Recording {
+	return newRecording(getDuration(false), nil, nil, true)
+}
+
 func NewStoppedRecordingToArchive() *operatorv1beta1.Recording {
 	stopped := operatorv1beta1.RecordingStateStopped
 	return newRecording(getDuration(false), &stopped, nil, true)
@@ -333,13 +613,124 @@ func NewArchivedRecording() *operatorv1beta1.Recording {
 	return rec
 }
 
+func NewArchivedRecordingWithTTL() *operatorv1beta1.Recording {
+	rec := NewArchivedRecording()
+	ttl := int32(3600)
+	rec.Spec.TTLSecondsAfterFinished = &ttl
+	rec.Status.CompletionTime = &metav1.Time{Time: NewRecordingCompletionTime()}
+	rec.Status.Conditions = []metav1.Condition{
+		{
+			Type:               operatorv1beta1.RecordingConditionArchived,
+			Status:             metav1.ConditionTrue,
+			Reason:             "RecordingArchived",
+			Message:            "Recording archived as \"saved-test-recording.jfr\"",
+			LastTransitionTime: metav1.NewTime(NewRecordingCompletionTime()),
+		},
+	}
+	return rec
+}
+
+// NewRecordingCompletionTime returns the time when NewArchivedRecordingWithTTL stopped
+func NewRecordingCompletionTime() time.Time {
+	return time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// NewRecordingDeletionTime returns the time when deleted recordings were deleted
+func NewRecordingDeletionTime() time.Time {
+	return time.Unix(0, 1598045501618*int64(time.Millisecond))
+}
+
+// NewRecordingWindowStart returns the StartAt time of scheduled recordings,
+// their StopAt time is one hour later
+func NewRecordingWindowStart() time.Time {
+	return time.Date(2021, time.May, 1, 22, 0, 0, 0, time.UTC)
+}
+
+func NewScheduledRecording() *operatorv1beta1.Recording {
+	rec := NewContinuousRecording()
+	setRecordingWindow(rec)
+	return rec
+}
+
+func NewRunningScheduledRecording() *operatorv1beta1.Recording {
+	rec := NewRunningContinuousRecording()
+	setRecordingWindow(rec)
+	return rec
+}
+
+func NewScheduledWorkloadRecording() *operatorv1beta1.Recording {
+	rec := NewWorkloadRecording()
+	rec.Spec.Duration = metav1.Duration{}
+	setRecordingWindow(rec)
+	return rec
+}
+
+func NewRunningScheduledWorkloadRecording() *operatorv1beta1.Recording {
+	rec := NewRunningWorkloadRecording(false)
+	rec.Spec.Duration = metav1.Duration{}
+	setRecordingWindow(rec)
+	return rec
+}
+
+func setRecordingWindow(rec *operatorv1beta1.Recording) {
+	startAt := metav1.NewTime(NewRecordingWindowStart())
+	stopAt := metav1.NewTime(startAt.Add(time.Hour))
+	rec.Spec.StartAt = &startAt
+	rec.Spec.StopAt = &stopAt
+}
+
 func NewDeletedArchivedRecording() *operatorv1beta1.Recording {
 	rec := NewArchivedRecording()
-	delTime := metav1.Unix(0, 1598045501618*int64(time.Millisecond))
+	delTime := metav1.NewTime(NewRecordingDeletionTime())
 	rec.DeletionTimestamp = &delTime
 	return rec
 }
 
+func NewForceDeletedArchivedRecording() *operatorv1beta1.Recording {
+	rec := NewDeletedArchivedRecording()
+	rec.Annotations = map[string]string{
+		operatorv1beta1.RecordingForceDeleteAnnotation: "true",
+	}
+	return rec
+}
+
+func NewDeletedArchivedRecordingWithPolicy(policy operatorv1beta1.RecordingDeletionPolicy) *operatorv1beta1.Recording {
+	rec := NewDeletedArchivedRecording()
+	rec.Spec.DeletionPolicy = policy
+	return rec
+}
+
+func NewRecordingWithTemplate(templateName string) *operatorv1beta1.Recording {
+	rec := NewRecording()
+	rec.Spec.EventOptions = nil
+	rec.Spec.Template = &operatorv1beta1.TemplateReference{
+		Name: templateName,
+	}
+	return rec
+}
+
+func NewRecordingWithTypedEvents(typeID string, option string) *operatorv1beta1.Recording {
+	rec := NewRecording()
+	rec.Spec.Events = []operatorv1beta1.EventOption{
+		{
+			TypeID: typeID,
+			Option: option,
+			Value:  "true",
+		},
+	}
+	return rec
+}
+
+func NewRecordingWithOptions() *operatorv1beta1.Recording {
+	rec := NewRecording()
+	maxSize := resource.MustParse("512Mi")
+	toDisk := true
+	rec.Spec.MaxSize = &maxSize
+	rec.Spec.MaxAge = &metav1.Duration{Duration: time.Hour}
+	rec.Spec.ToDisk = &toDisk
+	return rec
+}
+
 func newRecording(duration time.Duration, currentState *operatorv1beta1.RecordingState,
 	requestedState *operatorv1beta1.RecordingState, archive bool) *operatorv1beta1.Recording {
 	finalizers := []string{}
@@ -379,6 +770,359 @@ func newRecording(duration time.Duration, currentState *operatorv1beta1.Recordin
 	}
 }
 
+func NewWorkloadRecording() *operatorv1beta1.Recording {
+	rec := NewRecording()
+	rec.Spec.FlightRecorder = nil
+	rec.Spec.Workload = &operatorv1beta1.WorkloadReference{
+		Kind: operatorv1beta1.WorkloadKindDeployment,
+		Name: "test-app",
+	}
+	return rec
+}
+
+func NewSelectorRecording() *operatorv1beta1.Recording {
+	rec := NewRecording()
+	rec.Spec.FlightRecorder = nil
+	rec.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"app": "test-app",
+		},
+	}
+	return rec
+}
+
+func NewRunningWorkloadRecording(archive bool) *operatorv1beta1.Recording {
+	rec := NewWorkloadRecording()
+	running := operatorv1beta1.RecordingStateRunning
+	rec.Spec.Archive = archive
+	rec.Finalizers = []string{"operator.cryostat.io/recording.finalizer"}
+	rec.Status.State = &running
+	rec.Status.Targets = []operatorv1beta1.RecordingTargetStatus{
+		newRecordingTargetStatus("test-pod", running),
+		newRecordingTargetStatus("old-pod", running),
+	}
+	return rec
+}
+
+func NewWorkloadRecordingWithChangedSpec(policy operatorv1beta1.RecordingRestartPolicy) *operatorv1beta1.Recording {
+	rec := NewRunningWorkloadRecording(false)
+	rec.Spec.Duration = metav1.Duration{Duration: getDuration(true)}
+	setChangedSpec(rec, policy)
+	return rec
+}
+
+func NewDeletedWorkloadRecording() *operatorv1beta1.Recording {
+	rec := NewWorkloadRecording()
+	stopped := operatorv1beta1.RecordingStateStopped
+	delTime := metav1.NewTime(NewRecordingDeletionTime())
+	rec.DeletionTimestamp = &delTime
+	rec.Spec.Archive = true
+	rec.Finalizers = []string{"operator.cryostat.io/recording.finalizer"}
+	rec.Status.State = &stopped
+	target := newRecordingTargetStatus("test-pod", stopped)
+	savedDownloadURL := "http://path/to/saved-test-recording.jfr"
+	savedReportURL := "http://path/to/saved-test-recording.html"
+	target.DownloadURL = &savedDownloadURL
+	target.ReportURL = &savedReportURL
+	target.Archived = true
+	rec.Status.Targets = []operatorv1beta1.RecordingTargetStatus{target}
+	return rec
+}
+
+func newRecordingTargetStatus(pod string, state operatorv1beta1.RecordingState) operatorv1beta1.RecordingTargetStatus {
+	startTime := metav1.Unix(0, 1597090030341*int64(time.Millisecond))
+	downloadURL := "http://path/to/test-recording.jfr"
+	reportURL := "http://path/to/test-recording.html"
+	return operatorv1beta1.RecordingTargetStatus{
+		Pod:            pod,
+		FlightRecorder: pod,
+		State:          &state,
+		StartTime:      &startTime,
+		DownloadURL:    &downloadURL,
+		ReportURL:      &reportURL,
+	}
+}
+
+func NewCronRecording() *operatorv1beta1.CronRecording {
+	return &operatorv1beta1.CronRecording{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "nightly",
+			Namespace:         "default",
+			UID:               "0c7dbc5e-1a2a-4a24-bd5f-6e1bd5e4a0e0",
+			CreationTimestamp: metav1.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Spec: operatorv1beta1.CronRecordingSpec{
+			Schedule: "0 2 * * *",
+			RecordingTemplate: operatorv1beta1.RecordingTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": "nightly-profile",
+					},
+				},
+				Spec: NewRecording().Spec,
+			},
+		},
+	}
+}
+
+// NewCronRecordingChild returns a Recording created by NewCronRecording for the run
+// scheduled at 02:00 on the given day of May 2021
+func NewCronRecordingChild(day int, state *operatorv1beta1.RecordingState, failedReason string) *operatorv1beta1.Recording {
+	cron := NewCronRecording()
+	scheduled := time.Date(2021, time.May, day, 2, 0, 0, 0, time.UTC)
+	suffix := strconv.FormatInt(scheduled.Unix()/60, 10)
+	rec := NewRecording()
+	rec.Name = cron.Name + "-" + suffix
+	rec.Spec.Name = rec.Spec.Name + "-" + suffix
+	rec.Labels = map[string]string{
+		"app":                              "nightly-profile",
+		operatorv1beta1.CronRecordingLabel: cron.Name,
+	}
+	rec.Annotations = map[string]string{
+		"operator.cryostat.io/scheduled-at": scheduled.Format(time.RFC3339),
+	}
+	controller := true
+	rec.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: operatorv1beta1.GroupVersion.String(),
+			Kind:       "CronRecording",
+			Name:       cron.Name,
+			UID:        cron.UID,
+			Controller: &controller,
+		},
+	}
+	rec.Status.State = state
+	if len(failedReason) > 0 {
+		rec.Status.Conditions = []metav1.Condition{
+			{
+				Type:   operatorv1beta1.RecordingConditionFailed,
+				Status: metav1.ConditionTrue,
+				Reason: failedReason,
+			},
+		}
+	}
+	return rec
+}
+
+func NewIncidentCapture() *operatorv1beta1.IncidentCapture {
+	capture := NewSnapshotIncidentCapture()
+	capture.Spec.Duration = metav1.Duration{Duration: 30 * time.Second}
+	capture.Spec.Template = &operatorv1beta1.TemplateReference{
+		Name: "Profiling",
+		Type: operatorv1beta1.TemplateTypeTarget,
+	}
+	return capture
+}
+
+func NewSnapshotIncidentCapture() *operatorv1beta1.IncidentCapture {
+	return &operatorv1beta1.IncidentCapture{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-incident",
+			Namespace: "default",
+			UID:       "5b7e2a90-1c4d-4e8f-a6b3-9d2f0e1c7a55",
+		},
+		Spec: operatorv1beta1.IncidentCaptureSpec{
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": "test-app",
+				},
+			},
+		},
+	}
+}
+
+// IncidentRecordingName is the name of the recording NewIncidentCapture creates in each target JVM
+const IncidentRecordingName = "test-incident-5b7e2a90"
+
+// NewRecordingIncidentCapture returns NewIncidentCapture once the recording has
+// started on test-pod, and test-pod-2 has failed for lack of a FlightRecorder
+func NewRecordingIncidentCapture() *operatorv1beta1.IncidentCapture {
+	capture := NewIncidentCapture()
+	capture.Finalizers = []string{"operator.cryostat.io/incidentcapture.finalizer"}
+	startTime := metav1.Unix(0, 1597090030341*int64(time.Millisecond))
+	capture.Status.StartTime = &startTime
+	capture.Status.Targets = []operatorv1beta1.IncidentCaptureTarget{
+		{
+			Pod:            "test-pod",
+			FlightRecorder: "test-pod",
+			State:          operatorv1beta1.IncidentCaptureTargetRecording,
+			RecordingName:  IncidentRecordingName,
+		},
+		{
+			Pod:     "test-pod-2",
+			State:   operatorv1beta1.IncidentCaptureTargetFailed,
+			Message: "No FlightRecorder found for this pod",
+		},
+	}
+	return capture
+}
+
+// NewSnapshottedIncidentCapture returns NewSnapshotIncidentCapture once a snapshot
+// has been taken on test-pod, and test-pod-2 has failed for lack of a FlightRecorder
+func NewSnapshottedIncidentCapture() *operatorv1beta1.IncidentCapture {
+	capture := NewRecordingIncidentCapture()
+	capture.Spec = NewSnapshotIncidentCapture().Spec
+	capture.Status.Targets[0].RecordingName = "snapshot-1"
+	return capture
+}
+
+func NewRecordingTrigger() *operatorv1beta1.RecordingTrigger {
+	return &operatorv1beta1.RecordingTrigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crash-trigger",
+			Namespace: "default",
+			UID:       "7e0d4b21-3c5f-4f0b-9a2e-1d3b6c8f5a42",
+		},
+		Spec: operatorv1beta1.RecordingTriggerSpec{
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": "test-app",
+				},
+			},
+			TerminationReasons: []string{"OOMKilled"},
+			WaitingReasons:     []string{"CrashLoopBackOff"},
+			RecordingTemplate: operatorv1beta1.RecordingTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": "crash-profile",
+					},
+				},
+				Spec: NewRecording().Spec,
+			},
+		},
+	}
+}
+
+// NewObservedRecordingTrigger returns NewRecordingTrigger after it has seen
+// test-pod with the given restart count
+func NewObservedRecordingTrigger(restartCount int32) *operatorv1beta1.RecordingTrigger {
+	trigger := NewRecordingTrigger()
+	trigger.Status.Pods = []operatorv1beta1.RecordingTriggerPodStatus{
+		{
+			Pod:          "test-pod",
+			RestartCount: restartCount,
+		},
+	}
+	return trigger
+}
+
+// NewTriggerTime returns the time used by RecordingTrigger tests
+func NewTriggerTime() time.Time {
+	return time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC)
+}
+
+// NewTriggeredRecording returns the Recording created by NewRecordingTrigger
+// for test-pod at the time given by NewTriggerTime
+func NewTriggeredRecording(reason string) *operatorv1beta1.Recording {
+	trigger := NewRecordingTrigger()
+	suffix := strconv.FormatInt(NewTriggerTime().Unix()/60, 10)
+	rec := NewRecording()
+	rec.Name = trigger.Name + "-test-pod-" + suffix
+	rec.Spec.Name = rec.Spec.Name + "-" + suffix
+	ttl := int32(86400)
+	rec.Spec.TTLSecondsAfterFinished = &ttl
+	rec.Labels = map[string]string{
+		"app":                                 "crash-profile",
+		operatorv1beta1.RecordingTriggerLabel: trigger.Name,
+	}
+	rec.Annotations = map[string]string{
+		operatorv1beta1.RecordingTriggerReasonAnnotation: reason,
+	}
+	controller := true
+	rec.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion:         operatorv1beta1.GroupVersion.String(),
+			Kind:               "RecordingTrigger",
+			Name:               trigger.Name,
+			UID:                trigger.UID,
+			Controller:         &controller,
+			BlockOwnerDeletion: &controller,
+		},
+	}
+	return rec
+}
+
+// NewAlertPayload returns an Alertmanager webhook payload containing a single
+// alert with the given status, for the given pod in the default namespace
+func NewAlertPayload(status string, pod string, fingerprint string) string {
+	return fmt.Sprintf(`{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"HighLatency\"}",
+  "status": "%s",
+  "receiver": "cryostat",
+  "groupLabels": {"alertname": "HighLatency"},
+  "commonLabels": {"alertname": "HighLatency", "namespace": "default"},
+  "commonAnnotations": {},
+  "externalURL": "http://alertmanager:9093",
+  "alerts": [
+    {
+      "status": "%s",
+      "labels": {"alertname": "HighLatency", "namespace": "default", "pod": "%s"},
+      "annotations": {"summary": "Request latency is high"},
+      "startsAt": "2021-05-01T12:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph",
+      "fingerprint": "%s"
+    }
+  ]
+}`, status, status, pod, fingerprint)
+}
+
+// NewAlertRecordingName returns the name of the Recording created for the
+// alert in NewAlertPayload, which started firing at 2021-05-01T12:00:00Z
+func NewAlertRecordingName(fingerprint string) string {
+	return "alert-" + fingerprint + "-1619870400"
+}
+
+// NewAlertRecording returns the Recording created for the alert in
+// NewAlertPayload on test-pod
+func NewAlertRecording(fingerprint string) *operatorv1beta1.Recording {
+	ttl := int32(86400)
+	return &operatorv1beta1.Recording{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NewAlertRecordingName(fingerprint),
+			Namespace: "default",
+			Labels: map[string]string{
+				operatorv1beta1.AlertFingerprintLabel: fingerprint,
+			},
+			Annotations: map[string]string{
+				operatorv1beta1.AlertNameAnnotation: "HighLatency",
+			},
+		},
+		Spec: operatorv1beta1.RecordingSpec{
+			Name: NewAlertRecordingName(fingerprint),
+			Template: &operatorv1beta1.TemplateReference{
+				Name: "Profiling",
+				Type: operatorv1beta1.TemplateTypeTarget,
+			},
+			Duration:                metav1.Duration{Duration: 5 * time.Minute},
+			Archive:                 true,
+			TTLSecondsAfterFinished: &ttl,
+			FlightRecorder:          &corev1.LocalObjectReference{Name: "test-pod"},
+		},
+	}
+}
+
+func NewAdoptedRecording() *operatorv1beta1.Recording {
+	return &operatorv1beta1.Recording{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod-test-recording",
+			Namespace: "default",
+			Labels: map[string]string{
+				operatorv1beta1.RecordingLabel: "test-pod",
+			},
+			Annotations: map[string]string{
+				operatorv1beta1.RecordingAdoptedAnnotation: "true",
+			},
+		},
+		Spec: operatorv1beta1.RecordingSpec{
+			Name:           "test-recording",
+			Duration:       metav1.Duration{Duration: 30 * time.Second},
+			FlightRecorder: &corev1.LocalObjectReference{Name: "test-pod"},
+		},
+	}
+}
+
 func getDuration(continuous bool) time.Duration {
 	seconds := 0
 	if !continuous {
@@ -399,6 +1143,128 @@ func NewTargetPod() *corev1.Pod {
 	}
 }
 
+// NewRestartedTargetPod returns the target pod after its container has
+// restarted the given number of times
+func NewRestartedTargetPod(restartCount int32) *corev1.Pod {
+	pod := NewTargetPod()
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:         "test-container",
+			RestartCount: restartCount,
+		},
+	}
+	return pod
+}
+
+// NewTerminatingTargetPod returns the target pod once its deletion has been
+// requested, at the time given by NewPodTerminationTime
+func NewTerminatingTargetPod() *corev1.Pod {
+	pod := NewTargetPod()
+	setPodTerminating(pod)
+	return pod
+}
+
+func NewTerminatingWorkloadTargetPod() *corev1.Pod {
+	pod := NewWorkloadTargetPod()
+	setPodTerminating(pod)
+	return pod
+}
+
+// NewPodTerminationTime returns when the deletion of terminating pods was
+// requested, one hour into their recordings
+func NewPodTerminationTime() time.Time {
+	return time.Unix(0, 1597090030341*int64(time.Millisecond)).Add(time.Hour)
+}
+
+func setPodTerminating(pod *corev1.Pod) {
+	grace := int64(30)
+	delTime := metav1.NewTime(NewPodTerminationTime().Add(time.Duration(grace) * time.Second))
+	pod.DeletionTimestamp = &delTime
+	pod.DeletionGracePeriodSeconds = &grace
+}
+
+func NewWorkloadTargetPod() *corev1.Pod {
+	pod := NewTargetPod()
+	pod.Labels = map[string]string{
+		"app": "test-app",
+	}
+	return pod
+}
+
+// NewRunningWorkloadTargetPod returns NewWorkloadTargetPod with its container
+// ready after restarting the given number of times
+func NewRunningWorkloadTargetPod(restartCount int32) *corev1.Pod {
+	pod := NewWorkloadTargetPod()
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:         "test-container",
+			Ready:        true,
+			RestartCount: restartCount,
+			State: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{},
+			},
+		},
+	}
+	return pod
+}
+
+// NewRestartedWorkloadTargetPod returns NewWorkloadTargetPod after its container
+// restarted once, having terminated for the given reason
+func NewRestartedWorkloadTargetPod(reason string, ready bool) *corev1.Pod {
+	pod := NewRunningWorkloadTargetPod(1)
+	container := &pod.Status.ContainerStatuses[0]
+	container.Ready = ready
+	container.LastTerminationState = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{
+			Reason:   reason,
+			ExitCode: 137,
+		},
+	}
+	return pod
+}
+
+// NewCrashLoopWorkloadTargetPod returns NewWorkloadTargetPod with its container
+// waiting to be restarted after repeated failures
+func NewCrashLoopWorkloadTargetPod() *corev1.Pod {
+	pod := NewRestartedWorkloadTargetPod("Error", false)
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{
+			Reason: "CrashLoopBackOff",
+		},
+	}
+	return pod
+}
+
+// NewPendingWorkloadTargetPod returns a pod in the same workload as
+// NewWorkloadTargetPod that does not have a FlightRecorder yet
+func NewPendingWorkloadTargetPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod-2",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app": "test-app",
+			},
+		},
+	}
+}
+
+func NewTargetDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-app",
+			Namespace: "default",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": "test-app",
+				},
+			},
+		},
+	}
+}
+
 func NewCryostatPod() *corev1.Pod {
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{